@@ -72,6 +72,8 @@ func DumpAndSummaryFileResult(results []FileCheckResult) LogOutputs {
 
 // ProcessFiles checks file existing test, upcate contents and so on.
 func ProcessFiles(config *Config, cwd string, envs *EnvVar) (results []FileCheckResult) {
+	// written files are owned by process.user to be readable/writable from the command
+	owner, ownerErr := lookupCredential(config.Process.User, config.Process.Group)
 	for _, rule := range config.Files {
 		files, err := SearchFiles(rule.Name, cwd)
 		from := found
@@ -148,7 +150,15 @@ func ProcessFiles(config *Config, cwd string, envs *EnvVar) (results []FileCheck
 						dir = filepath.Dir(dest)
 						result.dest = dest
 					}
-					os.MkdirAll(dir, 0755)
+					if err := mkdirAllAs(dir, owner); err != nil {
+						result.error = fmt.Errorf("can't create directory '%s': %w", dir, err)
+						results = append(results, result)
+						continue
+					}
+				} else if err != nil {
+					result.error = fmt.Errorf("can't check destination '%s': %w", dest, err)
+					results = append(results, result)
+					continue
 				} else if stat.IsDir() {
 					result.dest = filepath.Join(dest, srcFileName)
 				} else {
@@ -200,6 +210,11 @@ func ProcessFiles(config *Config, cwd string, envs *EnvVar) (results []FileCheck
 				}
 				srcFile.Close()
 				destFile.Close()
+				if ownerErr != nil {
+					result.error = ownerErr
+				} else if err := owner.chown(result.dest); err != nil && result.error == nil {
+					result.error = fmt.Errorf("can't change owner of file '%s': %w", result.dest, err)
+				}
 			} else { // no move and no rewrite
 				// todo: existing check
 			}
@@ -208,3 +223,30 @@ func ProcessFiles(config *Config, cwd string, envs *EnvVar) (results []FileCheck
 	}
 	return
 }
+
+// mkdirAllAs creates the directory and its parents like os.MkdirAll, and changes the owner of created ones
+//
+// The command running as process.user can create files next to the written files.
+func mkdirAllAs(dir string, owner *credential) error {
+	var created []string
+	for current := filepath.Clean(dir); ; current = filepath.Dir(current) {
+		if _, err := os.Stat(current); err == nil {
+			break
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		created = append(created, current)
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, path := range created {
+		if err := owner.chown(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -315,3 +316,40 @@ func TestCheckFile(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessFiles_CreateDirectory(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "cradle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dirPath, "src.txt"), []byte("hello"), 0644))
+
+	config := &Config{Files: []File{
+		{Name: "src.txt", MoveTo: filepath.Join(dirPath, "conf", "app", "dest.txt")},
+		// parent is not a directory
+		{Name: "src.txt", MoveTo: filepath.Join(dirPath, "src.txt", "dest.txt")},
+	}}
+	if os.Getuid() == 0 {
+		config.Process.User = "65534"
+		config.Process.Group = "65534"
+	}
+	results := ProcessFiles(config, dirPath, NewEnvVar())
+	assert.Len(t, results, 2)
+	assert.NoError(t, results[0].error)
+	content, err := ioutil.ReadFile(filepath.Join(dirPath, "conf", "app", "dest.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+	assert.Error(t, results[1].error)
+	assert.Error(t, mkdirAllAs(filepath.Join(dirPath, "src.txt", "dest"), nil))
+
+	if os.Getuid() == 0 {
+		// created directories are owned by process.user
+		for _, path := range []string{"conf", "conf/app", "conf/app/dest.txt"} {
+			stat, err := os.Stat(filepath.Join(dirPath, path))
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(65534), stat.Sys().(*syscall.Stat_t).Uid, path)
+		}
+		stat, err := os.Stat(dirPath)
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(0), stat.Sys().(*syscall.Stat_t).Uid)
+	}
+}
@@ -0,0 +1,41 @@
+package docradle
+
+import (
+	"os/exec"
+	"sync"
+)
+
+// managedChildren are child processes that are waited by os/exec. The reaper doesn't reap them.
+var managedChildren = struct {
+	sync.Mutex
+	pids map[int]bool
+}{
+	pids: make(map[int]bool),
+}
+
+// startCommand starts the command and protects it from the zombie reaper until releaseCommand is called
+func startCommand(cmd *exec.Cmd) error {
+	managedChildren.Lock()
+	defer managedChildren.Unlock()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	managedChildren.pids[cmd.Process.Pid] = true
+	return nil
+}
+
+// releaseCommand should be called after cmd.Wait() returns
+func releaseCommand(cmd *exec.Cmd) {
+	managedChildren.Lock()
+	defer managedChildren.Unlock()
+	delete(managedChildren.pids, cmd.Process.Pid)
+}
+
+// runCommand is the same as cmd.Run() but works with the zombie reaper
+func runCommand(cmd *exec.Cmd) error {
+	if err := startCommand(cmd); err != nil {
+		return err
+	}
+	defer releaseCommand(cmd)
+	return cmd.Wait()
+}
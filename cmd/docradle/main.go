@@ -14,13 +14,20 @@ var (
 	configFlag  = runCommand.Flag("config", "Config filename").Default(`docradle.cue,docradle.json,docradle.yaml,docradle.yml`).Short('c').String()
 	dryRunFlag  = runCommand.Flag("dryrun", "Check EnvVar/Files only").Short('d').Bool()
 	dotEnvFlag  = runCommand.Flag("dotenv", ".env filename").Short('e').Default(".env").String()
-	command     = runCommand.Arg("command", "Command name to run").Required().String()
+	skipDeps    = runCommand.Flag("skip-deps", "Skip waiting for dependencies").Bool()
+	userFlag    = runCommand.Flag("user", "User (and group) to run the command as like 'app:app' (overrides process.user)").String()
+	command     = runCommand.Arg("command", "Command name to run (optional if processes exist in config file)").String()
 	args        = runCommand.Arg("args", "Arguments").Strings()
 	initCommand = kingpin.Command("init", "Generate config file")
 	format      = initCommand.Flag("format", "Config file format").Short('f').Default("json").Enum("cue", "json", "yaml")
+	waitCommand = kingpin.Command("wait", "Wait for dependencies without config file")
+	timeout     = waitCommand.Flag("timeout", "Timeout of each dependency").Short('t').Default("10s").Duration()
+	interval    = waitCommand.Flag("interval", "Interval of checks").Short('i').Default("1s").Duration()
+	urls        = waitCommand.Arg("urls", "URLs of dependencies like tcp://db:5432").Required().Strings()
 )
 
 func main() {
+	docradle.RunResourceHelper()
 	color.IsSupportColor()
 	switch kingpin.Parse() {
 	case runCommand.FullCommand():
@@ -29,18 +36,32 @@ func main() {
 			color.Fprintf(os.Stderr, "<red>Cannot get current folder: %v</>\n", err)
 			os.Exit(1)
 		}
-		config, envvar, err := docradle.ParseAndVerifyConfig(wd, os.Stdout, os.Stderr, *configFlag, *dotEnvFlag)
+		config, envvar, err := docradle.ParseAndVerifyConfig(wd, os.Stdout, os.Stderr, *configFlag, *dotEnvFlag, *userFlag, *skipDeps)
 		if err != nil {
+			os.Exit(docradle.ExitCode(err))
+		}
+		if *command == "" && len(config.Processes) == 0 {
+			color.Fprintln(os.Stderr, "<red>command is required if config file doesn't have processes</>")
 			os.Exit(1)
 		}
-		docradle.DumpCommand(os.Stdout, *command, *args, *dryRunFlag)
+		for _, process := range config.Processes {
+			docradle.DumpCommand(os.Stdout, process.Command[0], process.Command[1:], *dryRunFlag)
+		}
+		if *command != "" {
+			docradle.DumpCommand(os.Stdout, *command, *args, *dryRunFlag)
+		}
 		if !(*dryRunFlag) {
 			err = docradle.Exec(os.Stdout, os.Stderr, config, *command, *args, envvar)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Fails to run command: %v", err)
-				os.Exit(1)
+				os.Exit(docradle.ExitCode(err))
 			}
 		}
+	case waitCommand.FullCommand():
+		err := docradle.Wait(os.Stdout, os.Stderr, *urls, *timeout, *interval)
+		if err != nil {
+			os.Exit(docradle.ExitCode(err))
+		}
 	case initCommand.FullCommand():
 		err := docradle.Generate(os.Stdout, *format)
 		if err != nil {
@@ -1,14 +1,16 @@
 package docradle
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"cuelang.org/go/cue"
@@ -81,23 +83,41 @@ func ReadConfig(filePath string, reader io.Reader) (*Config, error) {
 		Env:           config.Env,
 		DashboardPort: config.DashboardPort,
 		DelvePort:     config.DelvePort,
-		Process:       config.Process,
 		HealthCheck:   config.HealthCheck,
 		LogLevel:      config.LogLevel,
+
+		DependsOnTimeout: time.Duration(config.DependsOnTimeout * float64(time.Second)),
+		DependencyMonitor: DependencyMonitor{
+			Interval:    time.Duration(config.DependencyMonitor.Interval * float64(time.Second)),
+			Threshold:   time.Duration(config.DependencyMonitor.Threshold * float64(time.Second)),
+			Policy:      config.DependencyMonitor.Policy,
+			GracePeriod: time.Duration(config.DependencyMonitor.GracePeriod * float64(time.Second)),
+		},
+	}
+	process, err := encodeProcess(config.Process)
+	if err != nil {
+		return nil, err
+	}
+	result.Process = process
+	hooks, err := encodeHooks(config.Hooks)
+	if err != nil {
+		return nil, fmt.Errorf("hooks' %w", err)
 	}
+	result.Hooks = hooks
+
 	files, err := encodeFiles(merged.Value().Lookup("file"), codec)
 	if err != nil {
 		return nil, fmt.Errorf("Internal error at file parsing: %w", err)
 	}
 	result.Files = files
 
-	stdout, err := encodeLog(merged, "stdout", config.Stdout, codec)
+	stdout, err := encodeLog(merged.Value().Lookup("stdout"), config.Stdout, codec)
 	if err != nil {
 		return nil, fmt.Errorf("Internal error at stdout parsing: %w", err)
 	}
 	result.Stdout = stdout
 
-	stderr, err := encodeLog(merged, "stderr", config.Stderr, codec)
+	stderr, err := encodeLog(merged.Value().Lookup("stderr"), config.Stderr, codec)
 	if err != nil {
 		return nil, fmt.Errorf("Internal error at stderr parsing: %w", err)
 	}
@@ -108,13 +128,21 @@ func ReadConfig(filePath string, reader io.Reader) (*Config, error) {
 		return nil, fmt.Errorf("Internal error at dependsOn parsing: %w", err)
 	}
 	result.DependsOn = dependsOn
+
+	processes, err := encodeProcesses(merged.Value().Lookup("processes"), codec)
+	if err != nil {
+		return nil, err
+	}
+	result.Processes = processes
 	return &result, nil
 }
 
 // ParseAndVerifyConfig reads and verify configs
 //
-// It dumps config status and error message to stdout, stderr
-func ParseAndVerifyConfig(workingDir string, stdout, stderr io.Writer, configFlag, dotEnvFlag string) (*Config, *EnvVar, error) {
+// It dumps config status and error message to stdout, stderr.
+// If skipDependencies is true, it doesn't wait for dependencies.
+// userFlag like "app:app" overrides process.user and process.group. Files are written with the ownership of the user.
+func ParseAndVerifyConfig(workingDir string, stdout, stderr io.Writer, configFlag, dotEnvFlag, userFlag string, skipDependencies bool) (*Config, *EnvVar, error) {
 	files, err := SearchFiles(configFlag, workingDir)
 	if err != nil {
 		color.Fprintf(stderr, "<red>config option pattern error %q\n</>\n", configFlag)
@@ -145,6 +173,15 @@ func ParseAndVerifyConfig(workingDir string, stdout, stderr io.Writer, configFla
 			panic(err)
 		}
 	}
+	if userFlag != "" {
+		config.Process.User, config.Process.Group = splitUserSpec(userFlag)
+	}
+	runAs, err := lookupCredential(config.Process.User, config.Process.Group)
+	if err != nil {
+		color.Fprintf(stderr, "\n<red>Cannot run as the user:</>\n")
+		color.Fprintf(stderr, "  <red>%s</>\n", err.Error())
+		return nil, nil, err
+	}
 	outputs := make(map[string]LogOutputs)
 	var dotEnvs []string
 	dotEnvPath := filepath.Join(workingDir, dotEnvFlag)
@@ -162,9 +199,13 @@ func ParseAndVerifyConfig(workingDir string, stdout, stderr io.Writer, configFla
 	if len(config.Files) > 0 {
 		checkFileResults := ProcessFiles(config, workingDir, envvars)
 		outputs["file"] = DumpAndSummaryFileResult(checkFileResults)
-
-		// todo: handle signal
-		checkDependencyResult := WaitForDependencies(context.TODO(), config.DependsOn)
+	}
+	if len(config.DependsOn) > 0 && !skipDependencies {
+		checkDependencyResult, sig := waitForDependenciesWithSignal(dependsOnAs(config.DependsOn, runAs), config.DependsOnTimeout, envvars, stdout)
+		if sig != nil {
+			color.Fprintf(stderr, "\n<yellow>Waiting for dependencies is canceled by signal: %s</>\n", sig)
+			return nil, nil, &CanceledError{Signal: sig}
+		}
 		outputs["dependency"] = DumpAndSummaryDependsOnResult(checkDependencyResult)
 	}
 	showErrorOnly := outputs["env"].HasError() || outputs["file"].HasError() || outputs["dependency"].HasError()
@@ -183,17 +224,115 @@ func ParseAndVerifyConfig(workingDir string, stdout, stderr io.Writer, configFla
 	}
 	if len(config.DependsOn) > 0 {
 		color.Fprintln(stdout, "<bg=black;fg=lightBlue;op=reverse;>  Dependencies  </>\n")
-		if outputs["dependency"].Dump(showErrorOnly) {
+		if skipDependencies {
+			color.Fprintln(stdout, "    <gray>Skipped by --skip-deps</>\n")
+		} else if outputs["dependency"].Dump(showErrorOnly) {
 			color.Fprintln(stdout, "    <fg=lightGreen;op=underscore,bold;>No Error</>\n")
 		}
 	}
 	if showErrorOnly {
 		color.Fprintln(stdout, "<fg=lightRed;op=underscore,bold;>Fail to run command due to configuration error.</>\n")
+		if !outputs["env"].HasError() && !outputs["file"].HasError() {
+			return nil, nil, fmt.Errorf("fail to run: %w", ErrDependencyNotReady)
+		}
 		return nil, nil, errors.New("fail to run")
 	}
 	return config, envvars, nil
 }
 
+func encodeProcess(p cueProcess) (Process, error) {
+	result := Process{
+		NoticeExitHTTP:   p.NoticeExitHTTP,
+		NoticeExitSlack:  p.NoticeExitSlack,
+		NoticeExitPubSub: p.NoticeExitPubSub,
+		Rerun:            p.Rerun,
+		LogBucket:        p.LogBucket,
+		LogKey:           p.LogKey,
+		StopTimeout:      time.Duration(p.StopTimeout * float64(time.Second)),
+		Restart:          p.Restart,
+		MaxRestarts:      p.MaxRestarts,
+		RestartBackoff:   encodeBackoff(p.RestartBackoff),
+		RestartWindow:    time.Duration(p.RestartWindow * float64(time.Second)),
+		User:             p.User,
+		Group:            p.Group,
+		Nice:             p.Nice,
+		OOMScoreAdj:      p.OOMScoreAdj,
+		Timeout:          time.Duration(p.Timeout * float64(time.Second)),
+		Watchdog:         time.Duration(p.Watchdog * float64(time.Second)),
+	}
+	// rerun is kept for compatibility
+	if p.Rerun && (result.Restart == "" || result.Restart == "no") {
+		result.Restart = "always"
+	}
+	if result.Restart == "" {
+		result.Restart = "no"
+	}
+	if result.RestartBackoff == nil {
+		result.RestartBackoff = &Backoff{
+			Initial:    defaultRestartDelay,
+			Max:        defaultMaxRestartDelay,
+			Multiplier: 2,
+			Jitter:     0.2,
+		}
+	} else {
+		if result.RestartBackoff.Initial == 0 {
+			result.RestartBackoff.Initial = defaultRestartDelay
+		}
+		if result.RestartBackoff.Max == 0 {
+			result.RestartBackoff.Max = defaultMaxRestartDelay
+		}
+	}
+	if p.StopSignal != "" {
+		sig, err := parseSignal(p.StopSignal)
+		if err != nil {
+			return result, fmt.Errorf("process's stopSignal is invalid: %w", err)
+		}
+		result.StopSignal = sig
+	}
+	if p.LogBucket != "" {
+		if _, err := parseLogKey(p.LogKey); err != nil {
+			return result, fmt.Errorf("process's logKey is invalid: %w", err)
+		}
+	}
+	limits, err := parseLimits(p.Limits)
+	if err != nil {
+		return result, fmt.Errorf("process's limits is invalid: %w", err)
+	}
+	result.Limits = limits
+	preStop, err := encodeHook("preStop", p.PreStop)
+	if err != nil {
+		return result, fmt.Errorf("process's %w", err)
+	}
+	result.PreStop = preStop
+	if len(p.SignalRewrite) > 0 {
+		result.SignalRewrite = make(map[syscall.Signal]syscall.Signal, len(p.SignalRewrite))
+		for from, to := range p.SignalRewrite {
+			fromSignal, err := parseSignal(from)
+			if err != nil {
+				return result, fmt.Errorf("process's signalRewrite is invalid: %w", err)
+			}
+			toSignal, err := parseSignal(to)
+			if err != nil {
+				return result, fmt.Errorf("process's signalRewrite is invalid: %w", err)
+			}
+			result.SignalRewrite[fromSignal] = toSignal
+		}
+	}
+	return result, nil
+}
+
+func encodeBackoff(b *cueBackoff) *Backoff {
+	if b == nil {
+		return nil
+	}
+	return &Backoff{
+		Initial:    time.Duration(b.Initial * float64(time.Second)),
+		Max:        time.Duration(b.Max * float64(time.Second)),
+		Multiplier: b.Multiplier,
+		Jitter:     b.Jitter,
+	}
+}
+
 func encodeFiles(fvalues cue.Value, codec *gocodec.Codec) (result []File, err error) {
 	files, err := toSlice(fvalues)
 	if err != nil {
@@ -224,6 +363,54 @@ func encodeFiles(fvalues cue.Value, codec *gocodec.Codec) (result []File, err er
 	return
 }
 
+func encodeProcesses(pvalues cue.Value, codec *gocodec.Codec) (result []ProcessEntry, err error) {
+	processes, err := toSlice(pvalues)
+	if err != nil {
+		return nil, fmt.Errorf("Internal error at processes parsing: %w", err)
+	}
+	names := make(map[string]bool)
+	for _, src := range processes {
+		var p cueProcessEntry
+		err = codec.Encode(src, &p)
+		if err != nil {
+			return nil, fmt.Errorf("Internal error at processes parsing: %w", err)
+		}
+		if len(p.Command) == 0 {
+			return nil, fmt.Errorf("processes '%s' should have command", p.Name)
+		}
+		if p.Name == mainProcessName {
+			return nil, fmt.Errorf("processes' name '%s' is reserved for the command", p.Name)
+		}
+		if names[p.Name] {
+			return nil, fmt.Errorf("processes' name '%s' is duplicated", p.Name)
+		}
+		names[p.Name] = true
+		entry := ProcessEntry{
+			Name:    p.Name,
+			Command: p.Command,
+			OnExit:  p.OnExit,
+		}
+		entry.Stdout, err = encodeLog(src.Lookup("stdout"), p.Stdout, codec)
+		if err != nil {
+			return nil, fmt.Errorf("Internal error at stdout parsing of processes '%s': %w", p.Name, err)
+		}
+		entry.Stderr, err = encodeLog(src.Lookup("stderr"), p.Stderr, codec)
+		if err != nil {
+			return nil, fmt.Errorf("Internal error at stderr parsing of processes '%s': %w", p.Name, err)
+		}
+		entry.Process, err = encodeProcess(p.Process)
+		if err != nil {
+			return nil, fmt.Errorf("processes '%s': %w", p.Name, err)
+		}
+		entry.DependsOn, err = encodeDependsOn(src.Lookup("dependsOn"), codec)
+		if err != nil {
+			return nil, fmt.Errorf("processes '%s': %w", p.Name, err)
+		}
+		result = append(result, entry)
+	}
+	return
+}
+
 func encodeRewrite(rsrc cue.Value, codec *gocodec.Codec) (result []Rewrite, err error) {
 	slice, err := toSlice(rsrc)
 	if err != nil {
@@ -240,7 +427,7 @@ func encodeRewrite(rsrc cue.Value, codec *gocodec.Codec) (result []Rewrite, err
 	return
 }
 
-func encodeLog(lvalues *cue.Instance, key string, parsed cueLog, codec *gocodec.Codec) (result LogConfig, err error) {
+func encodeLog(root cue.Value, parsed cueLog, codec *gocodec.Codec) (result LogConfig, err error) {
 	result = LogConfig{
 		Structured:   parsed.Structured,
 		DefaultLevel: parsed.DefaultLevel,
@@ -249,7 +436,6 @@ func encodeLog(lvalues *cue.Instance, key string, parsed cueLog, codec *gocodec.
 		PassThrough:  parsed.PassThrough,
 		Tags:         parsed.Tags,
 	}
-	root := lvalues.Value().Lookup(key)
 	if !root.Exists() {
 		return
 	}
@@ -274,33 +460,202 @@ func encodeDependsOn(lvalues cue.Value, codec *gocodec.Codec) (result []DependsO
 		return nil, err
 	}
 	for _, src := range logs {
-		var d cueDependsOn
-		err = codec.Encode(src, &d)
+		entry, err := encodeDependsOnEntry(src, codec)
 		if err != nil {
 			return nil, err
 		}
-		u, err := url.Parse(d.URL)
+		if len(entry.AnyOf) == 0 && entry.URL == nil {
+			return nil, errors.New("dependsOn should have url, command or anyOf")
+		}
+		result = append(result, entry)
+	}
+	if _, err := dependsOnDepths(result); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func encodeDependsOnEntry(src cue.Value, codec *gocodec.Codec) (entry DependsOn, err error) {
+	var d cueDependsOn
+	err = codec.Encode(src, &d)
+	if err != nil {
+		return entry, err
+	}
+	var u *url.URL
+	if d.URL != "" {
+		u, err = url.Parse(d.URL)
+		if err != nil {
+			return entry, fmt.Errorf("dependsOn's URL '%s' is invalid: %w", d.URL, err)
+		}
+	}
+	if len(d.Command) > 0 {
+		if u != nil {
+			return entry, fmt.Errorf("dependsOn '%s' should not have both url and command", d.URL)
+		}
+		u = commandURL(d.Command)
+	} else if u != nil && u.Scheme == "exec" && u.Host+u.Path == "" {
+		return entry, fmt.Errorf("dependsOn's URL '%s' doesn't have command", d.URL)
+	}
+	headers := make([][2]string, len(d.Headers))
+	for i, header := range d.Headers {
+		fragments := strings.SplitN(header, ":", 2)
+		headers[i] = [2]string{
+			fragments[0],
+			strings.TrimSpace(fragments[1]),
+		}
+	}
+	expectStatus, err := encodeHTTPStatus(src.Lookup("expectStatus"))
+	if err != nil {
+		return entry, fmt.Errorf("dependsOn's expectStatus of '%s' is invalid: %w", d.URL, err)
+	}
+	entry = DependsOn{
+		Name:           d.Name,
+		After:          d.After,
+		URL:            u,
+		Headers:        headers,
+		Timeout:        time.Duration(d.Timeout * float64(time.Second)),
+		Interval:       time.Duration(d.Interval * float64(time.Second)),
+		AttemptTimeout: time.Duration(d.AttemptTimeout * float64(time.Second)),
+		Method:         d.Method,
+		ExpectStatus:   expectStatus,
+		BodyContains:   d.BodyContains,
+		User:           d.User,
+		Password:       d.Password,
+		Query:          d.Query,
+		Command:        d.Command,
+		Resolver:       d.Resolver,
+		Send:           d.Send,
+		Expect:         d.Expect,
+		Contains:       d.Contains,
+		MinSize:        d.MinSize,
+		MaxAge:         time.Duration(d.MaxAge * float64(time.Second)),
+		NotExists:      d.NotExists,
+		Monitor:        d.Monitor,
+		Critical:       d.Critical,
+	}
+	if d.BodyRegexp != "" {
+		entry.BodyRegexp, err = regexp.Compile(d.BodyRegexp)
+		if err != nil {
+			return entry, fmt.Errorf("dependsOn's bodyRegexp of '%s' is invalid: %w", d.URL, err)
+		}
+	}
+	if d.Regexp != "" {
+		entry.Regexp, err = regexp.Compile(d.Regexp)
+		if err != nil {
+			return entry, fmt.Errorf("dependsOn's regexp of '%s' is invalid: %w", d.URL, err)
+		}
+	}
+	if d.NotExists && (d.Contains != "" || d.Regexp != "" || d.MinSize > 0 || d.MaxAge > 0) {
+		return entry, fmt.Errorf("dependsOn '%s' can't have notExists with other file conditions", d.URL)
+	}
+	if d.JSONPath != "" {
+		entry.JSONPath, err = ParseJSONPathAssertion(d.JSONPath)
+		if err != nil {
+			return entry, fmt.Errorf("dependsOn's jsonPath of '%s' is invalid: %w", d.URL, err)
+		}
+	}
+	entry.Backoff = encodeBackoff(d.Backoff)
+	if d.TLS != nil {
+		entry.TLS = &TLSConfig{
+			CA:                 d.TLS.CA,
+			Cert:               d.TLS.Cert,
+			Key:                d.TLS.Key,
+			ServerName:         d.TLS.ServerName,
+			InsecureSkipVerify: d.TLS.InsecureSkipVerify,
+			WarnExpiry:         time.Duration(d.TLS.WarnExpiry * float64(24*time.Hour)),
+		}
+	}
+	if entry.Method == "" {
+		// HEAD doesn't return body
+		if entry.BodyContains != "" || entry.BodyRegexp != nil || entry.JSONPath != nil {
+			entry.Method = "GET"
+		} else {
+			entry.Method = "HEAD"
+		}
+	}
+	alternatives, err := toSlice(src.Lookup("anyOf"))
+	if err != nil {
+		return entry, err
+	}
+	if len(alternatives) > 0 && u != nil {
+		return entry, fmt.Errorf("dependsOn '%s' should not have both url and anyOf", d.URL)
+	}
+	for _, alternativeSrc := range alternatives {
+		alternative, err := encodeDependsOnEntry(alternativeSrc, codec)
+		if err != nil {
+			return entry, err
+		}
+		if alternative.URL == nil {
+			return entry, errors.New("alternatives in anyOf should have url or command")
+		}
+		if alternative.Name != "" || len(alternative.After) > 0 || len(alternative.AnyOf) > 0 {
+			return entry, fmt.Errorf("alternative '%s' in anyOf should not have name, after and anyOf", alternative.URL)
+		}
+		entry.AnyOf = append(entry.AnyOf, alternative)
+	}
+	return entry, nil
+}
+
+// encodeHTTPStatus parses status code list like 200, "2xx", "200-204"
+func encodeHTTPStatus(v cue.Value) (result [][2]int, err error) {
+	var values []cue.Value
+	if v.Kind() == cue.ListKind {
+		values, err = toSlice(v)
 		if err != nil {
-			return nil, fmt.Errorf("dependsOn's URL '%s' is invalid: %w", d.URL, err)
-		}
-		headers := make([][2]string, len(d.Headers))
-		for i, header := range d.Headers {
-			fragments := strings.SplitN(header, ":", 2)
-			headers[i] = [2]string{
-				fragments[0],
-				strings.TrimSpace(fragments[1]),
+			return nil, err
+		}
+	} else if v.Exists() {
+		values = append(values, v)
+	}
+	for _, value := range values {
+		switch value.Kind() {
+		case cue.IntKind:
+			status, err := value.Int64()
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, [2]int{int(status), int(status)})
+		case cue.StringKind:
+			src, err := value.String()
+			if err != nil {
+				return nil, err
+			}
+			statusRange, err := parseHTTPStatusRange(src)
+			if err != nil {
+				return nil, err
 			}
+			result = append(result, statusRange)
+		default:
+			return nil, fmt.Errorf("status should be number or string, but %v", value)
 		}
-		result = append(result, DependsOn{
-			URL:      u,
-			Headers:  headers,
-			Timeout:  time.Duration(d.Timeout * float64(time.Second)),
-			Interval: time.Duration(d.Interval * float64(time.Second)),
-		})
 	}
 	return
 }
 
+func parseHTTPStatusRange(src string) ([2]int, error) {
+	src = strings.TrimSpace(src)
+	if fragments := strings.SplitN(src, "-", 2); len(fragments) == 2 {
+		from, err1 := strconv.Atoi(strings.TrimSpace(fragments[0]))
+		to, err2 := strconv.Atoi(strings.TrimSpace(fragments[1]))
+		if err1 != nil || err2 != nil || from > to {
+			return [2]int{}, fmt.Errorf("invalid status range '%s'", src)
+		}
+		return [2]int{from, to}, nil
+	}
+	if strings.HasSuffix(strings.ToLower(src), "xx") {
+		base, err := strconv.Atoi(src[:len(src)-2])
+		if err != nil {
+			return [2]int{}, fmt.Errorf("invalid status pattern '%s'", src)
+		}
+		return [2]int{base * 100, base*100 + 99}, nil
+	}
+	status, err := strconv.Atoi(src)
+	if err != nil {
+		return [2]int{}, fmt.Errorf("invalid status '%s'", src)
+	}
+	return [2]int{status, status}, nil
+}
+
 func toSlice(v cue.Value) (result []cue.Value, err error) {
 	switch v.Kind() {
 	case cue.ListKind:
@@ -327,20 +682,29 @@ type Config struct {
 	Files         []File
 	DependsOn     []DependsOn
 	Process       Process
+	Hooks         Hooks
+	Processes     []ProcessEntry
 	HealthCheck   HealthCheck
 	LogLevel      string
+
+	DependsOnTimeout  time.Duration
+	DependencyMonitor DependencyMonitor
 }
 
 type cueConfig struct {
 	Env           []Env       `json:"env"`
 	DashboardPort int         `json:"dashboardPort"`
 	DelvePort     int         `json:"delvePort"`
-	Process       Process     `json:"process"`
+	Process       cueProcess  `json:"process"`
+	Hooks         cueHooks    `json:"hooks"`
 	HealthCheck   HealthCheck `json:"healthCheck"`
 	Version       string      `json:"version"`
 	Stdout        cueLog      `json:"stdout"`
 	Stderr        cueLog      `json:"stderr"`
 	LogLevel      string      `json:"logLevel"`
+
+	DependsOnTimeout  float64              `json:"dependsOnTimeout"`
+	DependencyMonitor cueDependencyMonitor `json:"dependencyMonitor"`
 }
 
 type Env struct {
@@ -391,25 +755,180 @@ type cueFile struct {
 }
 
 type DependsOn struct {
-	URL      *url.URL
-	Headers  [][2]string
-	Timeout  time.Duration
-	Interval time.Duration
+	Name           string
+	After          []string
+	AnyOf          []DependsOn
+	URL            *url.URL
+	Headers        [][2]string
+	Timeout        time.Duration
+	Interval       time.Duration
+	AttemptTimeout time.Duration
+	Backoff        *Backoff
+	Method         string
+	ExpectStatus   [][2]int
+	BodyContains   string
+	BodyRegexp     *regexp.Regexp
+	JSONPath       *JSONPathAssertion
+	TLS            *TLSConfig
+	User           string
+	Password       string
+	Query          string
+	Command        []string
+	Resolver       string
+	Send           string
+	Expect         string
+	Contains       string
+	Regexp         *regexp.Regexp
+	MinSize        int64
+	MaxAge         time.Duration
+	NotExists      bool
+	Monitor        bool
+	Critical       bool
+	runAs          *credential // process.user to run exec check as
 }
 
 type cueDependsOn struct {
-	URL      string   `json:"url"`
-	Headers  []string `json:"headers"`
-	Timeout  float64  `json:"timeout"`
-	Interval float64  `json:"interval"`
+	Name           string      `json:"name"`
+	After          []string    `json:"after"`
+	URL            string      `json:"url"`
+	Headers        []string    `json:"headers"`
+	Timeout        float64     `json:"timeout"`
+	Interval       float64     `json:"interval"`
+	AttemptTimeout float64     `json:"attemptTimeout"`
+	Backoff        *cueBackoff `json:"backoff"`
+	Method         string      `json:"method"`
+	BodyContains   string      `json:"bodyContains"`
+	BodyRegexp     string      `json:"bodyRegexp"`
+	JSONPath       string      `json:"jsonPath"`
+	TLS            *cueTLS     `json:"tls"`
+	User           string      `json:"user"`
+	Password       string      `json:"password"`
+	Query          string      `json:"query"`
+	Command        []string    `json:"command"`
+	Resolver       string      `json:"resolver"`
+	Send           string      `json:"send"`
+	Expect         string      `json:"expect"`
+	Contains       string      `json:"contains"`
+	Regexp         string      `json:"regexp"`
+	MinSize        int64       `json:"minSize"`
+	MaxAge         float64     `json:"maxAge"`
+	NotExists      bool        `json:"notExists"`
+	Monitor        bool        `json:"monitor"`
+	Critical       bool        `json:"critical"`
+}
+
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	Jitter     float64
+}
+
+type cueBackoff struct {
+	Initial    float64 `json:"initial"`
+	Max        float64 `json:"max"`
+	Multiplier float64 `json:"multiplier"`
+	Jitter     float64 `json:"jitter"`
+}
+
+type TLSConfig struct {
+	CA                 string
+	Cert               string
+	Key                string
+	ServerName         string
+	InsecureSkipVerify bool
+	WarnExpiry         time.Duration
+}
+
+type cueTLS struct {
+	CA                 string  `json:"ca"`
+	Cert               string  `json:"cert"`
+	Key                string  `json:"key"`
+	ServerName         string  `json:"serverName"`
+	InsecureSkipVerify bool    `json:"insecureSkipVerify"`
+	WarnExpiry         float64 `json:"warnExpiry"`
+}
+
+type DependencyMonitor struct {
+	Interval    time.Duration
+	Threshold   time.Duration
+	Policy      string
+	GracePeriod time.Duration
+}
+
+type cueDependencyMonitor struct {
+	Interval    float64 `json:"interval"`
+	Threshold   float64 `json:"threshold"`
+	Policy      string  `json:"policy"`
+	GracePeriod float64 `json:"gracePeriod"`
 }
 
 type Process struct {
-	NoticeExitHTTP   string `json:"noticeExitHttp"`
-	NoticeExitSlack  string `json:"noticeExitSlack"`
-	NoticeExitPubSub string `json:"noticeExitPubSub"`
-	Rerun            bool   `json:"rerun"`
-	LogBucket        string `json:"logBucket"`
+	NoticeExitHTTP   string
+	NoticeExitSlack  string
+	NoticeExitPubSub string
+	Rerun            bool
+	LogBucket        string
+	LogKey           string
+	SignalRewrite    map[syscall.Signal]syscall.Signal
+	StopSignal       syscall.Signal
+	StopTimeout      time.Duration
+	PreStop          *Hook
+	Restart          string
+	MaxRestarts      int
+	RestartBackoff   *Backoff
+	RestartWindow    time.Duration
+	User             string
+	Group            string
+	Limits           []ResourceLimit
+	Nice             *int
+	OOMScoreAdj      *int
+	Timeout          time.Duration
+	Watchdog         time.Duration
+}
+
+type cueProcess struct {
+	NoticeExitHTTP   string                 `json:"noticeExitHttp"`
+	NoticeExitSlack  string                 `json:"noticeExitSlack"`
+	NoticeExitPubSub string                 `json:"noticeExitPubSub"`
+	Rerun            bool                   `json:"rerun"`
+	LogBucket        string                 `json:"logBucket"`
+	LogKey           string                 `json:"logKey"`
+	SignalRewrite    map[string]string      `json:"signalRewrite"`
+	StopSignal       string                 `json:"stopSignal"`
+	StopTimeout      float64                `json:"stopTimeout"`
+	PreStop          *cueHook               `json:"preStop"`
+	Restart          string                 `json:"restart"`
+	MaxRestarts      int                    `json:"maxRestarts"`
+	RestartBackoff   *cueBackoff            `json:"restartBackoff"`
+	RestartWindow    float64                `json:"restartWindow"`
+	User             string                 `json:"user"`
+	Group            string                 `json:"group"`
+	Limits           map[string]interface{} `json:"limits"`
+	Nice             *int                   `json:"nice"`
+	OOMScoreAdj      *int                   `json:"oomScoreAdj"`
+	Timeout          float64                `json:"timeout"`
+	Watchdog         float64                `json:"watchdog"`
+}
+
+// ProcessEntry is a command in processes section that docradle supervises with the main command
+type ProcessEntry struct {
+	Name      string
+	Command   []string
+	Stdout    LogConfig
+	Stderr    LogConfig
+	Process   Process
+	DependsOn []DependsOn
+	OnExit    string
+}
+
+type cueProcessEntry struct {
+	Name    string     `json:"name"`
+	Command []string   `json:"command"`
+	Stdout  cueLog     `json:"stdout"`
+	Stderr  cueLog     `json:"stderr"`
+	Process cueProcess `json:"process"`
+	OnExit  string     `json:"onExit"`
 }
 
 type HealthCheck struct {
@@ -1,10 +1,18 @@
 package docradle
 
 import (
+	"errors"
 	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/rs/xid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -88,6 +96,718 @@ func Test_ReadConfig(t *testing.T) {
 				assert.NoError(t, err)
 			},
 		},
+		{
+			name: "success: http conditions",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": [
+					    {
+					      "url": "http://localhost:8080/actuator/health",
+					      "expectStatus": [200, "3xx", "401-403"],
+					      "jsonPath": "$.status == \"UP\""
+					    },
+					    {
+					      "url": "http://localhost:8081/health"
+					    }
+					  ]
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				if config == nil {
+					return
+				}
+				assert.Equal(t, 2, len(config.DependsOn))
+				assert.Equal(t, "GET", config.DependsOn[0].Method)
+				assert.Equal(t, [][2]int{{200, 200}, {300, 399}, {401, 403}}, config.DependsOn[0].ExpectStatus)
+				assert.Equal(t, `$.status == "UP"`, config.DependsOn[0].JSONPath.String())
+				assert.Equal(t, "HEAD", config.DependsOn[1].Method)
+			},
+		},
+		{
+			name: "success: tls",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": {
+					    "url": "tls://vault:8200",
+					    "tls": {
+					      "ca": "${CERT_DIR}/ca.pem",
+					      "serverName": "vault.internal",
+					      "warnExpiry": 30
+					    }
+					  }
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				if config == nil {
+					return
+				}
+				assert.Equal(t, 1, len(config.DependsOn))
+				assert.Equal(t, "${CERT_DIR}/ca.pem", config.DependsOn[0].TLS.CA)
+				assert.Equal(t, "vault.internal", config.DependsOn[0].TLS.ServerName)
+				assert.Equal(t, false, config.DependsOn[0].TLS.InsecureSkipVerify)
+				assert.Equal(t, 30*24*time.Hour, config.DependsOn[0].TLS.WarnExpiry)
+			},
+		},
+		{
+			name: "success: database",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": {
+					    "url": "postgres://app@db:5432/app",
+					    "password": "${DB_PASSWORD}",
+					    "query": "SELECT 1"
+					  }
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				if config == nil {
+					return
+				}
+				assert.Equal(t, 1, len(config.DependsOn))
+				assert.Equal(t, "postgres", config.DependsOn[0].URL.Scheme)
+				assert.Equal(t, "${DB_PASSWORD}", config.DependsOn[0].Password)
+				assert.Equal(t, "SELECT 1", config.DependsOn[0].Query)
+			},
+		},
+		{
+			name: "success: after and anyOf",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": [
+					    {
+					      "name": "db",
+					      "after": ["vault"],
+					      "anyOf": [
+					        {"url": "postgres://primary:5432/app"},
+					        {"url": "postgres://replica:5432/app", "timeout": 3}
+					      ]
+					    },
+					    {
+					      "name": "vault",
+					      "url": "http://vault:8200/v1/sys/health"
+					    }
+					  ]
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				if config == nil {
+					return
+				}
+				assert.Equal(t, 2, len(config.DependsOn))
+				assert.Equal(t, "db", config.DependsOn[0].Name)
+				assert.Equal(t, []string{"vault"}, config.DependsOn[0].After)
+				assert.Nil(t, config.DependsOn[0].URL)
+				assert.Equal(t, 2, len(config.DependsOn[0].AnyOf))
+				assert.Equal(t, "replica:5432", config.DependsOn[0].AnyOf[1].URL.Host)
+				assert.Equal(t, 10*time.Second, config.DependsOn[0].AnyOf[0].Timeout)
+				assert.Equal(t, 3*time.Second, config.DependsOn[0].AnyOf[1].Timeout)
+			},
+		},
+		{
+			name: "success: backoff",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": {
+					    "url": "http://localhost:8080/health",
+					    "attemptTimeout": 2,
+					    "backoff": {
+					      "initial": 0.5,
+					      "max": 10
+					    }
+					  }
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				if config == nil {
+					return
+				}
+				assert.Equal(t, 1, len(config.DependsOn))
+				assert.Equal(t, 2*time.Second, config.DependsOn[0].AttemptTimeout)
+				assert.Equal(t, &Backoff{
+					Initial:    500 * time.Millisecond,
+					Max:        10 * time.Second,
+					Multiplier: 2,
+					Jitter:     0.2,
+				}, config.DependsOn[0].Backoff)
+			},
+		},
+		{
+			name: "success: monitor",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": {
+					    "url": "tcp://db:5432",
+					    "monitor": true,
+					    "critical": true
+					  },
+					  "dependencyMonitor": {
+					    "threshold": 60,
+					    "policy": "restart"
+					  }
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				if config == nil {
+					return
+				}
+				assert.Equal(t, 1, len(config.DependsOn))
+				assert.Equal(t, true, config.DependsOn[0].Monitor)
+				assert.Equal(t, true, config.DependsOn[0].Critical)
+				assert.Equal(t, DependencyMonitor{
+					Interval:    5 * time.Second,
+					Threshold:   60 * time.Second,
+					Policy:      "restart",
+					GracePeriod: 10 * time.Second,
+				}, config.DependencyMonitor)
+			},
+		},
+		{
+			name: "success: dependsOnTimeout",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": {"url": "tcp://db:5432"},
+					  "dependsOnTimeout": 60
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				if config == nil {
+					return
+				}
+				assert.Equal(t, 60*time.Second, config.DependsOnTimeout)
+			},
+		},
+		{
+			name: "error: unknown monitor policy",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependencyMonitor": {
+					    "policy": "ignore"
+					  }
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "success: command",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": [
+					    {"command": ["pg_isready", "-h", "${DB_HOST}"]},
+					    {"url": "exec:///opt/app/bin/check-migration"}
+					  ]
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				if config == nil {
+					return
+				}
+				assert.Equal(t, 2, len(config.DependsOn))
+				assert.Equal(t, []string{"pg_isready", "-h", "${DB_HOST}"}, config.DependsOn[0].Command)
+				assert.Equal(t, "exec://pg_isready -h ${DB_HOST}", config.DependsOn[0].URL.String())
+				assert.Equal(t, []string{"/opt/app/bin/check-migration"}, commandLine(config.DependsOn[1]))
+			},
+		},
+		{
+			name: "success: dns",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": {
+					    "url": "dns://db?type=A&min=2",
+					    "resolver": "${DNS_SERVER}"
+					  }
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				if config == nil {
+					return
+				}
+				assert.Equal(t, 1, len(config.DependsOn))
+				assert.Equal(t, "dns", config.DependsOn[0].URL.Scheme)
+				assert.Equal(t, "2", config.DependsOn[0].URL.Query().Get("min"))
+				assert.Equal(t, "${DNS_SERVER}", config.DependsOn[0].Resolver)
+			},
+		},
+		{
+			name: "success: message brokers",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": [
+					    {"url": "kafka://kafka1:9092,kafka2:9092?topic=logs"},
+					    {"url": "nats://nats:4222?stream=ORDERS"},
+					    {"url": "amqps://rabbitmq/production?queue=tasks"}
+					  ]
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				if config == nil {
+					return
+				}
+				assert.Equal(t, 3, len(config.DependsOn))
+				assert.Equal(t, "kafka1:9092,kafka2:9092", config.DependsOn[0].URL.Host)
+				assert.Equal(t, "nats", config.DependsOn[1].URL.Scheme)
+				assert.Equal(t, "tasks", config.DependsOn[2].URL.Query().Get("queue"))
+			},
+		},
+		{
+			name: "success: udp and websocket",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": [
+					    {"url": "udp://game:7777", "send": "ping", "expect": "pong"},
+					    {"url": "wss://push:8443/socket"}
+					  ]
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				if config == nil {
+					return
+				}
+				assert.Equal(t, 2, len(config.DependsOn))
+				assert.Equal(t, "ping", config.DependsOn[0].Send)
+				assert.Equal(t, "pong", config.DependsOn[0].Expect)
+				assert.Equal(t, "wss", config.DependsOn[1].URL.Scheme)
+			},
+		},
+		{
+			name: "success: file conditions",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": [
+					    {"url": "file:///var/run/batch/*.done", "contains": "READY", "regexp": "^status=ok", "minSize": 1, "maxAge": 60},
+					    {"url": "file:///var/run/app.lock", "notExists": true}
+					  ]
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				if config == nil {
+					return
+				}
+				assert.Equal(t, 2, len(config.DependsOn))
+				assert.Equal(t, "READY", config.DependsOn[0].Contains)
+				assert.Equal(t, "^status=ok", config.DependsOn[0].Regexp.String())
+				assert.Equal(t, int64(1), config.DependsOn[0].MinSize)
+				assert.Equal(t, time.Minute, config.DependsOn[0].MaxAge)
+				assert.True(t, config.DependsOn[1].NotExists)
+			},
+		},
+		{
+			name: "error: notExists with other file conditions",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": {"url": "file:///var/run/app.lock", "notExists": true, "contains": "READY"}
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "success: signal rewrite",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "process": {"signalRewrite": {"SIGTERM": "QUIT", "HUP": "USR1"}}
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				if config == nil {
+					return
+				}
+				assert.Equal(t, map[syscall.Signal]syscall.Signal{
+					syscall.SIGTERM: syscall.SIGQUIT,
+					syscall.SIGHUP:  syscall.SIGUSR1,
+				}, config.Process.SignalRewrite)
+			},
+		},
+		{
+			name: "success: stop settings",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "process": {
+					    "stopSignal": "QUIT",
+					    "stopTimeout": 30,
+					    "preStop": {"url": "http://localhost:8080/shutdown", "method": "POST"}
+					  }
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				if config == nil {
+					return
+				}
+				assert.Equal(t, syscall.SIGQUIT, config.Process.StopSignal)
+				assert.Equal(t, 30*time.Second, config.Process.StopTimeout)
+				assert.Equal(t, &Hook{
+					Name:    "preStop",
+					URL:     "http://localhost:8080/shutdown",
+					Method:  "POST",
+					Timeout: 10 * time.Second,
+				}, config.Process.PreStop)
+			},
+		},
+		{
+			name: "restart policy",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "process": {
+					    "restart": "on-failure",
+					    "maxRestarts": 5,
+					    "restartBackoff": {"initial": 0.5, "max": 10},
+					    "restartWindow": 30
+					  }
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "on-failure", config.Process.Restart)
+				assert.Equal(t, 5, config.Process.MaxRestarts)
+				assert.Equal(t, &Backoff{
+					Initial:    500 * time.Millisecond,
+					Max:        10 * time.Second,
+					Multiplier: 2,
+					Jitter:     0.2,
+				}, config.Process.RestartBackoff)
+				assert.Equal(t, 30*time.Second, config.Process.RestartWindow)
+			},
+		},
+		{
+			name: "restart policy: default max of restartBackoff",
+			args: args{
+				filePath: "config.json",
+				content:  `{"process": {"restart": "always", "restartBackoff": {"initial": 2}}}`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &Backoff{
+					Initial:    2 * time.Second,
+					Max:        time.Minute,
+					Multiplier: 2,
+					Jitter:     0.2,
+				}, config.Process.RestartBackoff)
+			},
+		},
+		{
+			name: "user and group",
+			args: args{
+				filePath: "config.json",
+				content:  `{"process": {"user": "app", "group": "1000"}}`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "app", config.Process.User)
+				assert.Equal(t, "1000", config.Process.Group)
+			},
+		},
+		{
+			name: "resource limits",
+			args: args{
+				filePath: "config.json",
+				content:  `{"process": {"limits": {"nofile": 65536, "core": "unlimited", "stack": "8388608:unlimited"}, "nice": 10, "oomScoreAdj": -500}}`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []ResourceLimit{
+					{Name: "nofile", Soft: 65536, Hard: 65536},
+					{Name: "core", Soft: unlimited, Hard: unlimited},
+					{Name: "stack", Soft: 8388608, Hard: unlimited},
+				}, config.Process.Limits)
+				assert.Equal(t, 10, *config.Process.Nice)
+				assert.Equal(t, -500, *config.Process.OOMScoreAdj)
+			},
+		},
+		{
+			name: "error: nice is out of range",
+			args: args{
+				filePath: "config.json",
+				content:  `{"process": {"nice": 20}}`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "timeout and watchdog",
+			args: args{
+				filePath: "config.json",
+				content:  `{"process": {"timeout": 3600, "watchdog": 0.5}}`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, time.Hour, config.Process.Timeout)
+				assert.Equal(t, 500*time.Millisecond, config.Process.Watchdog)
+			},
+		},
+		{
+			name: "restart policy: default",
+			args: args{
+				filePath: "config.json",
+				content:  `{}`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "no", config.Process.Restart)
+				assert.Equal(t, 0, config.Process.MaxRestarts)
+				assert.Equal(t, &Backoff{
+					Initial:    time.Second,
+					Max:        time.Minute,
+					Multiplier: 2,
+					Jitter:     0.2,
+				}, config.Process.RestartBackoff)
+				assert.Equal(t, time.Minute, config.Process.RestartWindow)
+			},
+		},
+		{
+			name: "restart policy: rerun is an alias of always",
+			args: args{
+				filePath: "config.json",
+				content:  `{"process": {"rerun": true}}`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "always", config.Process.Restart)
+			},
+		},
+		{
+			name: "error: unknown restart policy",
+			args: args{
+				filePath: "config.json",
+				content:  `{"process": {"restart": "sometimes"}}`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "log bucket",
+			args: args{
+				filePath: "config.json",
+				content:  `{"process": {"logBucket": "s3://logs"}}`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "s3://logs", config.Process.LogBucket)
+				assert.Equal(t, "{{.Hostname}}/{{.StartTime}}.log.gz", config.Process.LogKey)
+			},
+		},
+		{
+			name: "error: invalid logKey",
+			args: args{
+				filePath: "config.json",
+				content:  `{"process": {"logBucket": "s3://logs", "logKey": "{{.Hostname"}}`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "hooks",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "hooks": {
+					    "preStart": [{"name": "migrate", "command": ["./manage.py", "migrate"]}],
+					    "postExit": [{"url": "http://localhost:8080/exited", "method": "POST", "failurePolicy": "ignore"}]
+					  }
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, Hooks{
+					PreStart: []*Hook{
+						{Name: "migrate", Command: []string{"./manage.py", "migrate"}, Method: "GET", Timeout: time.Minute, FailurePolicy: "fail"},
+					},
+					PostExit: []*Hook{
+						{Name: "postExit#1", URL: "http://localhost:8080/exited", Method: "POST", Timeout: time.Minute, FailurePolicy: "ignore"},
+					},
+				}, config.Hooks)
+			},
+		},
+		{
+			name: "processes",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "processes": [
+					    {"name": "exporter", "command": ["node_exporter"], "stdout": {"structured": false}, "process": {"restart": "always"}, "onExit": "none"},
+					    {"name": "shipper", "command": ["fluent-bit"], "dependsOn": {"url": "tcp://fluentd:24224"}}
+					  ]
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.NoError(t, err)
+				if assert.Len(t, config.Processes, 2) {
+					exporter := config.Processes[0]
+					assert.Equal(t, "exporter", exporter.Name)
+					assert.Equal(t, []string{"node_exporter"}, exporter.Command)
+					assert.False(t, exporter.Stdout.Structured)
+					assert.Equal(t, "info", exporter.Stdout.DefaultLevel)
+					assert.Equal(t, "error", exporter.Stderr.DefaultLevel)
+					assert.Equal(t, "always", exporter.Process.Restart)
+					assert.Equal(t, "none", exporter.OnExit)
+					shipper := config.Processes[1]
+					assert.Equal(t, "stop", shipper.OnExit)
+					assert.Equal(t, "no", shipper.Process.Restart)
+					if assert.Len(t, shipper.DependsOn, 1) {
+						assert.Equal(t, "tcp://fluentd:24224", shipper.DependsOn[0].URL.String())
+					}
+				}
+			},
+		},
+		{
+			name: "error: duplicated process name",
+			args: args{
+				filePath: "config.json",
+				content:  `{"processes": [{"name": "app", "command": ["app"]}, {"name": "app", "command": ["app2"]}]}`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "error: reserved process name",
+			args: args{
+				filePath: "config.json",
+				content:  `{"processes": [{"name": "main", "command": ["app"]}]}`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), "reserved")
+				}
+			},
+		},
+		{
+			name: "error: unknown failurePolicy",
+			args: args{
+				filePath: "config.json",
+				content:  `{"hooks": {"preStart": [{"command": ["./migrate"], "failurePolicy": "retry"}]}}`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "error: preStop has both command and url",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "process": {"preStop": {"command": ["nginx", "-s", "quit"], "url": "http://localhost/shutdown"}}
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "error: unknown signal",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "process": {"signalRewrite": {"TERM": "SIGFOO"}}
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "error: both url and command",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": {"url": "tcp://db:5432", "command": ["pg_isready"]}
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "error: circular dependency",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": [
+					    {"name": "a", "after": ["b"], "url": "tcp://a:80"},
+					    {"name": "b", "after": ["a"], "url": "tcp://b:80"}
+					  ]
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.Error(t, err)
+				if err != nil {
+					assert.Contains(t, err.Error(), "circular dependency")
+				}
+			},
+		},
+		{
+			name: "error: no url",
+			args: args{
+				filePath: "config.json",
+				content: `{
+					  "dependsOn": [
+					    {"name": "a"}
+					  ]
+					}
+				`,
+			},
+			check: func(t *testing.T, config *Config, err error) {
+				assert.Error(t, err)
+			},
+		},
 		{
 			name: "error: json",
 			args: args{
@@ -140,3 +860,27 @@ func Test_ReadConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_ParseAndVerifyConfig_DependsOnOnly(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	dirPath := filepath.Join(os.TempDir(), "cradle"+xid.New().String())
+	os.MkdirAll(dirPath, 0755)
+	defer os.RemoveAll(dirPath)
+	ioutil.WriteFile(filepath.Join(dirPath, "docradle.json"), []byte(`{
+	  "dependsOn": {"url": "tcp://`+address+`", "timeout": 0.1, "interval": 0.02}
+	}`), 0644)
+
+	// config without file rules should wait for dependencies
+	_, _, err = ParseAndVerifyConfig(dirPath, ioutil.Discard, ioutil.Discard, "docradle.json", ".env", "", false)
+	assert.True(t, errors.Is(err, ErrDependencyNotReady))
+
+	config, _, err := ParseAndVerifyConfig(dirPath, ioutil.Discard, ioutil.Discard, "docradle.json", ".env", "", true)
+	assert.NoError(t, err)
+	if config != nil {
+		assert.Equal(t, 1, len(config.DependsOn))
+	}
+}
@@ -5,149 +5,789 @@ import (
 )
 
 var bundle_f1bcb9c9bc167c664d6b397fdd3de634 = []byte(
-	"PK\x03\x04\x14\x00\x08\x00\x00\x00\\\x1a4P\x00\x00\x00\x00\x00\x00\x00\x00" +
-		"\x00\x00\x00\x00\x10\x00\x09\x00json-schema.jsonUT\x05\x00\x01\x91\x9d$" +
-		"^\x1fe&\x00\x9c\x07\xb6{\x94\x1b\x06\xcdk\x13#\x0c4y\x96\xed\x9b\xdb\x9f" +
-		"\xaey\xd9\x1c)r\xfbbrB'\x19\x14i\xf7\xda\x0c\xbcYt\x14\xfa\xb6\x02\xc8K" +
-		"W\xc8\x8b\xc5f\xbb7\xb4\xc4\xef[\xd6\x88\xc1yl\x16\n\x89\xf2\xfb\xbb\xaa" +
-		"\x9b\x9c\x15*\xe7\xa9\xfeSSwY\x91\x83\xe6\x9d\xc3b\x93\x94\x08\x8dT\xcb" +
-		"\xaa\x93\xc8[\x86Nk\x0f\x1bT?\x90B8I1\xfa\xbf\xdc\x03\xc04:\x8au\xf5I\x00" +
-		"|4\xfa]\xe6\xef\x8f\x00\xd0\x959\x16:bY\xf7\x0b\xcb\xb9\xae\x18\xb9\x03" +
-		"&1\n\x12\x1av)D\x18\xcf\xfcM\x09~=\xca\xfbMn\xb8\xcb\x81\xbd\x80\xd5\xbb" +
-		"MY`\x0e\x9f\xee5\x02I\xac\xa9\xd8\xfaBk\xae\xb5P\xf2\xd6\xc6D\xf2\xcd|\x1a" +
-		"\x9b\xa1w`\xaaqZ\x15\x00\x00IU\xcb\xacU!;d\xc7Y\xa5\xf2\\\xb5@^\xa9n\xf5" +
-		"\x85\xa7W\x87\x94\xda\x1bbF\xa2EX\xcf\x08\xd4\xb2\x1drt\xba\x0b\xf3\xcb" +
-		"$\xe48m\xc2\xe0\xf3\xd4\xa6Z/(Q\xe3\xae_(ff1A6\xe7\x93>\xd5\x88<\x98W@g" +
-		"\xa0D\xe4\x8c\xa1\xf7\xc6\x13\x99\x11\xd02\xd7\xe0$\xdb\xfc\xed\xc5\xa2" +
-		"\"\xc5\xf9\x89\xc4r\xdbH\xca\xb2i9\x99H5\x1d\x9e'\xc5Iq\xd92Wh\xd54gj\x87" +
-		"a3\x8e\\\xc4)\xe4\x15\xc3&\xc0\x88\x9ba\xfab\xbe\x82\xb3\xf2D\xa2\x96\xc4" +
-		"\xfb0:M \xe1\x9e\xdc\x1cc\xa8\xf1\x95\x97\x0e\x18\x0d\x90\x88r\x04\xa9S" +
-		"%\x1c!\xe3gX&v\x1e\x87\xe4\x9b\x07>\x11\xd6\xb6\xd3\x96\xf7-\x0fs\"\x88" +
-		"\x1a\xa2\xee\xd5v\x01\x05\x80\x9d\x99\x90\xaa\xd4$O\xf6$s,\xab\x15\xc51" +
-		"\x0c\xaef=\xc0\xb7v\x0e\x92K\x0cD\xa9W\xdf`\xaf\x86\x7f\x11\xdb*\x92V\x1e" +
-		"\x97t\xd2\x091<\x0e\xe82\x90\xf0\xa7\xebx`[\xb77l\x12\xb7\x96X\xcaS\x8d" +
-		"\xd6i\xda\xe6\x84&\xc7\xdf\xbc\x18\xe4\x1f\x98d\xdd\x9cH\x9eVie\xc9p*\xf2" +
-		"\xf3G\xda\xd5\xe0\x87\xbf#r\x9d\x99$_\x90\xe0g\xb6\n\xbe\xc6.\x18\x97\xb1" +
-		" \x86\xac*\x92p\xe3\xd0B\xc6\x91pn\xe4\xa3\x90\xd4\x8aM\x823\xeci\x91\xe0" +
-		"\xfa\x19\xf7j\xf5\xc7K\xc2\x01t\xf9O\xf1z\xaf\xb4@\x8a%o\xef5\xdd|\xa5\xb7" +
-		"\xb9-\x0bLcm\xb5$q\xc2\xc98\xa2SJ\x943\x1f\xfaK\x92\xc5\xb5\xc9\x9cxl\xdb" +
-		"QV%\xd3\xb4\xb0\xfe\xca\xf5\xfe\xd8\x18\x03\x10Qu\x05V\\\xcc\xe0 U.\x05" +
-		"\x85\xcf-\x1a\x16\x9c49\x94*\x12\x8e \x1c)\x94\xa8\x83\xf2\xcc\xb1\x07\xea" +
-		"5\xc97\xc5\xa3\xcc\xe6\xcdlx\xe1\xbb\xfd\xb9\x00\xc9 -\xdc\xf1\n\x0d6G\xe7" +
-		"\xb4\xb58\xa14\x7f\xaa,\xb9\xc6J\xad\xc2\xc2\xb5O\x86\xe4\xc8!T\xe3\xe7" +
-		"\x15\x90\xa9l\xb5\xa7p<)\xa2\xeb\xdf\x1d\xfd\x03\x1d\x95\x94`\x88\xb6i\xe2" +
-		"\xd0\xfa&;O\x14[x5]i\xe5\x1a5\x1cj6M\xfc\x10t*\xe8\x98\x9c}\x83\x95 -@\x8a" +
-		"\xdfo\x1bR\xc4-\x97#r\xeaD F\xc1\xf1\xf8\xf7\x92Q3\n\xb5m\xaf\x88?\x83\x9a" +
-		"\xc6 \x15A\x89\xd8sn\x90whvQ'\x8e\x15\xee\xa1\xe0\xde*4\xc0\x10\x9b\x0f" +
-		"\xcc\xa7z\x8d\xf3v\x9c\x90\xc0z\x13\x0b\xb9 B~:-\x82'x\x04\xd9\xd3)\x81" +
-		"T\xd4t\x08=ea\xa3\x11\xb1\x08o\xbe(r\x00\xb8\x0e\xd9\x0e\x88\xaf\xb4\x1e" +
-		"=K\x1c\xf8\x9c\x81Fg\xd0\x19<\x9d\xc9\xcc\xe0\x0d\xd3;4?>\xb6?\x8d'\x85" +
-		"\x12U\xf2{\xfa?b\x8d\xab\xb3B\x97,\x8aX\x9f\xb2\x06~\x84\xc5\x13\xf0i\xcf" +
-		"\x1bx!&\x9a\xab\x04:\x06\xa1\xb1\xb9G\xcf\xca\xfad\x9f\xe4\xa4tCB\xdc\xa0" +
-		"`\xb8\xd6\x1a\xdf1\x0b\x10\xa2\x09\x01\x08\x0b%\xa1\x0f\x0e*\xd1\x15\xad" +
-		"pP\x8cz\xda\x8c\xc2Z_\x0b5\x06\x9a\xba\x0e\n\xe3`f\x03\x11\xe7gU\xfde\x86" +
-		"\xe3T\xef\x99J\x94\xaf\xfc\xf8KQ\x8e\xbb~\x1a\xf5\x8ad\x07\xa2\x9d\x05\xc9" +
-		"\x16'\x9a\x98\xfd\x9a\x06y)\xf1\x9d\xbe\x06\xaf\xbe\xde\x9c\xde_\x7f}\x8d" +
-		"\xde\xa9\xcc4\xfa\xff\x98]\xa7\x93\x9fk\xae<\x8d\xf9C}\xaa\xa6\xb4\xe2\xb8" +
-		"C\xeev\xfb\x9d\xd7\xd7\xd2\xd0\x8a\xc7-\x0b\xff7\x18\xddWZ\x99\xc5\x00o" +
-		"D;cd\xac\x01'\x88\x16\xcf\xa0\x7f\xb3\xa0\xc3\xda\x90\x9c\x84\x1e\xf4\xc6" +
-		"\xeaNh0\xc6\xada\x19?\x1e\x1d\xc2\xb5\xe9\xde\x85\xcb9+{\xd9W\x10\xe6\xf8" +
-		"\x8d(:\x99\xe8%\x8d\xdd\xa0\xd7\xef\xaf\x07qx2\x7f`^\x8b.\x09\xb7\xc88\xc4" +
-		"\xa2ZA\xc5\xd0n\x91[\xf0\x0e\xc1\x01\xf5\x90o\xcd\x1aA\x1d\xcf ~\x05%\xcf" +
-		"v\xb4O\xee\xde=.^\xe2\xda7\x91\x816\x9d!\xea\x1dVtc;\xe6\x04\x88M@\xec!" +
-		"\xd4(\xacVF\x845\xda\x8f*\x12\xe8+y\x1b\xa9\xed\x89\xe8\xb2\xffYv'\x16\x15" +
-		"\x19X\x94\xc9\xf7#\xf2\x8d\x1du\x93\xd3n\x06\x92\x9b\xee\xb0\xbb*t\x0d\x15" +
-		"\x08i\x8a\xd9\xec\x10\xd25\xc6\xd7&#cz\xd7\x19\xd9\x97v\xb8/n\xc5/-\xbd" +
-		"\x14\xa9\x9b\xdf\xbc.Y\xd8\x8e7\xb2\x15i\x16*\xc9\xc2\xa5X\xb2\x04\x0b\x97" +
-		"^AYm-\xb5\x92q\xa02N\xaeA\x9dv\xa9UD\xbb\x0c\xbc\xb6j\x9d\x8d\xbe\n\xdb" +
-		"\xe5r\xbcx\xbdcw\"\xbd\xe5\x1a\xd4e\x87Z\xaa\xcb0}t\xbfo>\xea\x14\x1a\xb4" +
-		"\x1e\xd1\xf1\x18\xbd\xadN\xb7\x1e\x01PK\x07\x08\x93\xa9\xe7\xddV\x05\x00" +
-		"\x00V\x05\x00\x00PK\x03\x04\x14\x00\x08\x00\x00\x00\xadi4P\x00\x00\x00\x00" +
-		"\x00\x00\x00\x00\x00\x00\x00\x00\x0b\x00\x09\x00sample.jsonUT\x05\x00\x01" +
-		"\xe6(%^\x1f\xb5\x06\x00\x9c\x07v\xace\x1a.9\x08\xe3-\xe6\xfb\xe96\xad\xff" +
-		"\x05\xf1;\xc47F&\xdfZ\x87 VK\xf1+\x05\x91d\xd1\xe9\n\x18\xab\xdfO\x03(#" +
-		"Y\xbb(\x9f\xbfG\x01*\x108\xb4/\xd7\xf2xV\xad\x92lm\xb5\xaeQ]j\x17\xf8\xba" +
-		"\x849\xe1\xd8\x0f@E\xd4\xbf\xa2@\xb7d\xfaN\x1a\xbc\xd4\xf1,\xa7<\x1c\x97" +
-		"t\xcf?!\xd0\xa0E\x95\x9f\xe8}\xb4K\x83\xfd\x8d\xf5P\xdbz\xe8mM\xb1\xdd\xd6" +
-		"\xa0\xdb\xaa~\x82\x8cL@\x86\x98\xf7\xeb\x80\xac\xe7\x87/\xd1!rO\x8d\xee" +
-		"\xf3\xcf\xa3\x16\x00\xf8UyC\"\xaa\x88\xea\xb7\xce\xf2\xc1\xfe\xc2\xe1|\xaa" +
-		"\x11\x07hy\xfcH$0MF\x00\x90\xeeS\x00\xdd3r\xd0J\xe9mPm\x07\xf0\x0c\xbaR" +
-		"P\xf7H\x1ch;\xa1\x01\xf4\x1d\xf4\xb0~\x0f\xb2k\xd3\x83 \x97\x88\xf2\x03" +
-		"\x03p\xe6\x1b\xa4t\x0d\xb1\x80\x12\xe7\xa2\xf9E\xc88?.\xbc\x13\xbe\xf8A" +
-		"`\x8e\x18\x1d\xf8s\xdf\xa6\x93e>\x92\xb7\xe4\xa0\xce\xf1m\x14\x1d\xea(\x16" +
-		"=\xaa\xf3B\xe1T\x17p^\xcfR\xa9\xa4a\x03\"\xd6\xb2\x99\x0f\x01\xe0\xc3\xb2" +
-		"W\xfc\xc7uo\xe9H\xf4Zr\xff7[.\xbd\x1bt\xae\x99z\xc6\xdd\xe5\xc0\xdc\xb9" +
-		"\x90\xc6\xfd.\x1e\x80\xa7\xcd\xf4vRM#ala\xa4\xdd\x0f\xa0\xc3a>\x8c\x9c_" +
-		"4\x00\xc0r\x9e\xde\x180\x8aH\xcc\x0b{\x06\xdd\xb1\x84mg\x13\xda}\x9e\xaf" +
-		"\x89\xf3\x97\x8a\xdb\xd3\x8b\xbb\xff\xc0):\xdd^\x7f@\xf7\x05\x06/F\xb9\xac" +
-		"\xf4T\x1b\x95\xbaQ\x9c\xebx\xf0X\xccd\x1f\x02\x0c^\"\xfb>\x81S\xb9\xdb\xb7" +
-		"\x15\xe6\x07\x9e\x82\xed\x02!}p\x8b\x8e\xa8\xb2xTw\xb6\xd6\xc4\x86\x05\"" +
-		"\xa9\xb0\x87\xcc\xa1\x11\x0b\xed\xa2\x88\xd1ABj{\xe9\xcd\xe5\xae\x1e]T\x86" +
-		"O\xdbf\xb9\xb5\xc3\x1e\x96#\x09d\xb1X\xb9\xce\xa0\x84\x1b\xbbVM\xef\x10" +
-		"\x9a'\xde\xfe\xf9k\xad+\x7fr\x8a\x98\xef\x1b0\xab\x01\xa6~N\xd3\xff\xc9" +
-		"i\xea\x91\xac\xff\xfb\xe2\x17n\xd1\x11\xd3=\xd2Yhq\xd8Q*fm\x06\x7f\xfe\xf7" +
-		"S\xbb\xbc\xc22\xd87&\x03PK\x07\x08\x07!\xc7\xfa!\x02\x00\x00!\x02\x00\x00" +
-		"PK\x03\x04\x14\x00\x08\x00\x00\x00xi4P\x00\x00\x00\x00\x00\x00\x00\x00\x00" +
-		"\x00\x00\x00\n\x00\x09\x00schema.cueUT\x05\x00\x01\x85(%^\x1f~\x0d \x8c" +
-		"\x93\xe5:\x9d=\xbf\xb7\xed\xdf\xfdlV\xf7\xa4\x06<\xc9\x17M%\x15\xb3/\xc8" +
-		" \x85&$\xc0\x13k\x1d\x90\x14\xbd\xd6\xff~\xbf\xfaK\xa4TB$T<\xd57;\xbb\xf7" +
-		"\x8bb\xa6\xef\"\xa2\xc9B\xe1t\xb1F$f\x96\xe1f\x17\xb1\xb3`LDn\x9c\x94\xd2" +
-		"\x984}\x17\xc6)Fa\x96C\xc1\x9a\x80\x9b(7i i%\x81J\x15\n'u\xde\x0f\xd3J)" +
-		"\xb5\xa9tU\xd7\xf1\xe8\xe3\xc7\xab_O\xb7\x99\x80t\\F\x83\xa0A\x13\xc0\xa2" +
-		"\x85\x00\xcc\"*\x11)\xdf\x8a\x0d\x90_3j\xbcz\x01u_\xc6\xd4\xe04\xeb\x82" +
-		"A\xa8\xb0u\x87\x040os\x89\x9b\xe1\xb9\xe0E\x1b#\x05-oI\xcd\xff?\xeej\x05" +
-		"\x00q\xd1W\\\xb0\x8c\x0b\xe6\xcb}\xf4\xc5&\xb3to\xdfv\xc5f\x09\x11!$R.p" +
-		"\xfa\xe7\x19\x9b\xbe\x8a\x9b)\xe1\x02\xd0:XlR\x1d\xc0)U\x8e\x9a\x97<q\xf3" +
-		"\xb4\xf8My\x9b\xc6\x10\xbfO1\x1e\x04\xab?<}\xf6\xfe\xeb\xabGo\xc5\x94\xb6" +
-		"DR\xe23\x17\x82\xbe\x0e\xc1\x17`n\xf0\x83\x11,\x84]\xab\xc3\x0b~\xcf\x15" +
-		"\xeb\xb7>\xbe$\xa6\xc8n\x07\x9b\x0c\x9fDMr\x0f\xf8\x04\xbd\x89\xf8*\xd1" +
-		"X\x1b\x91[b\x93\x05\xaa\x99RB6*\x9c\xca\x14s$\xbb\x18\x91F\xf1$.\x14\xc4" +
-		"=\xe5Z\x11=\x10H-\x1b\xd7=)@\xeeMUY\xc7\xfe@\xad\x87\xef\xdf\\K\xf6\xd9" +
-		"[\xd1\xf8\xa1\xf9\xe6\xbe \x1c\xdb\x17\x09\xa5\x06+\xf3\x8f\xd3ix\xdcAS" +
-		"2\x9b\xd6\xec+\x0b\xa1I*:\xaaZ\xdc\xb6<\x88\x0d\x06M\xa6\x8b^B@\xc5\xa9" +
-		"\x81\x00\xed\xf3\xfc\xafV\x03\x82\x97(\xd0\xd5\x91\x18)\xa6\x93\xc2\xb9" +
-		"r\xaa\x86\xc4\xdaf\xba\x91\x13\x00m8\x0bnV9\xf8\x1b\x92\xb6#\x87\xb1\x8f" +
-		"\x14\xd1\x1a\x02\x03\x01'\xfbgzQ\xc1\xe9\xbe\xc0\x06\x8f\x8c>k\xb2:\xde" +
-		"\xb1\x0e\x057\x9f&\xfeI\xc1\xfd\x1d\xc4\xe5\x9fx\xf2G\x93\xdf\x93\x8b\x91" +
-		"\x12\xbcE]2\xd8G\x92\x98\x9ee\xed/\x8b\x1a \xa0\xa5e\x07=\xe5V\x85_\xf8" +
-		"\x83\xe1\xeb\x14)1\xf8\xa6j\x9a\xbfk\xcf\x0f}\xda*)\xc7\x18\xb2\xbbjOs\x89" +
-		"\xb1\x1b\xc4\x11\x9bB\xc1dW\x04\x01\x92\x8d\xe1&\x08\x15\xd3\xf7'\xf6i\xfb" +
-		"\xe7\xe8\xe4b\x0d\xc3&\x17\x86Q\x81\xdc\xdc\x8b?\x7f\xff\xce/\xa2@\xdd[" +
-		"\x92\x8d\xa6\x05\x01%{\xaa\xa3a\x88(;/8\xa1\xe1\xf0l\xd7\x99\x02\x92(\xbe" +
-		"-\xad\x81\x00\xcd\xd0`\x87^g\xe7\xf6\xf76/\xe7\x8d\x8d\xfb\x93\xa3\xa5\x85" +
-		"\xd37\x14\xfa1W>\xc0\xdeto\x9f\x80/\x0eu?\xa2}XXJ)\xeaB\xef\xee\xe6\x9c" +
-		"i\xe6T#\xd8\x9e_a,\x00t\x17\x0bb\xc3\x9c\x12\xe3k\xc7\xab\x14\x15<\xb4\x8d" +
-		"\x1bx\n\x1d%\xb9Av=\xf9\xf8M6(\x14\x88~b\xef\x06@\xb2\x8dC\xe6\x1f\xfc\xa1" +
-		"\xe0 \x0e\x09\xbd\xf4\xb5Db\xc3P>\xdeqA\xb15\xc4Xi\xa4M\x07\xf1\x84\xd6" +
-		"\xc68\xa0\x1e\x1b>\xd1|A;\xf8\x07/\xfb\xbeU1\x15R\xf6\xa8\x82\xe7!\x9e\xcd" +
-		"B)\xd2\xca\nZV>\xb0\xf8\x17\xbb\x11K7m\xc6+Sk0q\xceIim\xdd\x82\xa7\xa2\xb7" +
-		"u6\x92\xee\xb0\xbd\xe3\x9f_\xed\xe3\x90|\x19\x12-e8Nn\x87\x04\xdd\x90\xe8" +
-		"\x95[~0tMC\xe7\xa1\x84\x84\xbfC\xc2\xc7hg\xb5]\xd9\xd8\xe2\xf1\x90\xd6\xdc" +
-		"\x83\xf2\xa6\xec\xe8\x11\xc1\x12\xe8k\xee\xd7\xa4\xb1\x09\x02.\x14\xbaC" +
-		"%e\x12\x7ft\x8c\"\xed\x1cR!\xcf:\xde\xda\xa2\x00\xc6B:F.\x1bf\x94jQi\x1f" +
-		"~B\xc1\xcb\x99\x8fy\x99\x02\"Q\xd7\xc0\xd8\xa8\xe8f\x02F\xea\xc5\x92\xcd" +
-		"Ats\xb4p\x17\xe4\xdc\x14\xd6\xb0\xd63\xec\xa7\xe60\x95.\xaeIj\x10\xb0|\x1f" +
-		"\x17]\xea\x8f\xa7\xa5\x00$\x08a\xe9\x01\x03e:\xb3\xb8+\xed`\xc8\x8a\xc9" +
-		"9s\xbc\x17\xa0\xe1\xa3\xf5\xf1'\x0d\x95\xe9\xf7O<\xbf\xe4d(\n\xf6\x00\x80" +
-		"q\x04V\x15v\xdd\xdc\xcc8R\x0b\x80\x88\xb7\xd9\xccD\x04\x88\xd4=3\xb3\x0d" +
-		"8\xeayb\x812\x1e\x85\x12\xee\xd6PV@ \xa0\x86q\x85\x06f\xeeq\x17u}\xa6\x1b" +
-		"y\x01\xe7\xe1\\\x08{\xdf\xdc\xd8\x82\xbf\x9c\x0e\x89\xde\xc7\xa9\x05*\xd8" +
-		"\xe1AE$\xb2\x898\xeb\xf7\x9a\x90\xfb\xd2\x83\xb0\x1b\x92/*[\x0d\xa7\xc4" +
-		"\xec\xfd:\x8bt4\xad\x9127\x196\xa6\x9e\xe0\xbb\xa3\xd4\xdaB\xbe\xb0\x16" +
-		"\x97\x8c\xa1\x15\xa3\xfc\x18\xb4\xce\xc8tc\xa1\x1a^bV\xc5\x9b\xef\x82\x9b" +
-		"\xebg\xef\xbf\x7f\x7f\xf4y\x1b\xf6\xc4\x00\xd6U\x04\x0f\xef\xc0\x8e\xaa" +
-		"\x0e\x09\xdeIM\xc4X\xfb\x8aF\xd9b\xe9\xf8o\xb4d\xa0P<PK\x07\x08\"e\xc0~" +
-		"\xae\x04\x00\x00\xae\x04\x00\x00PK\x01\x02\x14\x03\x14\x00\x08\x00\x00\x00" +
-		"\\\x1a4P\x93\xa9\xe7\xddV\x05\x00\x00V\x05\x00\x00\x10\x00\x09\x00 \x00" +
-		"\x00\x00\x00\x00\x00\x00\xa4\x81\x00\x00\x00\x00json-schema.jsonUT\x05\x00" +
-		"\x01\x91\x9d$^b,2666-5e249d91,application/jsonPK\x01\x02\x14\x03\x14\x00" +
-		"\x08\x00\x00\x00\xadi4P\x07!\xc7\xfa!\x02\x00\x00!\x02\x00\x00\x0b\x00\x09" +
-		"\x00\x1f\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x9d\x05\x00\x00sample.json" +
-		"UT\x05\x00\x01\xe6(%^b,6b6-5e2528e6,application/jsonPK\x01\x02\x14\x03\x14" +
-		"\x00\x08\x00\x00\x00xi4P\"e\xc0~\xae\x04\x00\x00\xae\x04\x00\x00\n\x00\x09" +
-		"\x00\x19\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x00\x08\x00\x00schema.cueU" +
-		"T\x05\x00\x01\x85(%^b,d7f-5e252885,text/plainPK\x05\x06\x00\x00\x00\x00" +
-		"\x03\x00\x03\x00\"\x01\x00\x00\xef\x0c\x00\x00\x01\x00-")
+	"PK\x03\x04\x14\x00\x00\x00\x00\x00+\xb5R]\xc1\xe2x\x98\xbc\x8e\x00\x00\xbc" +
+		"\x8e\x00\x00\x10\x00\x00\x00json-schema.jsonp\xeb\x18{\n  \"definitions\"" +
+		": {\n    \"hook\": {\n      \"$comment\": \"It should have one of comman" +
+		"d and url\",\n      \"type\": \"object\",\n      \"title\": \"The Hook S" +
+		"chema\",\n      \"properties\": {\n        \"command\": {\n          \"t" +
+		"ype\": \"array\",\n          \"title\": \"Command and arguments. They ca" +
+		"n contain environment variables\",\n          \"items\": {\n            " +
+		"\"type\": \"string\"\n          },\n          \"examples\": [\n         " +
+		"   [\"nginx\", \"-s\", \"quit\"]\n          ]\n        },\n        \"url" +
+		"\": {\n          \"type\": \"string\",\n          \"title\": \"URL to ca" +
+		"ll instead of command\",\n          \"pattern\": \"^https?://.+\",\n    " +
+		"      \"examples\": [\n            \"http://localhost:8080/actuator/shut" +
+		"down\"\n          ]\n        },\n        \"method\": {\n          \"type" +
+		"\": \"string\",\n          \"title\": \"HTTP method\",\n          \"defa" +
+		"ult\": \"GET\",\n          \"enum\": [\"GET\", \"POST\", \"PUT\", \"DELE" +
+		"TE\"]\n        },\n        \"timeout\": {\n          \"type\": \"number\"" +
+		",\n          \"title\": \"Timeout seconds\",\n          \"default\": 10\n" +
+		"        }\n      }\n    },\n    \"processEntry\": {\n      \"type\": \"o" +
+		"bject\",\n      \"title\": \"The Process Entry Schema\",\n      \"requir" +
+		"ed\": [\"name\", \"command\"],\n      \"properties\": {\n        \"name\"" +
+		": {\n          \"type\": \"string\",\n          \"title\": \"Name used a" +
+		"s process tag of logs\",\n          \"pattern\": \"^[a-zA-Z0-9_.-]+$\",\n" +
+		"          \"examples\": [\n            \"metrics-exporter\"\n          ]" +
+		"\n        },\n        \"command\": {\n          \"type\": \"array\",\n  " +
+		"        \"title\": \"Command and arguments. They can contain environment" +
+		" variables\",\n          \"minItems\": 1,\n          \"items\": {\n     " +
+		"       \"type\": \"string\"\n          },\n          \"examples\": [\n  " +
+		"          [\"node_exporter\", \"--web.listen-address=:9100\"]\n         " +
+		" ]\n        },\n        \"stdout\": { \"$ref\": \"#/definitions/logger\"" +
+		" },\n        \"stderr\": { \"$ref\": \"#/definitions/logger\" },\n      " +
+		"  \"process\": { \"$ref\": \"#/properties/process\" },\n        \"depend" +
+		"sOn\": { \"$ref\": \"#/properties/dependsOn\" },\n        \"onExit\": {\n" +
+		"          \"$comment\": \"stop: other processes are stopped when this pr" +
+		"ocess finishes, none: others keep running\",\n          \"type\": \"stri" +
+		"ng\",\n          \"title\": \"Behavior when this process finishes\",\n  " +
+		"        \"default\": \"stop\",\n          \"enum\": [\"stop\", \"none\"]" +
+		"\n        }\n      }\n    },\n    \"lifecycleHook\": {\n      \"$comment" +
+		"\": \"It should have one of command and url\",\n      \"type\": \"object" +
+		"\",\n      \"title\": \"The Lifecycle Hook Schema\",\n      \"properties" +
+		"\": {\n        \"name\": {\n          \"type\": \"string\",\n          \"" +
+		"title\": \"Name to tag output logs (default: preStart#1, postExit#1, ..." +
+		")\",\n          \"examples\": [\n            \"migrate\"\n          ]\n " +
+		"       },\n        \"command\": {\n          \"type\": \"array\",\n     " +
+		"     \"title\": \"Command and arguments. They can contain environment va" +
+		"riables\",\n          \"items\": {\n            \"type\": \"string\"\n  " +
+		"        },\n          \"examples\": [\n            [\"./manage.py\", \"m" +
+		"igrate\"]\n          ]\n        },\n        \"url\": {\n          \"type" +
+		"\": \"string\",\n          \"title\": \"URL to call instead of command\"" +
+		",\n          \"pattern\": \"^https?://.+\"\n        },\n        \"method" +
+		"\": {\n          \"type\": \"string\",\n          \"title\": \"HTTP meth" +
+		"od\",\n          \"default\": \"GET\",\n          \"enum\": [\"GET\", \"" +
+		"POST\", \"PUT\", \"DELETE\"]\n        },\n        \"timeout\": {\n      " +
+		"    \"type\": \"number\",\n          \"title\": \"Timeout seconds\",\n  " +
+		"        \"default\": 60\n        },\n        \"failurePolicy\": {\n     " +
+		"     \"$comment\": \"fail: docradle stops when the hook fails, ignore: s" +
+		"hows error and continues\",\n          \"type\": \"string\",\n          " +
+		"\"title\": \"Behavior when the hook fails\",\n          \"default\": \"f" +
+		"ail\",\n          \"enum\": [\"fail\", \"ignore\"]\n        }\n      }\n" +
+		"    },\n    \"logger\": {\n      \"$id\": \"#/properties/stdout\",\n    " +
+		"  \"type\": \"object\",\n      \"title\": \"The Stdout Schema\",\n      " +
+		"\"required\": [],\n      \"properties\": {\n        \"defaultLevel\": {\n" +
+		"          \"$id\": \"#/properties/stdout/properties/defaultLevel\",\n   " +
+		"       \"type\": \"string\",\n          \"title\": \"The DefaultLevel Sc" +
+		"hema\",\n          \"enum\": [\n            \"trace\",\n            \"de" +
+		"bug\",\n            \"info\",\n            \"warn\",\n            \"erro" +
+		"r\"\n          ],\n          \"default\": \"info\"\n        },\n        " +
+		"\"structured\": {\n          \"$id\": \"#/properties/stdout/properties/s" +
+		"tructured\",\n          \"type\": \"boolean\",\n          \"title\": \"T" +
+		"he Structured Schema\",\n          \"default\": true\n        },\n      " +
+		"  \"exportConfig\": {\n          \"$id\": \"#/properties/stdout/properti" +
+		"es/exportConfig\",\n          \"type\": \"string\",\n          \"title\"" +
+		": \"The ExportConfig Schema\",\n          \"default\": \"\",\n          " +
+		"\"examples\": [\n            \"fluentd://my-app.staging\",\n            " +
+		"\"kafka://my-app\"\n          ],\n          \"pattern\": \"^(.*)$\"\n   " +
+		"     },\n        \"exportHost\": {\n          \"$id\": \"#/properties/st" +
+		"dout/properties/exportHost\",\n          \"type\": \"string\",\n        " +
+		"  \"title\": \"The ExportHost Schema\",\n          \"default\": \"\",\n " +
+		"         \"examples\": [\n            \"tcp://localhost:24224/prod\"\n  " +
+		"        ],\n          \"pattern\": \"^(.*)$\"\n        },\n        \"pas" +
+		"sThrough\": {\n          \"$id\": \"#/properties/stdout/properties/passT" +
+		"hrough\",\n          \"type\": \"boolean\",\n          \"title\": \"The " +
+		"Passthrough Schema\",\n          \"default\": true\n        },\n        " +
+		"\"mask\": {\n          \"$id\": \"#/properties/stdout/properties/mask\"," +
+		"\n          \"type\": \"array\",\n          \"title\": \"The Mask Schema" +
+		"\",\n          \"items\": {\n            \"$id\": \"#/properties/stdout/" +
+		"properties/mask/items\",\n            \"type\": \"string\",\n           " +
+		" \"title\": \"The Items Schema\",\n            \"pattern\": \"^(.+)$\"\n" +
+		"          }\n        },\n        \"tags\": {\n          \"$id\": \"#/pro" +
+		"perties/stdout/properties/tags\",\n          \"type\": \"object\",\n    " +
+		"      \"title\": \"The Tags Schema\",\n          \"additionalProperties\"" +
+		": {\"type\": \"string\"}\n        }\n      }\n    }\n  },\n  \"$schema\"" +
+		": \"http://json-schema.org/draft-07/schema#\",\n  \"$id\": \"https://raw" +
+		".githubusercontent.com/future-architect/docradle/master/data/json-schema" +
+		".json\",\n  \"type\": \"object\",\n  \"title\": \"The Root Schema\",\n  " +
+		"\"required\": [],\n  \"properties\": {\n    \"env\": {\n      \"$id\": \"" +
+		"#/properties/env\",\n      \"type\": \"array\",\n      \"title\": \"The " +
+		"Env Schema\",\n      \"items\": {\n        \"$comment\": \"This entity d" +
+		"eclare the environment variable what the application needs\",\n        \"" +
+		"$id\": \"#/properties/env/items\",\n        \"type\": \"object\",\n     " +
+		"   \"title\": \"The Items Schema\",\n        \"required\": [\n          " +
+		"\"name\"\n        ],\n        \"properties\": {\n          \"name\": {\n" +
+		"            \"$id\": \"#/properties/env/items/properties/name\",\n      " +
+		"      \"type\": \"string\",\n            \"title\": \"The Name Schema\"," +
+		"\n            \"default\": \"\",\n            \"examples\": [\n         " +
+		"     \"TEST\"\n            ],\n            \"pattern\": \"^(.*)$\"\n    " +
+		"      },\n          \"default\": {\n            \"$id\": \"#/properties/" +
+		"env/items/properties/default\",\n            \"type\": \"string\",\n    " +
+		"        \"title\": \"The Default Schema\",\n            \"default\": \"\"" +
+		",\n            \"examples\": [\n              \"default value\"\n       " +
+		"     ],\n            \"pattern\": \"^(.*)$\"\n          },\n          \"" +
+		"required\": {\n            \"$comment\": \"If it is true and this key is" +
+		" not defined, docradle shows error\",\n            \"$id\": \"#/properti" +
+		"es/env/items/properties/required\",\n            \"type\": \"boolean\",\n" +
+		"            \"Title\": \"The Required Schema\",\n            \"default\"" +
+		": false,\n            \"examples\": [\n              true\n            ]" +
+		"\n          },\n          \"pattern\": {\n            \"$comment\": \"Sp" +
+		"ecify pattern to match env var value\",\n            \"$id\": \"#/proper" +
+		"ties/env/items/properties/pattern\",\n            \"type\": \"string\",\n" +
+		"            \"title\": \"Thsi is pattern\",\n            \"default\": \"" +
+		"\",\n            \"examples\": [\n              \"^https?://(.*)\"\n    " +
+		"        ]\n          },\n          \"mask\": {\n            \"$comment\"" +
+		": \"Specify this env var contains sensitive data\",\n            \"$id\"" +
+		": \"#/properties/env/items/properties/mask\",\n            \"type\": \"s" +
+		"tring\",\n            \"title\": \"The Mask Schema\",\n            \"def" +
+		"ault\": \"auto\",\n            \"enum\": [\n              \"auto\",\n   " +
+		"           \"hide\",\n              \"dhow\"\n            ]\n          }" +
+		"\n        }\n      }\n    },\n    \"file\": {\n      \"$comment\": \"Thi" +
+		"s entity declare the config file to be injected from outside of containe" +
+		"r\",\n      \"$id\": \"#/properties/file\",\n      \"type\": \"array\",\n" +
+		"      \"title\": \"The File Schema\",\n      \"items\": {\n        \"$id" +
+		"\": \"#/properties/file/items\",\n        \"type\": \"object\",\n       " +
+		" \"title\": \"The Items Schema\",\n        \"required\": [\n          \"" +
+		"name\"\n        ],\n        \"properties\": {\n          \"name\": {\n  " +
+		"          \"$id\": \"#/properties/file/items/properties/name\",\n       " +
+		"     \"type\": \"string\",\n            \"title\": \"The Name Schema\",\n" +
+		"            \"default\": \"\",\n            \"examples\": [\n           " +
+		"   \"test.txt\"\n            ],\n            \"pattern\": \"^(.*)$\"\n  " +
+		"        },\n          \"moveTo\": {\n            \"$id\": \"#/properties" +
+		"/file/items/properties/moveTo\",\n            \"type\": \"string\",\n   " +
+		"         \"title\": \"The Moveto Schema\",\n            \"default\": \"\"" +
+		",\n            \"examples\": [\n              \"/opt/config\"\n         " +
+		"   ],\n            \"pattern\": \"^(.*)$\"\n          },\n          \"re" +
+		"quired\": {\n            \"$id\": \"#/properties/file/items/properties/r" +
+		"equired\",\n            \"type\": \"boolean\",\n            \"title\": \"" +
+		"The Required Schema\",\n            \"default\": false,\n            \"e" +
+		"xamples\": [\n              false\n            ]\n          },\n        " +
+		"  \"default\": {\n            \"$id\": \"#/properties/file/items/propert" +
+		"ies/default\",\n            \"type\": \"string\",\n            \"title\"" +
+		": \"The Default Schema\",\n            \"default\": \"\",\n            \"" +
+		"examples\": [\n              \"/opt/config/config.json\"\n            ]," +
+		"\n            \"pattern\": \"^(.*)$\"\n          },\n          \"rewrite" +
+		"\": {\n            \"$id\": \"#/properties/file/items/properties/rewrite" +
+		"\",\n            \"type\": \"array\",\n            \"title\": \"The Rewr" +
+		"ite Schema\",\n            \"items\": {\n              \"$id\": \"#/prop" +
+		"erties/file/items/properties/rewrite/items\",\n              \"type\": \"" +
+		"object\",\n              \"title\": \"The Items Schema\",\n             " +
+		" \"required\": [\n                \"pattern\",\n                \"replac" +
+		"e\"\n              ],\n              \"properties\": {\n                " +
+		"\"pattern\": {\n                  \"$id\": \"#/properties/file/items/pro" +
+		"perties/rewrite/items/properties/pattern\",\n                  \"type\":" +
+		" \"string\",\n                  \"title\": \"The Pattern Schema\",\n    " +
+		"              \"default\": \"\",\n                  \"examples\": [\n   " +
+		"                 \"$VERSION\"\n                  ],\n                  \"" +
+		"pattern\": \"^(.*)$\"\n                },\n                \"replace\": " +
+		"{\n                  \"$id\": \"#/properties/file/items/properties/rewri" +
+		"te/items/properties/replace\",\n                  \"type\": \"string\",\n" +
+		"                  \"title\": \"The Replace Schema\",\n                  " +
+		"\"default\": \"\",\n                  \"examples\": [\n                 " +
+		"   \"${APP_MODE}\"\n                  ],\n                  \"pattern\":" +
+		" \"^(.*)$\"\n                }\n              }\n            }\n        " +
+		"  }\n        }\n      }\n    },\n    \"dependsOn\": {\n      \"$id\": \"" +
+		"#/properties/dependsOn\",\n      \"type\": \"array\",\n      \"title\": " +
+		"\"The Depends-on Schema\",\n      \"items\": {\n        \"$id\": \"#/pro" +
+		"perties/dependsOn/items\",\n        \"type\": \"object\",\n        \"tit" +
+		"le\": \"The Items Schema\",\n        \"oneOf\": [\n          { \"require" +
+		"d\": [\"url\"] },\n          { \"required\": [\"command\"] },\n         " +
+		" { \"required\": [\"anyOf\"] }\n        ],\n        \"properties\": {\n " +
+		"         \"name\": {\n            \"$id\": \"#/properties/dependsOn/item" +
+		"s/properties/name\",\n            \"type\": \"string\",\n            \"t" +
+		"itle\": \"Name to refer from after\",\n            \"examples\": [\n    " +
+		"          \"vault\"\n            ]\n          },\n          \"after\": {" +
+		"\n            \"$comment\": \"It starts checking after all of them becom" +
+		"e ready\",\n            \"$id\": \"#/properties/dependsOn/items/properti" +
+		"es/after\",\n            \"type\": \"array\",\n            \"title\": \"" +
+		"Names of dependencies to wait before checking\",\n            \"items\":" +
+		" {\n              \"type\": \"string\"\n            }\n          },\n   " +
+		"       \"anyOf\": {\n            \"$comment\": \"It becomes ready when o" +
+		"ne of them becomes ready\",\n            \"$id\": \"#/properties/depends" +
+		"On/items/properties/anyOf\",\n            \"type\": \"array\",\n        " +
+		"    \"title\": \"Alternative dependencies\",\n            \"items\": {\n" +
+		"              \"$ref\": \"#/properties/dependsOn/items\"\n            }\n" +
+		"          },\n          \"url\": {\n            \"$id\": \"#/properties/" +
+		"dependsOn/items/properties/url\",\n            \"type\": \"string\",\n  " +
+		"          \"title\": \"The Url Schema\",\n            \"default\": \"\"," +
+		"\n            \"examples\": [\n              \"http://microservice\"\n  " +
+		"          ],\n            \"pattern\":  \"^((file)|(https?)|(tcp[46]?)|(" +
+		"unix)|(tls)|(postgres(ql)?)|(mysql)|(rediss?)|(grpcs?)|(exec)|(dns)|(kaf" +
+		"ka)|(nats)|(amqps?)|(udp[46]?)|(wss?))://.+\"\n          },\n          \"" +
+		"command\": {\n            \"$comment\": \"It is used instead of url. Arg" +
+		"uments can contain environment variables\",\n            \"$id\": \"#/pr" +
+		"operties/dependsOn/items/properties/command\",\n            \"type\": \"" +
+		"array\",\n            \"title\": \"Command and arguments that should exi" +
+		"t with code 0\",\n            \"items\": {\n              \"type\": \"st" +
+		"ring\"\n            },\n            \"examples\": [\n              [\"pg" +
+		"_isready\", \"-h\", \"db\"]\n            ]\n          },\n          \"he" +
+		"ader\": {\n            \"$id\": \"#/properties/dependsOn/items/propertie" +
+		"s/header\",\n            \"type\": \"array\",\n            \"title\": \"" +
+		"The Header Schema\",\n            \"items\": {\n              \"$id\": \"" +
+		"#/properties/dependsOn/items/properties/header/items\",\n              \"" +
+		"type\": \"string\",\n              \"title\": \"The Items Schema\",\n   " +
+		"           \"default\": \"\",\n              \"examples\": [\n          " +
+		"      \"Authorization: Bearer 12345\"\n              ],\n              \"" +
+		"pattern\": \"^(.*)$\"\n            }\n          },\n          \"timeout\"" +
+		": {\n            \"$id\": \"#/properties/dependsOn/items/properties/time" +
+		"out\",\n            \"type\": \"number\",\n            \"title\": \"The " +
+		"Timeout Schema\",\n            \"default\": 10,\n            \"examples\"" +
+		": [\n              3\n            ]\n          },\n          \"interval\"" +
+		": {\n            \"$id\": \"#/properties/dependsOn/items/properties/inte" +
+		"rval\",\n            \"type\": \"number\",\n            \"title\": \"The" +
+		" Interval Schema\",\n            \"default\": 1,\n            \"examples" +
+		"\": [\n              1\n            ]\n          },\n          \"attempt" +
+		"Timeout\": {\n            \"$id\": \"#/properties/dependsOn/items/proper" +
+		"ties/attemptTimeout\",\n            \"type\": \"number\",\n            \"" +
+		"title\": \"Timeout seconds of each attempt\",\n            \"exclusiveMi" +
+		"nimum\": 0.01,\n            \"examples\": [\n              3.0\n        " +
+		"    ]\n          },\n          \"backoff\": {\n            \"$comment\":" +
+		" \"Delay between attempts starts from initial and is multiplied by multi" +
+		"plier up to max. jitter shortens each delay randomly by this ratio\",\n " +
+		"           \"$id\": \"#/properties/dependsOn/items/properties/backoff\"," +
+		"\n            \"type\": \"object\",\n            \"title\": \"Exponentia" +
+		"l backoff of intervals\",\n            \"properties\": {\n              " +
+		"\"initial\": {\n                \"$id\": \"#/properties/dependsOn/items/" +
+		"properties/backoff/properties/initial\",\n                \"type\": \"nu" +
+		"mber\",\n                \"title\": \"Initial delay seconds (default: in" +
+		"terval)\",\n                \"exclusiveMinimum\": 0.01\n              }," +
+		"\n              \"max\": {\n                \"$id\": \"#/properties/depe" +
+		"ndsOn/items/properties/backoff/properties/max\",\n                \"type" +
+		"\": \"number\",\n                \"title\": \"Max delay seconds\",\n    " +
+		"            \"exclusiveMinimum\": 0.01,\n                \"examples\": [" +
+		"\n                  30\n                ]\n              },\n           " +
+		"   \"multiplier\": {\n                \"$id\": \"#/properties/dependsOn/" +
+		"items/properties/backoff/properties/multiplier\",\n                \"typ" +
+		"e\": \"number\",\n                \"title\": \"Multiplier of delay\",\n " +
+		"               \"default\": 2,\n                \"minimum\": 1\n        " +
+		"      },\n              \"jitter\": {\n                \"$id\": \"#/prop" +
+		"erties/dependsOn/items/properties/backoff/properties/jitter\",\n        " +
+		"        \"type\": \"number\",\n                \"title\": \"Ratio to sho" +
+		"rten each delay randomly\",\n                \"default\": 0.2,\n        " +
+		"        \"minimum\": 0,\n                \"maximum\": 1\n              }" +
+		"\n            }\n          },\n          \"method\": {\n            \"$c" +
+		"omment\": \"Default value is HEAD. If body conditions exist, GET is used" +
+		"\",\n            \"$id\": \"#/properties/dependsOn/items/properties/meth" +
+		"od\",\n            \"type\": \"string\",\n            \"title\": \"The M" +
+		"ethod Schema\",\n            \"enum\": [\n              \"GET\",\n      " +
+		"        \"HEAD\",\n              \"POST\",\n              \"OPTIONS\"\n " +
+		"           ]\n          },\n          \"expectStatus\": {\n            \"" +
+		"$comment\": \"Acceptable HTTP status. Default value is 2xx\",\n         " +
+		"   \"$id\": \"#/properties/dependsOn/items/properties/expectStatus\",\n " +
+		"           \"type\": \"array\",\n            \"title\": \"The ExpectStat" +
+		"us Schema\",\n            \"items\": {\n              \"$id\": \"#/prope" +
+		"rties/dependsOn/items/properties/expectStatus/items\",\n              \"" +
+		"type\": [\"integer\", \"string\"],\n              \"title\": \"The Items" +
+		" Schema\",\n              \"examples\": [\n                200,\n       " +
+		"         \"2xx\",\n                \"200-204\"\n              ],\n      " +
+		"        \"pattern\": \"^[1-5](\\\\d\\\\d|xx)(-[1-5]\\\\d\\\\d)?$\"\n    " +
+		"        }\n          },\n          \"bodyContains\": {\n            \"$i" +
+		"d\": \"#/properties/dependsOn/items/properties/bodyContains\",\n        " +
+		"    \"type\": \"string\",\n            \"title\": \"The BodyContains Sch" +
+		"ema\",\n            \"examples\": [\n              \"READY\"\n          " +
+		"  ]\n          },\n          \"bodyRegexp\": {\n            \"$id\": \"#" +
+		"/properties/dependsOn/items/properties/bodyRegexp\",\n            \"type" +
+		"\": \"string\",\n            \"title\": \"The BodyRegexp Schema\",\n    " +
+		"        \"examples\": [\n              \"\\\"status\\\":\\\\s*\\\"(UP|OK" +
+		")\\\"\"\n            ]\n          },\n          \"jsonPath\": {\n       " +
+		"     \"$id\": \"#/properties/dependsOn/items/properties/jsonPath\",\n   " +
+		"         \"type\": \"string\",\n            \"title\": \"The JSONPath Sc" +
+		"hema\",\n            \"examples\": [\n              \"$.status == \\\"UP" +
+		"\\\"\"\n            ]\n          },\n          \"tls\": {\n            \"" +
+		"$comment\": \"TLS setting for https://, tls://, grpc:// and databases. F" +
+		"ile paths and serverName can contain envvars\",\n            \"$id\": \"" +
+		"#/properties/dependsOn/items/properties/tls\",\n            \"type\": \"" +
+		"object\",\n            \"title\": \"The TLS Schema\",\n            \"pro" +
+		"perties\": {\n              \"ca\": {\n                \"$id\": \"#/prop" +
+		"erties/dependsOn/items/properties/tls/properties/ca\",\n                " +
+		"\"type\": \"string\",\n                \"title\": \"CA certificate file " +
+		"(PEM)\",\n                \"examples\": [\n                  \"/etc/ssl/" +
+		"private-ca.pem\"\n                ]\n              },\n              \"c" +
+		"ert\": {\n                \"$id\": \"#/properties/dependsOn/items/proper" +
+		"ties/tls/properties/cert\",\n                \"type\": \"string\",\n    " +
+		"            \"title\": \"Client certificate file (PEM)\",\n             " +
+		"   \"examples\": [\n                  \"${CERT_DIR}/client.pem\"\n      " +
+		"          ]\n              },\n              \"key\": {\n               " +
+		" \"$id\": \"#/properties/dependsOn/items/properties/tls/properties/key\"" +
+		",\n                \"type\": \"string\",\n                \"title\": \"C" +
+		"lient private key file (PEM)\",\n                \"examples\": [\n      " +
+		"            \"${CERT_DIR}/client-key.pem\"\n                ]\n         " +
+		"     },\n              \"serverName\": {\n                \"$id\": \"#/p" +
+		"roperties/dependsOn/items/properties/tls/properties/serverName\",\n     " +
+		"           \"type\": \"string\",\n                \"title\": \"Server na" +
+		"me for SNI and verification\"\n              },\n              \"insecur" +
+		"eSkipVerify\": {\n                \"$id\": \"#/properties/dependsOn/item" +
+		"s/properties/tls/properties/insecureSkipVerify\",\n                \"typ" +
+		"e\": \"boolean\",\n                \"title\": \"Skip server certificate " +
+		"verification\",\n                \"default\": false\n              },\n " +
+		"             \"warnExpiry\": {\n                \"$id\": \"#/properties/" +
+		"dependsOn/items/properties/tls/properties/warnExpiry\",\n               " +
+		" \"type\": \"number\",\n                \"title\": \"Show warning if ser" +
+		"ver certificate expires within this days\",\n                \"examples\"" +
+		": [\n                  30\n                ]\n              }\n         " +
+		"   }\n          },\n          \"monitor\": {\n            \"$id\": \"#/p" +
+		"roperties/dependsOn/items/properties/monitor\",\n            \"type\": \"" +
+		"boolean\",\n            \"title\": \"Keep checking while the command run" +
+		"s\",\n            \"default\": false\n          },\n          \"critical" +
+		"\": {\n            \"$id\": \"#/properties/dependsOn/items/properties/cr" +
+		"itical\",\n            \"type\": \"boolean\",\n            \"title\": \"" +
+		"Apply dependencyMonitor.policy when it is down\",\n            \"default" +
+		"\": false\n          },\n          \"user\": {\n            \"$comment\"" +
+		": \"It overwrites user info in url. It can contain envvars\",\n         " +
+		"   \"$id\": \"#/properties/dependsOn/items/properties/user\",\n         " +
+		"   \"type\": \"string\",\n            \"title\": \"User name for databas" +
+		"es\",\n            \"examples\": [\n              \"${DB_USER}\"\n      " +
+		"      ]\n          },\n          \"password\": {\n            \"$comment" +
+		"\": \"It overwrites user info in url. It can contain envvars\",\n       " +
+		"     \"$id\": \"#/properties/dependsOn/items/properties/password\",\n   " +
+		"         \"type\": \"string\",\n            \"title\": \"Password for da" +
+		"tabases\",\n            \"examples\": [\n              \"${DB_PASSWORD}\"" +
+		"\n            ]\n          },\n          \"query\": {\n            \"$id" +
+		"\": \"#/properties/dependsOn/items/properties/query\",\n            \"ty" +
+		"pe\": \"string\",\n            \"title\": \"Probe query for databases\"," +
+		"\n            \"examples\": [\n              \"SELECT 1\",\n            " +
+		"  \"EXISTS ready\"\n            ]\n          },\n          \"resolver\":" +
+		" {\n            \"$comment\": \"Default port is 53. System resolver is u" +
+		"sed if it is omitted\",\n            \"$id\": \"#/properties/dependsOn/i" +
+		"tems/properties/resolver\",\n            \"type\": \"string\",\n        " +
+		"    \"title\": \"DNS server for dns://\",\n            \"examples\": [\n" +
+		"              \"10.96.0.10:53\"\n            ]\n          },\n          " +
+		"\"send\": {\n            \"$comment\": \"It can contain environment vari" +
+		"ables\",\n            \"$id\": \"#/properties/dependsOn/items/properties" +
+		"/send\",\n            \"type\": \"string\",\n            \"title\": \"Pa" +
+		"yload to send to udp://\",\n            \"examples\": [\n              \"" +
+		"ping\"\n            ]\n          },\n          \"expect\": {\n          " +
+		"  \"$comment\": \"If it is omitted, the target is ready unless it reject" +
+		"s the datagram\",\n            \"$id\": \"#/properties/dependsOn/items/p" +
+		"roperties/expect\",\n            \"type\": \"string\",\n            \"ti" +
+		"tle\": \"Text that udp:// response should contain\",\n            \"exam" +
+		"ples\": [\n              \"pong\"\n            ]\n          },\n        " +
+		"  \"contains\": {\n            \"$id\": \"#/properties/dependsOn/items/p" +
+		"roperties/contains\",\n            \"type\": \"string\",\n            \"" +
+		"title\": \"Text that file:// content should contain\",\n            \"ex" +
+		"amples\": [\n              \"READY\"\n            ]\n          },\n     " +
+		"     \"regexp\": {\n            \"$id\": \"#/properties/dependsOn/items/" +
+		"properties/regexp\",\n            \"type\": \"string\",\n            \"t" +
+		"itle\": \"Pattern that file:// content should match\",\n            \"ex" +
+		"amples\": [\n              \"^status=(ok|ready)$\"\n            ]\n     " +
+		"     },\n          \"minSize\": {\n            \"$id\": \"#/properties/d" +
+		"ependsOn/items/properties/minSize\",\n            \"type\": \"integer\"," +
+		"\n            \"title\": \"Minimum file size in bytes for file://\",\n  " +
+		"          \"minimum\": 0,\n            \"examples\": [\n              1\n" +
+		"            ]\n          },\n          \"maxAge\": {\n            \"$id\"" +
+		": \"#/properties/dependsOn/items/properties/maxAge\",\n            \"typ" +
+		"e\": \"number\",\n            \"title\": \"Seconds within which file:// " +
+		"should be modified\",\n            \"exclusiveMinimum\": 0,\n           " +
+		" \"examples\": [\n              60\n            ]\n          },\n       " +
+		"   \"notExists\": {\n            \"$comment\": \"It can't be used with o" +
+		"ther file conditions\",\n            \"$id\": \"#/properties/dependsOn/i" +
+		"tems/properties/notExists\",\n            \"type\": \"boolean\",\n      " +
+		"      \"title\": \"Wait until file:// doesn't exist\",\n            \"de" +
+		"fault\": false\n          }\n        }\n      }\n    },\n    \"dependsOn" +
+		"Timeout\": {\n      \"$comment\": \"Each dependency's timeout is also ap" +
+		"plied\",\n      \"$id\": \"#/properties/dependsOnTimeout\",\n      \"typ" +
+		"e\": \"number\",\n      \"title\": \"Overall timeout seconds of waiting " +
+		"for all dependencies\",\n      \"exclusiveMinimum\": 0,\n      \"example" +
+		"s\": [\n        60\n      ]\n    },\n    \"dependencyMonitor\": {\n     " +
+		" \"$comment\": \"Behavior of monitoring dependencies that have monitor o" +
+		"ption while the command runs\",\n      \"$id\": \"#/properties/dependenc" +
+		"yMonitor\",\n      \"type\": \"object\",\n      \"title\": \"The Depende" +
+		"ncy Monitor Schema\",\n      \"properties\": {\n        \"interval\": {\n" +
+		"          \"$id\": \"#/properties/dependencyMonitor/properties/interval\"" +
+		",\n          \"type\": \"number\",\n          \"title\": \"Check interva" +
+		"l seconds\",\n          \"default\": 5\n        },\n        \"threshold\"" +
+		": {\n          \"$id\": \"#/properties/dependencyMonitor/properties/thre" +
+		"shold\",\n          \"type\": \"number\",\n          \"title\": \"Second" +
+		"s to apply policy after critical dependency is down\",\n          \"defa" +
+		"ult\": 30\n        },\n        \"policy\": {\n          \"$id\": \"#/pro" +
+		"perties/dependencyMonitor/properties/policy\",\n          \"type\": \"st" +
+		"ring\",\n          \"title\": \"Action to the command when critical depe" +
+		"ndency is down\",\n          \"default\": \"none\",\n          \"enum\":" +
+		" [\"none\", \"stop\", \"restart\"]\n        },\n        \"gracePeriod\":" +
+		" {\n          \"$id\": \"#/properties/dependencyMonitor/properties/grace" +
+		"Period\",\n          \"type\": \"number\",\n          \"title\": \"Secon" +
+		"ds to wait after SIGTERM before SIGKILL\",\n          \"default\": 10\n " +
+		"       }\n      }\n    },\n    \"process\": {\n      \"$id\": \"#/proper" +
+		"ties/process\",\n      \"type\": \"object\",\n      \"title\": \"The Pro" +
+		"cess Schema\",\n      \"properties\": {\n        \"noticeExitHttp\": {\n" +
+		"          \"$comment\": \"It can contain environment variables\",\n     " +
+		"     \"$id\": \"#/properties/process/properties/noticeExitHttp\",\n     " +
+		"     \"type\": \"string\",\n          \"title\": \"URL to POST JSON exit" +
+		" report when the command exits\",\n          \"examples\": [\n          " +
+		"  \"https://example.com/exit\"\n          ]\n        },\n        \"notic" +
+		"eExitSlack\": {\n          \"$comment\": \"It can contain environment va" +
+		"riables\",\n          \"$id\": \"#/properties/process/properties/noticeE" +
+		"xitSlack\",\n          \"type\": \"string\",\n          \"title\": \"Sla" +
+		"ck incoming webhook URL to send exit information\",\n          \"example" +
+		"s\": [\n            \"${SLACK_WEBHOOK_URL}\"\n          ]\n        },\n " +
+		"       \"logBucket\": {\n          \"$comment\": \"gocloud blob URL. It " +
+		"can contain environment variables\",\n          \"$id\": \"#/properties/" +
+		"process/properties/logBucket\",\n          \"type\": \"string\",\n      " +
+		"    \"title\": \"Bucket to upload output of the command when it exits\"," +
+		"\n          \"examples\": [\n            \"s3://my-bucket?region=us-west" +
+		"-1\",\n            \"gs://my-bucket\",\n            \"file:///var/log/do" +
+		"cradle\"\n          ]\n        },\n        \"logKey\": {\n          \"$c" +
+		"omment\": \"Hostname, StartTime, Command and ProcessID are available\",\n" +
+		"          \"$id\": \"#/properties/process/properties/logKey\",\n        " +
+		"  \"type\": \"string\",\n          \"title\": \"Object key template of u" +
+		"ploaded log\",\n          \"default\": \"{{.Hostname}}/{{.StartTime}}.lo" +
+		"g.gz\"\n        },\n        \"noticeExitPubSub\": {\n          \"$commen" +
+		"t\": \"gocloud pubsub URL. The report is sent as JSON message body\",\n " +
+		"         \"$id\": \"#/properties/process/properties/noticeExitPubSub\",\n" +
+		"          \"type\": \"string\",\n          \"title\": \"Pub/Sub topic to" +
+		" send exit report\",\n          \"examples\": [\n            \"kafka://d" +
+		"ocradle-exit\"\n          ]\n        },\n        \"signalRewrite\": {\n " +
+		"         \"$comment\": \"Signal names like TERM, SIGTERM or numbers are " +
+		"available\",\n          \"$id\": \"#/properties/process/properties/signa" +
+		"lRewrite\",\n          \"type\": \"object\",\n          \"title\": \"Con" +
+		"vert signals that are forwarded to the command\",\n          \"additiona" +
+		"lProperties\": {\n            \"type\": \"string\"\n          },\n      " +
+		"    \"examples\": [\n            {\"TERM\": \"QUIT\"}\n          ]\n    " +
+		"    },\n        \"stopSignal\": {\n          \"$comment\": \"If it is om" +
+		"itted, the received signal (or TERM when dependency monitor stops the co" +
+		"mmand) is used\",\n          \"$id\": \"#/properties/process/properties/" +
+		"stopSignal\",\n          \"type\": \"string\",\n          \"title\": \"S" +
+		"ignal to stop the command\",\n          \"examples\": [\n            \"Q" +
+		"UIT\"\n          ]\n        },\n        \"stopTimeout\": {\n          \"" +
+		"$id\": \"#/properties/process/properties/stopTimeout\",\n          \"typ" +
+		"e\": \"number\",\n          \"title\": \"Seconds to wait after stop sign" +
+		"al before killing the process group\",\n          \"default\": 10\n     " +
+		"   },\n        \"preStop\": {\n          \"$ref\": \"#/definitions/hook\"" +
+		",\n          \"title\": \"Hook that runs before sending stop signal\"\n " +
+		"       },\n        \"restart\": {\n          \"$comment\": \"The command" +
+		" isn't restarted when it is stopped by signal\",\n          \"$id\": \"#" +
+		"/properties/process/properties/restart\",\n          \"type\": \"string\"" +
+		",\n          \"title\": \"Restart policy of the command\",\n          \"" +
+		"enum\": [\"no\", \"on-failure\", \"always\"],\n          \"default\": \"" +
+		"no\"\n        },\n        \"maxRestarts\": {\n          \"$id\": \"#/pro" +
+		"perties/process/properties/maxRestarts\",\n          \"type\": \"integer" +
+		"\",\n          \"title\": \"Give up after this number of consecutive res" +
+		"tarts (0: unlimited)\",\n          \"default\": 0,\n          \"minimum\"" +
+		": 0\n        },\n        \"restartBackoff\": {\n          \"$comment\": " +
+		"\"Delay starts from initial (default: 1) and is multiplied by multiplier" +
+		" up to max (default: 60)\",\n          \"$id\": \"#/properties/process/p" +
+		"roperties/restartBackoff\",\n          \"type\": \"object\",\n          " +
+		"\"title\": \"Exponential backoff of delays before restarts\",\n         " +
+		" \"properties\": {\n            \"initial\": {\n              \"$id\": \"" +
+		"#/properties/process/properties/restartBackoff/properties/initial\",\n  " +
+		"            \"type\": \"number\",\n              \"title\": \"Initial de" +
+		"lay seconds\",\n              \"exclusiveMinimum\": 0.01\n            }," +
+		"\n            \"max\": {\n              \"$id\": \"#/properties/process/" +
+		"properties/restartBackoff/properties/max\",\n              \"type\": \"n" +
+		"umber\",\n              \"title\": \"Max delay seconds\",\n             " +
+		" \"exclusiveMinimum\": 0.01\n            },\n            \"multiplier\":" +
+		" {\n              \"$id\": \"#/properties/process/properties/restartBack" +
+		"off/properties/multiplier\",\n              \"type\": \"number\",\n     " +
+		"         \"title\": \"Multiplier of delay\",\n              \"default\":" +
+		" 2,\n              \"minimum\": 1\n            },\n            \"jitter\"" +
+		": {\n              \"$id\": \"#/properties/process/properties/restartBac" +
+		"koff/properties/jitter\",\n              \"type\": \"number\",\n        " +
+		"      \"title\": \"Ratio to shorten each delay randomly\",\n            " +
+		"  \"default\": 0.2,\n              \"minimum\": 0,\n              \"maxi" +
+		"mum\": 1\n            }\n          }\n        },\n        \"restartWindo" +
+		"w\": {\n          \"$comment\": \"Restart count and delay are reset when" +
+		" the command runs longer than this\",\n          \"$id\": \"#/properties" +
+		"/process/properties/restartWindow\",\n          \"type\": \"number\",\n " +
+		"         \"title\": \"Seconds to detect crash loop\",\n          \"defau" +
+		"lt\": 60,\n          \"exclusiveMinimum\": 0.01\n        },\n        \"u" +
+		"ser\": {\n          \"$comment\": \"Files in files section are written w" +
+		"ith ownership of this user. --user flag overrides it\",\n          \"$id" +
+		"\": \"#/properties/process/properties/user\",\n          \"type\": \"str" +
+		"ing\",\n          \"title\": \"User name or uid to run the command as\"," +
+		"\n          \"examples\": [\n            \"app\",\n            \"1000\"\n" +
+		"          ]\n        },\n        \"group\": {\n          \"$comment\": \"" +
+		"Supplementary groups of the user are used if it is not set\",\n         " +
+		" \"$id\": \"#/properties/process/properties/group\",\n          \"type\"" +
+		": \"string\",\n          \"title\": \"Group name or gid to run the comma" +
+		"nd as\",\n          \"examples\": [\n            \"app\",\n            \"" +
+		"1000\"\n          ]\n        },\n        \"limits\": {\n          \"$com" +
+		"ment\": \"Applied only to the command (not docradle itself) before it st" +
+		"arts\",\n          \"$id\": \"#/properties/process/properties/limits\",\n" +
+		"          \"type\": \"object\",\n          \"title\": \"Resource limits " +
+		"of the command\",\n          \"additionalProperties\": false,\n         " +
+		" \"properties\": {\n            \"nofile\": {\n              \"$comment\"" +
+		": \"number (both soft and hard limit), \\\"unlimited\\\" or \\\"soft:har" +
+		"d\\\"\",\n              \"oneOf\": [\n                { \"type\": \"inte" +
+		"ger\", \"minimum\": 0 },\n                { \"type\": \"string\", \"patt" +
+		"ern\": \"^(unlimited|[0-9]+)(:(unlimited|[0-9]+))?$\" }\n              ]" +
+		"\n            },\n            \"nproc\": {\n              \"$comment\": " +
+		"\"number (both soft and hard limit), \\\"unlimited\\\" or \\\"soft:hard\\" +
+		"\"\",\n              \"oneOf\": [\n                { \"type\": \"integer" +
+		"\", \"minimum\": 0 },\n                { \"type\": \"string\", \"pattern" +
+		"\": \"^(unlimited|[0-9]+)(:(unlimited|[0-9]+))?$\" }\n              ]\n " +
+		"           },\n            \"core\": {\n              \"$comment\": \"nu" +
+		"mber (both soft and hard limit), \\\"unlimited\\\" or \\\"soft:hard\\\"\"" +
+		",\n              \"oneOf\": [\n                { \"type\": \"integer\", " +
+		"\"minimum\": 0 },\n                { \"type\": \"string\", \"pattern\": " +
+		"\"^(unlimited|[0-9]+)(:(unlimited|[0-9]+))?$\" }\n              ]\n     " +
+		"       },\n            \"memlock\": {\n              \"$comment\": \"num" +
+		"ber (both soft and hard limit), \\\"unlimited\\\" or \\\"soft:hard\\\"\"" +
+		",\n              \"oneOf\": [\n                { \"type\": \"integer\", " +
+		"\"minimum\": 0 },\n                { \"type\": \"string\", \"pattern\": " +
+		"\"^(unlimited|[0-9]+)(:(unlimited|[0-9]+))?$\" }\n              ]\n     " +
+		"       },\n            \"stack\": {\n              \"$comment\": \"numbe" +
+		"r (both soft and hard limit), \\\"unlimited\\\" or \\\"soft:hard\\\"\",\n" +
+		"              \"oneOf\": [\n                { \"type\": \"integer\", \"m" +
+		"inimum\": 0 },\n                { \"type\": \"string\", \"pattern\": \"^" +
+		"(unlimited|[0-9]+)(:(unlimited|[0-9]+))?$\" }\n              ]\n        " +
+		"    }\n          }\n        },\n        \"nice\": {\n          \"$id\": " +
+		"\"#/properties/process/properties/nice\",\n          \"type\": \"integer" +
+		"\",\n          \"title\": \"Niceness of the command\",\n          \"mini" +
+		"mum\": -20,\n          \"maximum\": 19\n        },\n        \"oomScoreAd" +
+		"j\": {\n          \"$comment\": \"Linux only\",\n          \"$id\": \"#/" +
+		"properties/process/properties/oomScoreAdj\",\n          \"type\": \"inte" +
+		"ger\",\n          \"title\": \"oom_score_adj of the command\",\n        " +
+		"  \"minimum\": -1000,\n          \"maximum\": 1000        },\n        \"" +
+		"timeout\": {\n          \"$comment\": \"The command is stopped by stopSi" +
+		"gnal and docradle exits with 124\",\n          \"$id\": \"#/properties/p" +
+		"rocess/properties/timeout\",\n          \"type\": \"number\",\n         " +
+		" \"title\": \"Max seconds the command runs from the first start (restart" +
+		"s don't reset it)\",\n          \"exclusiveMinimum\": 0.01\n        },\n" +
+		"        \"watchdog\": {\n          \"$comment\": \"The process group is " +
+		"killed and docradle exits with 124\",\n          \"$id\": \"#/properties" +
+		"/process/properties/watchdog\",\n          \"type\": \"number\",\n      " +
+		"    \"title\": \"Seconds to kill the command if it doesn't write output\"" +
+		",\n          \"exclusiveMinimum\": 0.01\n        }\n      }\n    },\n   " +
+		" \"hooks\": {\n      \"$id\": \"#/properties/hooks\",\n      \"type\": \"" +
+		"object\",\n      \"title\": \"Commands that run before the command start" +
+		"s and after it exits\",\n      \"properties\": {\n        \"preStart\": " +
+		"{\n          \"$comment\": \"They run only once even if the command rest" +
+		"arts\",\n          \"$id\": \"#/properties/hooks/properties/preStart\",\n" +
+		"          \"type\": \"array\",\n          \"title\": \"Hooks that run in" +
+		" order before the command starts\",\n          \"items\": { \"$ref\": \"" +
+		"#/definitions/lifecycleHook\" }\n        },\n        \"postExit\": {\n  " +
+		"        \"$id\": \"#/properties/hooks/properties/postExit\",\n          " +
+		"\"type\": \"array\",\n          \"title\": \"Hooks that run in order aft" +
+		"er the command exits\",\n          \"items\": { \"$ref\": \"#/definition" +
+		"s/lifecycleHook\" }\n        }\n      }\n    },\n    \"processes\": {\n " +
+		"     \"$comment\": \"They start in order before the command. The command" +
+		" can be omitted if processes exist\",\n      \"$id\": \"#/properties/pro" +
+		"cesses\",\n      \"type\": \"array\",\n      \"title\": \"Commands that " +
+		"docradle supervises with the command\",\n      \"items\": { \"$ref\": \"" +
+		"#/definitions/processEntry\" }\n    },\n    \"stdout\": { \"$ref\": \"#/" +
+		"definitions/logger\" },\n    \"stderr\": { \"$ref\": \"#/definitions/log" +
+		"ger\" },\n    \"logLevel\": {\n      \"$id\": \"#/properties/logLevel\"," +
+		"\n      \"type\": \"string\",\n      \"title\": \"The Loglevel Schema\"," +
+		"\n      \"enum\": [\n        \"trace\",\n        \"debug\",\n        \"i" +
+		"nfo\",\n        \"warn\",\n        \"error\"\n      ],\n      \"default\"" +
+		": \"info\"\n    },\n    \"version\": {\n      \"$id\": \"#/properties/ve" +
+		"rsion\",\n      \"type\": \"string\",\n      \"title\": \"The Version Sc" +
+		"hema\",\n      \"default\": \"\",\n      \"examples\": [\n        \"1.0." +
+		"0\"\n      ],\n      \"pattern\": \"^(.*)$\"\n    },\n    \"author\": {\n" +
+		"      \"$id\": \"#/properties/author\",\n      \"type\": \"string\",\n  " +
+		"    \"title\": \"The Author Schema\",\n      \"default\": \"\",\n      \"" +
+		"examples\": [\n        \"{{.UserName}}\"\n      ],\n      \"pattern\": \"" +
+		"^(.*)$\"\n    }\n  }\n}\x03PK\x03\x04\x14\x00\x00\x00\x00\x00Xj6P\x93\x07" +
+		"h6\xba\x06\x00\x00\xba\x06\x00\x00\x0b\x00\x00\x00sample.jsonPk\x10{\n  " +
+		"\"$schema\": \"https://raw.githubusercontent.com/future-architect/docrad" +
+		"le/master/data/json-schema.json\",\n  \"$comment\": \"Sample JSON config" +
+		" for docradle\",\n  \"env\": [\n    {\n      \"$comment\": \"This entity" +
+		" declare the environment variable what the application needs\",\n      \"" +
+		"name\":  \"TEST\",\n      \"default\": \"default value\",\n      \"requi" +
+		"red\": true,\n      \"pattern\": \"\",\n      \"mask\": \"auto\"\n    }\n" +
+		"  ],\n  \"file\": [\n    {\n      \"$comment\": \"This entity declare th" +
+		"e config file to be injected from outside of container\",\n      \"name\"" +
+		": \"test.txt\",\n      \"moveTo\": \"/opt/config\",\n      \"required\":" +
+		" false,\n      \"default\": \"/opt/config/config.json\",\n      \"rewrit" +
+		"e\": [\n        {\n          \"pattern\": \"$VERSION\",\n          \"rep" +
+		"lace\": \"${APP_MODE}\"\n        }\n      ]\n    }\n  ],\n  \"dependsOn\"" +
+		": [\n    {\n      \"$comment\": \"This entity declares other container. " +
+		"docradle waits until this item is available.\",\n      \"url\": \"http:/" +
+		"/microservice\",\n      \"headers\": [\"Authorization: Bearer 12345\"],\n" +
+		"      \"timeout\": 3.0,\n      \"interval\": 1.0\n    }\n  ],\n  \"stdou" +
+		"t\": {\n    \"$comment\": \"Setting for stdout. If the application uses " +
+		"zerolog (JSON log), Set structured true\",\n    \"defaultLevel\": \"info" +
+		"\",\n    \"structured\": true,\n    \"exportConfig\": \"\",\n    \"expor" +
+		"tHost\": \"\",\n    \"passThrough\": true,\n    \"mask\": [\"mask\"],\n " +
+		"   \"tags\": {\"tag-key\": \"tag-value\"}\n  },\n  \"stderr\": {\n    \"" +
+		"$comment\": \"Setting for stderr. If the application uses zerolog (JSON " +
+		"log), Set structured true\",\n    \"defaultLevel\": \"error\",\n    \"st" +
+		"ructured\": true,\n    \"exportConfig\": \"\",\n    \"exportHost\": \"\"" +
+		",\n    \"passThrough\": true,\n    \"mask\": [\"mask\"],\n    \"tags\": " +
+		"{\"tag-key\": \"tag-value\"}\n  },\n  \"logLevel\": \"info\",\n  \"versi" +
+		"on\": \"1.0.0\",\n  \"author\": \"{{.UserName}}\"\n}\x03PK\x03\x04\x14\x00" +
+		"\x00\x00\x00\x00\xf8\xb4R]\x0c\xc94(\x941\x00\x00\x941\x00\x00\n\x00\x00" +
+		"\x00schema.cue\xf0\x18\x13// Environment variable declaration\nEnv :: {\n" +
+		"  $comment?: string\n  name:      string                    // name like" +
+		" \"APP_MODE\"\n  default?:  string                    // default value\n" +
+		"  required:  *false | true             // is this environment variable r" +
+		"equired? (default: false)\n  pattern?:  string                    // reg" +
+		"exp pattern of the value\n  mask:      *\"auto\" | \"hide\" | \"show\" /" +
+		"/ it contains any secret value like credential.\n                       " +
+		"                // \"auto\" hides value if key name contains \"PASSWORD\"" +
+		", \"SECRET\", \"CREDENTIAL\".\n}\n\n// Rewrite configuration file at run" +
+		"time\n// It is useful for modifying frontend code by using envvars\n// y" +
+		"ou can use regexp and envvars.\nRewrite :: {\n  $comment?: string\n  pat" +
+		"tern: string // rewrite target eg: \"<body.*>\"\n  replace: string // re" +
+		"write pattern eg: \"<script>const mode=${APP_MODE}\"</script>$1\"\n}\n\n" +
+		"// Config file injection declaration for docker volume flags\nFile :: {\n" +
+		"  $comment?: string\n  name:      string                 // file name ma" +
+		"tching pattern\n  moveTo?:   string                 // move the file to " +
+		"other location\n  required?: bool                   // is this file requ" +
+		"ired? (default: false)\n  default?:  string                 // default f" +
+		"ile if no file match\n  rewrite?:  [...Rewrite] | Rewrite // file rewrit" +
+		"e patterns\n}\n\nHTTPHeader :: =~ \"^[a-zA-Z-]+:\"\n\n// HTTP status cod" +
+		"e like 200, \"2xx\", \"200-204\"\nHTTPStatus :: int | =~ \"^[1-5](\\\\d\\" +
+		"\\d|xx)(-[1-5]\\\\d\\\\d)?$\"\n\n// Backoff of dependency check interval" +
+		"s\n// delay between attempts starts from initial and is multiplied by mu" +
+		"ltiplier up to max.\n// jitter shortens each delay randomly by this rati" +
+		"o (0.0 - 1.0).\nBackoff :: {\n  initial?:   float64         // initial d" +
+		"elay seconds (default: interval)\n  initial?:   > 0.01\n  max?:       fl" +
+		"oat64         // max delay seconds\n  max?:       > 0.01\n  multiplier: " +
+		"*2 | float64\n  multiplier: >= 1\n  jitter:     *0.2 | float64\n  jitter" +
+		":     >= 0 & <= 1\n}\n\n// TLS setting to access other services\n// file" +
+		" paths and serverName can contain envvars like ${CERT_DIR}\nTLS :: {\n  " +
+		"ca?:                string        // CA certificate file (PEM) to verify" +
+		" server\n  cert?:              string        // client certificate file " +
+		"(PEM)\n  key?:               string        // client private key file (P" +
+		"EM)\n  serverName?:        string        // server name for SNI and veri" +
+		"fication\n  insecureSkipVerify: *false | true // skip server certificate" +
+		" verification\n  warnExpiry?:        number        // show warning if se" +
+		"rver certificate expires within this days\n}\n\n// Wait for other servic" +
+		"es before launching command\n// It should have url, or anyOf that become" +
+		"s ready when one of alternatives becomes ready\nDependsOn :: {\n  $comme" +
+		"nt?: string\n  name?:         string                       // name to re" +
+		"fer from after\n  after?:        [...string]                  // start c" +
+		"hecking after these dependencies become ready\n  anyOf?:        [...Depe" +
+		"ndsOn]               // alternatives like primary and replica\n  // url " +
+		"should starts with file://, http://, https://, tcp://, unix://, tls://, " +
+		"postgres://, mysql://, redis://, grpc://, exec://, dns://, kafka://, nat" +
+		"s://, amqp://, udp://, ws://\n  url?:          =~ \"^((file)|(https?)|(t" +
+		"cp[46]?)|(unix)|(tls)|(postgres(ql)?)|(mysql)|(rediss?)|(grpcs?)|(exec)|" +
+		"(dns)|(kafka)|(nats)|(amqps?)|(udp[46]?)|(wss?))://.+\"\n  command?:    " +
+		"  [...string]                  // command and arguments that should exit" +
+		" with 0 (instead of url)\n  headers:       [...HTTPHeader]              " +
+		"// header when access to http server (metadata for grpc)\n  timeout:    " +
+		"   *10 | float64                // timeout seconds\n  timeout:       > 0" +
+		".01\n  interval:      *1 | float64                 // check intervals\n " +
+		" interval:      > 0.01\n  attemptTimeout?: float64                    //" +
+		" timeout seconds of each attempt\n  attemptTimeout?: > 0.01\n  backoff?:" +
+		"      Backoff                      // exponential backoff of intervals\n" +
+		"  method?:       \"GET\" | \"HEAD\" | \"POST\" | \"OPTIONS\" // http met" +
+		"hod (default: HEAD, or GET if body conditions exist)\n  expectStatus?: [" +
+		"...HTTPStatus] | HTTPStatus // acceptable http status (default: \"2xx\")" +
+		"\n  bodyContains?: string                       // response body should " +
+		"contain this text\n  bodyRegexp?:   string                       // resp" +
+		"onse body should match this pattern\n  jsonPath?:     string            " +
+		"           // condition for JSON response like '$.status == \"UP\"'\n  t" +
+		"ls?:          TLS                          // TLS setting for https://, " +
+		"tls://, grpc:// and databases\n  user?:         string                  " +
+		"     // user name for databases (overwrites user info in url)\n  passwor" +
+		"d?:     string                       // password for databases (overwrit" +
+		"es user info in url)\n  query?:        string                       // p" +
+		"robe query for databases like \"SELECT 1\"\n  resolver?:     string     " +
+		"                  // DNS server (\"host:port\") for dns://\n  send?:    " +
+		"     string                       // payload to send to udp:// (can cont" +
+		"ain environment variables)\n  expect?:       string                     " +
+		"  // udp:// response should contain this text\n  contains?:     string  " +
+		"                     // file:// content should contain this text\n  rege" +
+		"xp?:       string                       // file:// content should match " +
+		"this pattern\n  minSize?:      int                          // minimum f" +
+		"ile size in bytes for file://\n  minSize?:      >= 0\n  maxAge?:       f" +
+		"loat64                      // file:// should be modified within this se" +
+		"conds\n  maxAge?:       > 0\n  notExists:     *false | true             " +
+		"   // wait until file:// doesn't exist (like lock file)\n  monitor:     " +
+		"  *false | true                // keep checking while the command runs\n" +
+		"  critical:      *false | true                // apply dependencyMonitor" +
+		".policy when it is down\n}\n\n// Behavior of monitoring dependencies whi" +
+		"le the command runs\nDependencyMonitor :: {\n  interval:    *5 | float64" +
+		"                         // check interval seconds\n  interval:    > 0.0" +
+		"1\n  threshold:   *30 | float64                        // seconds to app" +
+		"ly policy after critical dependency is down\n  policy:      *\"none\" | " +
+		"\"stop\" | \"restart\"         // action to the command when critical de" +
+		"pendency is down\n  gracePeriod: *10 | float64                        //" +
+		" seconds to wait after SIGTERM before SIGKILL\n}\n\n// Health checking p" +
+		"ort\nHealthCheck :: {\n  $comment?: string\n  statsInterval: *3 | float6" +
+		"4         // interval seconds of checking CPU/Memory stats\n  interval: " +
+		"     *10 | float64        // interval seconds of updating stats\n  url?:" +
+		"          string | [...string] // check other services\n}\n\n// Command " +
+		"or HTTP call that runs at a point of the command's lifecycle\nHook :: {\n" +
+		"  command?: [...string]                          // command and argument" +
+		"s (can contain environment variables)\n  url?:     =~ \"^https?://.+\"  " +
+		"                  // URL to call instead of command\n  method:   *\"GET\"" +
+		" | \"POST\" | \"PUT\" | \"DELETE\"   // http method\n  timeout:  *10 | f" +
+		"loat64                        // timeout seconds\n  timeout:  > 0.01\n}\n" +
+		"\n// Hook that runs before the command starts or after it exits\nLifecyc" +
+		"leHook :: {\n  name?:         string                          // name to" +
+		" tag output logs (default: preStart#1, postExit#1, ...)\n  command?:    " +
+		"  [...string]                     // command and arguments (can contain " +
+		"environment variables)\n  url?:          =~ \"^https?://.+\"            " +
+		"   // URL to call instead of command\n  method:        *\"GET\" | \"POST" +
+		"\" | \"PUT\" | \"DELETE\" // http method\n  timeout:       *60 | float64" +
+		"                   // timeout seconds\n  timeout:       > 0.01\n  failur" +
+		"ePolicy: *\"fail\" | \"ignore\"              // \"fail\" stops docradle " +
+		"when the hook fails\n}\n\n// Commands like migration before the command " +
+		"starts and cleanup after it exits\nHooks :: {\n  preStart?: [...Lifecycl" +
+		"eHook] // run in order before the command starts (only once even if the " +
+		"command restarts)\n  postExit?: [...LifecycleHook] // run in order after" +
+		" the command exits (including stopped by signal)\n}\n\n// Resource limit" +
+		": number (both soft and hard limit), \"unlimited\" or \"soft:hard\" like" +
+		" \"1024:4096\"\nLimit :: int | =~\"^(unlimited|[0-9]+)(:(unlimited|[0-9]" +
+		"+))?$\"\n\n// Resource limits of the command (setrlimit)\nLimits :: {\n " +
+		" nofile?:  Limit // max number of open files\n  nproc?:   Limit // max n" +
+		"umber of processes of the user\n  core?:    Limit // max core file size " +
+		"in bytes\n  memlock?: Limit // max locked memory in bytes\n  stack?:   L" +
+		"imit // max stack size in bytes\n}\n\n// Process exit behavior\nProcess " +
+		":: {\n  $comment?: string\n  noticeExitHttp?:   string // URL to POST JS" +
+		"ON exit report when the command exits\n  noticeExitSlack?:  string // In" +
+		"coming webhook URL to send exit information\n  noticeExitPubSub?: string" +
+		" // gocloud pubsub URL to send exit report (eg: kafka://topic, mem://top" +
+		"ic)\n  rerun?:            bool   // Deprecated: same as restart: \"alway" +
+		"s\"\n  restart:           *\"no\" | \"on-failure\" | \"always\" // Resta" +
+		"rt the command when it exits (not when stopped by signal)\n  maxRestarts" +
+		":       *0 | int // Give up after this number of consecutive restarts (0" +
+		": unlimited)\n  maxRestarts:       >= 0\n  restartBackoff?:   Backoff //" +
+		" Delay before restarts (default: initial 1 second, max 60 seconds)\n  re" +
+		"startWindow:     *60 | float64 // Restart count and delay are reset if t" +
+		"he command runs longer than this seconds\n  restartWindow:     > 0.01\n " +
+		" logBucket?:        string // Upload output of the command to blob when " +
+		"it exits (eg: s3://bucket, gs://bucket, file:///var/log)\n  logKey:     " +
+		"       *\"{{.Hostname}}/{{.StartTime}}.log.gz\" | string // Object key t" +
+		"emplate of uploaded log\n  signalRewrite?:    [string]: string // Conver" +
+		"t forwarded signal like {\"TERM\": \"QUIT\"}\n  stopSignal?:       strin" +
+		"g // Signal to stop the command (default: received signal, or TERM)\n  s" +
+		"topTimeout:       *10 | float64 // Seconds to wait after stop signal bef" +
+		"ore killing the process group\n  stopTimeout:       > 0.01\n  preStop?: " +
+		"         Hook   // Run before sending stop signal\n  user?:             " +
+		"string // Run the command as this user (name or uid)\n  group?:         " +
+		"   string // Run the command as this group (name or gid, default: groups" +
+		" of the user)\n  limits?:           Limits // Resource limits applied be" +
+		"fore the command starts\n  nice?:             int    // Niceness of the " +
+		"command (-20: highest priority, 19: lowest priority)\n  nice?:          " +
+		"   >= -20 & <= 19\n  oomScoreAdj?:      int    // oom_score_adj of the c" +
+		"ommand (Linux only)\n  oomScoreAdj?:      >= -1000 & <= 1000\n  timeout?" +
+		":          float64 // Stop the command gracefully after this seconds fro" +
+		"m the first start (for batch jobs)\n  timeout?:          > 0.01\n  watch" +
+		"dog?:         float64 // Kill the command if it doesn't write output for" +
+		" this seconds\n  watchdog?:         > 0.01\n}\n\n// Command that docradl" +
+		"e supervises with the main command (like metrics exporter and log shippe" +
+		"r)\nProcessEntry :: {\n  $comment?: string\n  name:       =~ \"^[a-zA-Z0" +
+		"-9_.-]+$\"             // name used as \"process\" tag of logs\n  comman" +
+		"d:    [...string]                         // command and arguments (can " +
+		"contain environment variables)\n  stdout:     Log\n  stderr:     Log\n  " +
+		"stdout: defaultLevel: \"trace\" | \"debug\" | *\"info\" | \"warn\" | \"e" +
+		"rror\"\n  stderr: defaultLevel: \"trace\" | \"debug\" | \"info\" | \"war" +
+		"n\" | *\"error\"\n  process:    Process                             // r" +
+		"estart policy, stop signal, notifications and so on\n  dependsOn?: [...D" +
+		"ependsOn] | DependsOn          // wait for them before starting this pro" +
+		"cess\n  onExit:     *\"stop\" | \"none\"                    // \"stop\" " +
+		"stops other processes when this process finishes\n}\n\n// Logging config" +
+		"\nLog :: {\n  $comment?: string\n  defaultLevel:  string\n  structured: " +
+		"   *true | false\n  exportConfig?: string\n  exportHost?:   string\n  pa" +
+		"ssThrough:   *true | false\n  mask?:         string | [...string]\n  tag" +
+		"s?:         [string]: string\n}\n\n$comment?:      string\n// dashboard " +
+		"web service port\n// dashboardPort?: uint16\n// debugger     port for go" +
+		"\n// delvePort?:     uint16\nenv?:           [...Env]\nfile?:          [" +
+		"...File] | File\ndependsOn?:     [...DependsOn] | DependsOn\ndependsOnTi" +
+		"meout?: float64 // overall timeout seconds of waiting for all dependenci" +
+		"es\ndependsOnTimeout?: > 0.01\ndependencyMonitor: DependencyMonitor\nstd" +
+		"out:         Log\nstderr:         Log\nlogLevel:       \"trace\" | \"deb" +
+		"ug\" | *\"info\" | \"warn\" | \"error\"\nstdout: defaultLevel: \"trace\"" +
+		" | \"debug\" | *\"info\" | \"warn\" | \"error\"\nstderr: defaultLevel: \"" +
+		"trace\" | \"debug\" | \"info\" | \"warn\" | *\"error\"\nprocess:        " +
+		"Process\nhooks:          Hooks\nprocesses?:     [...ProcessEntry] // sta" +
+		"rted in order before the main command\n// healthCheck?:   HealthCheck\n\n" +
+		"// version number. you can specify via envvar(${ENVVAR}), other file(@fi" +
+		"lename)\nversion?: string\n// author name of this configuration\nauthor?" +
+		": string\n\x03PK\x01\x02\x14\x03\x14\x00\x00\x00\x00\x00+\xb5R]\xc1\xe2x" +
+		"\x98\xbc\x8e\x00\x00\xbc\x8e\x00\x00\x10\x00\x00\x00 \x00\x00\x00\x00\x00" +
+		"\x00\x00\xa4\x81\x00\x00\x00\x00json-schema.jsonb,8eb8-6ad54b12,applicat" +
+		"ion/jsonPK\x01\x02\x14\x03\x14\x00\x00\x00\x00\x00Xj6P\x93\x07h6\xba\x06" +
+		"\x00\x00\xba\x06\x00\x00\x0b\x00\x00\x00\x1f\x00\x00\x00\x00\x00\x00\x00" +
+		"\xa4\x81\xea\x8e\x00\x00sample.jsonb,6b6-5e284bb8,application/jsonPK\x01" +
+		"\x02\x14\x03\x14\x00\x00\x00\x00\x00\xf8\xb4R]\x0c\xc94(\x941\x00\x00\x94" +
+		"1\x00\x00\n\x00\x00\x00\x1a\x00\x00\x00\x00\x00\x00\x00\xa4\x81\xcd\x95\x00" +
+		"\x00schema.cueb,3190-6ad54ab4,text/plainPK\x05\x06\x00\x00\x00\x00\x03\x00" +
+		"\x03\x00\x08\x01\x00\x00\x89\xc7\x00\x00\x00\x00")
 
 func init() {
 	brbundle.RegisterEmbeddedBundle(bundle_f1bcb9c9bc167c664d6b397fdd3de634, "")
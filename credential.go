@@ -0,0 +1,162 @@
+package docradle
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// credential is the user and groups to run the command as (like gosu and su-exec)
+type credential struct {
+	uid    uint32
+	gid    uint32
+	groups []uint32
+	home   string
+}
+
+// sysCredential returns credential to set to exec.Cmd
+//
+// Supplementary groups are kept if docradle doesn't run as root because it can't change them.
+func (c *credential) sysCredential() *syscall.Credential {
+	if c == nil {
+		return nil
+	}
+	return &syscall.Credential{
+		Uid:         c.uid,
+		Gid:         c.gid,
+		Groups:      c.groups,
+		NoSetGroups: os.Getuid() != 0,
+	}
+}
+
+// applyTo makes the command run as the user with its HOME. cmd.Env should be set before.
+func (c *credential) applyTo(cmd *exec.Cmd) {
+	if c == nil {
+		return
+	}
+	cmd.Env = withHome(cmd.Env, c.home)
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Credential = c.sysCredential()
+}
+
+// chown changes the owner of the file written for the command
+func (c *credential) chown(path string) error {
+	if c == nil {
+		return nil
+	}
+	return os.Chown(path, int(c.uid), int(c.gid))
+}
+
+// splitUserSpec splits --user flag like "app", "app:app" or "1000:1000"
+func splitUserSpec(spec string) (userName, groupName string) {
+	parts := strings.SplitN(spec, ":", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return parts[0], ""
+}
+
+// lookupCredential resolves process.user and process.group (names or IDs)
+//
+// It returns nil if both are empty. Supplementary groups of the user are used unless group is specified.
+// Numeric user ID that doesn't exist in /etc/passwd runs with group 0 and HOME=/ like gosu.
+func lookupCredential(userName, groupName string) (*credential, error) {
+	if userName == "" && groupName == "" {
+		return nil, nil
+	}
+	var u *user.User
+	var err error
+	if userName == "" {
+		u, err = user.Current()
+	} else {
+		u, err = lookupUser(userName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("user '%s' has invalid uid '%s'", userName, u.Uid)
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("user '%s' has invalid gid '%s'", userName, u.Gid)
+	}
+	result := &credential{
+		uid:  uint32(uid),
+		gid:  uint32(gid),
+		home: u.HomeDir,
+	}
+	if result.home == "" {
+		result.home = "/"
+	}
+	if groupName != "" {
+		g, err := lookupGroup(groupName)
+		if err != nil {
+			return nil, err
+		}
+		result.gid = g
+		result.groups = []uint32{g}
+		return result, nil
+	}
+	result.groups = []uint32{result.gid}
+	if groupIDs, err := u.GroupIds(); err == nil {
+		for _, groupID := range groupIDs {
+			id, err := strconv.ParseUint(groupID, 10, 32)
+			if err == nil && uint32(id) != result.gid {
+				result.groups = append(result.groups, uint32(id))
+			}
+		}
+	}
+	return result, nil
+}
+
+func lookupUser(userName string) (*user.User, error) {
+	if _, err := strconv.ParseUint(userName, 10, 32); err == nil {
+		u, err := user.LookupId(userName)
+		if err == nil {
+			return u, nil
+		}
+		if _, ok := err.(user.UnknownUserIdError); ok {
+			return &user.User{Uid: userName, Gid: "0", HomeDir: "/"}, nil
+		}
+		return nil, fmt.Errorf("can't find user '%s': %w", userName, err)
+	}
+	u, err := user.Lookup(userName)
+	if err != nil {
+		return nil, fmt.Errorf("can't find user '%s': %w", userName, err)
+	}
+	return u, nil
+}
+
+func lookupGroup(groupName string) (uint32, error) {
+	if id, err := strconv.ParseUint(groupName, 10, 32); err == nil {
+		return uint32(id), nil
+	}
+	g, err := user.LookupGroup(groupName)
+	if err != nil {
+		return 0, fmt.Errorf("can't find group '%s': %w", groupName, err)
+	}
+	id, err := strconv.ParseUint(g.Gid, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("group '%s' has invalid gid '%s'", groupName, g.Gid)
+	}
+	return uint32(id), nil
+}
+
+// withHome replaces HOME environment variable for the user
+func withHome(envs []string, home string) []string {
+	result := make([]string, 0, len(envs)+1)
+	for _, env := range envs {
+		if !strings.HasPrefix(env, "HOME=") {
+			result = append(result, env)
+		}
+	}
+	return append(result, "HOME="+home)
+}
@@ -0,0 +1,132 @@
+package docradle
+
+import (
+	"io/ioutil"
+	"os"
+	"os/user"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitUserSpec(t *testing.T) {
+	testcases := []struct {
+		spec  string
+		user  string
+		group string
+	}{
+		{spec: "app", user: "app"},
+		{spec: "app:staff", user: "app", group: "staff"},
+		{spec: "1000:1000", user: "1000", group: "1000"},
+		{spec: ":staff", group: "staff"},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.spec, func(t *testing.T) {
+			userName, groupName := splitUserSpec(tt.spec)
+			assert.Equal(t, tt.user, userName)
+			assert.Equal(t, tt.group, groupName)
+		})
+	}
+}
+
+func TestLookupCredential(t *testing.T) {
+	root, err := user.LookupId("0")
+	if err != nil {
+		t.Skip("root user is not found")
+	}
+	testcases := []struct {
+		name     string
+		user     string
+		group    string
+		uid      uint32
+		gid      uint32
+		groups   []uint32
+		home     string
+		hasError bool
+	}{
+		{
+			name: "user name",
+			user: root.Username,
+			home: root.HomeDir,
+		},
+		{
+			name: "uid",
+			user: "0",
+			home: root.HomeDir,
+		},
+		{
+			name:   "uid and gid",
+			user:   "0",
+			group:  "1234",
+			gid:    1234,
+			groups: []uint32{1234},
+			home:   root.HomeDir,
+		},
+		{
+			name:   "unknown uid",
+			user:   "54321",
+			uid:    54321,
+			groups: []uint32{0},
+			home:   "/",
+		},
+		{
+			name:     "unknown user name",
+			user:     "docradle-unknown-user",
+			hasError: true,
+		},
+		{
+			name:     "unknown group name",
+			user:     "0",
+			group:    "docradle-unknown-group",
+			hasError: true,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := lookupCredential(tt.user, tt.group)
+			if tt.hasError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.uid, c.uid)
+			assert.Equal(t, tt.gid, c.gid)
+			if tt.groups != nil {
+				assert.Equal(t, tt.groups, c.groups)
+			} else {
+				assert.Equal(t, tt.gid, c.groups[0])
+			}
+			assert.Equal(t, tt.home, c.home)
+		})
+	}
+}
+
+func TestLookupCredential_Empty(t *testing.T) {
+	c, err := lookupCredential("", "")
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+	assert.Nil(t, c.sysCredential())
+	assert.NoError(t, c.chown("not-exist"))
+}
+
+func TestCredential_Chown(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("chown to other user requires root privilege")
+	}
+	file, err := ioutil.TempFile("", "docradle-chown")
+	assert.NoError(t, err)
+	file.Close()
+	defer os.Remove(file.Name())
+
+	c := &credential{uid: 65534, gid: 65534}
+	assert.NoError(t, c.chown(file.Name()))
+	stat, err := os.Stat(file.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(65534), stat.Sys().(*syscall.Stat_t).Uid)
+}
+
+func TestWithHome(t *testing.T) {
+	envs := withHome([]string{"PATH=/bin", "HOME=/root"}, "/home/app")
+	assert.Equal(t, []string{"PATH=/bin", "HOME=/home/app"}, envs)
+}
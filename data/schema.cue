@@ -30,16 +30,82 @@ File :: {
 
 HTTPHeader :: =~ "^[a-zA-Z-]+:"
 
+// HTTP status code like 200, "2xx", "200-204"
+HTTPStatus :: int | =~ "^[1-5](\\d\\d|xx)(-[1-5]\\d\\d)?$"
+
+// Backoff of dependency check intervals
+// delay between attempts starts from initial and is multiplied by multiplier up to max.
+// jitter shortens each delay randomly by this ratio (0.0 - 1.0).
+Backoff :: {
+  initial?:   float64         // initial delay seconds (default: interval)
+  initial?:   > 0.01
+  max?:       float64         // max delay seconds
+  max?:       > 0.01
+  multiplier: *2 | float64
+  multiplier: >= 1
+  jitter:     *0.2 | float64
+  jitter:     >= 0 & <= 1
+}
+
+// TLS setting to access other services
+// file paths and serverName can contain envvars like ${CERT_DIR}
+TLS :: {
+  ca?:                string        // CA certificate file (PEM) to verify server
+  cert?:              string        // client certificate file (PEM)
+  key?:               string        // client private key file (PEM)
+  serverName?:        string        // server name for SNI and verification
+  insecureSkipVerify: *false | true // skip server certificate verification
+  warnExpiry?:        number        // show warning if server certificate expires within this days
+}
+
 // Wait for other services before launching command
+// It should have url, or anyOf that becomes ready when one of alternatives becomes ready
 DependsOn :: {
   $comment?: string
-  // url should starts with tcp://, udp://, http://, https://
-  url:      =~ "^((file)|(https?)|(tcp[46]?)|(unix))://[a-z][\\w]*(:\\d+)?"
-  headers:  [...HTTPHeader]         // header when access to http server
-  timeout:  *10 | float64           // timeout seconds
-  timeout:  > 0.01
-  interval: *1 | float64            // check intervals
-  interval: > 0.01
+  name?:         string                       // name to refer from after
+  after?:        [...string]                  // start checking after these dependencies become ready
+  anyOf?:        [...DependsOn]               // alternatives like primary and replica
+  // url should starts with file://, http://, https://, tcp://, unix://, tls://, postgres://, mysql://, redis://, grpc://, exec://, dns://, kafka://, nats://, amqp://, udp://, ws://
+  url?:          =~ "^((file)|(https?)|(tcp[46]?)|(unix)|(tls)|(postgres(ql)?)|(mysql)|(rediss?)|(grpcs?)|(exec)|(dns)|(kafka)|(nats)|(amqps?)|(udp[46]?)|(wss?))://.+"
+  command?:      [...string]                  // command and arguments that should exit with 0 (instead of url)
+  headers:       [...HTTPHeader]              // header when access to http server (metadata for grpc)
+  timeout:       *10 | float64                // timeout seconds
+  timeout:       > 0.01
+  interval:      *1 | float64                 // check intervals
+  interval:      > 0.01
+  attemptTimeout?: float64                    // timeout seconds of each attempt
+  attemptTimeout?: > 0.01
+  backoff?:      Backoff                      // exponential backoff of intervals
+  method?:       "GET" | "HEAD" | "POST" | "OPTIONS" // http method (default: HEAD, or GET if body conditions exist)
+  expectStatus?: [...HTTPStatus] | HTTPStatus // acceptable http status (default: "2xx")
+  bodyContains?: string                       // response body should contain this text
+  bodyRegexp?:   string                       // response body should match this pattern
+  jsonPath?:     string                       // condition for JSON response like '$.status == "UP"'
+  tls?:          TLS                          // TLS setting for https://, tls://, grpc:// and databases
+  user?:         string                       // user name for databases (overwrites user info in url)
+  password?:     string                       // password for databases (overwrites user info in url)
+  query?:        string                       // probe query for databases like "SELECT 1"
+  resolver?:     string                       // DNS server ("host:port") for dns://
+  send?:         string                       // payload to send to udp:// (can contain environment variables)
+  expect?:       string                       // udp:// response should contain this text
+  contains?:     string                       // file:// content should contain this text
+  regexp?:       string                       // file:// content should match this pattern
+  minSize?:      int                          // minimum file size in bytes for file://
+  minSize?:      >= 0
+  maxAge?:       float64                      // file:// should be modified within this seconds
+  maxAge?:       > 0
+  notExists:     *false | true                // wait until file:// doesn't exist (like lock file)
+  monitor:       *false | true                // keep checking while the command runs
+  critical:      *false | true                // apply dependencyMonitor.policy when it is down
+}
+
+// Behavior of monitoring dependencies while the command runs
+DependencyMonitor :: {
+  interval:    *5 | float64                         // check interval seconds
+  interval:    > 0.01
+  threshold:   *30 | float64                        // seconds to apply policy after critical dependency is down
+  policy:      *"none" | "stop" | "restart"         // action to the command when critical dependency is down
+  gracePeriod: *10 | float64                        // seconds to wait after SIGTERM before SIGKILL
 }
 
 // Health checking port
@@ -50,14 +116,89 @@ HealthCheck :: {
   url?:          string | [...string] // check other services
 }
 
+// Command or HTTP call that runs at a point of the command's lifecycle
+Hook :: {
+  command?: [...string]                          // command and arguments (can contain environment variables)
+  url?:     =~ "^https?://.+"                    // URL to call instead of command
+  method:   *"GET" | "POST" | "PUT" | "DELETE"   // http method
+  timeout:  *10 | float64                        // timeout seconds
+  timeout:  > 0.01
+}
+
+// Hook that runs before the command starts or after it exits
+LifecycleHook :: {
+  name?:         string                          // name to tag output logs (default: preStart#1, postExit#1, ...)
+  command?:      [...string]                     // command and arguments (can contain environment variables)
+  url?:          =~ "^https?://.+"               // URL to call instead of command
+  method:        *"GET" | "POST" | "PUT" | "DELETE" // http method
+  timeout:       *60 | float64                   // timeout seconds
+  timeout:       > 0.01
+  failurePolicy: *"fail" | "ignore"              // "fail" stops docradle when the hook fails
+}
+
+// Commands like migration before the command starts and cleanup after it exits
+Hooks :: {
+  preStart?: [...LifecycleHook] // run in order before the command starts (only once even if the command restarts)
+  postExit?: [...LifecycleHook] // run in order after the command exits (including stopped by signal)
+}
+
+// Resource limit: number (both soft and hard limit), "unlimited" or "soft:hard" like "1024:4096"
+Limit :: int | =~"^(unlimited|[0-9]+)(:(unlimited|[0-9]+))?$"
+
+// Resource limits of the command (setrlimit)
+Limits :: {
+  nofile?:  Limit // max number of open files
+  nproc?:   Limit // max number of processes of the user
+  core?:    Limit // max core file size in bytes
+  memlock?: Limit // max locked memory in bytes
+  stack?:   Limit // max stack size in bytes
+}
+
 // Process exit behavior
 Process :: {
   $comment?: string
-  noticeExitHttp?:   string // Send back notification when process closed
+  noticeExitHttp?:   string // URL to POST JSON exit report when the command exits
   noticeExitSlack?:  string // Incoming webhook URL to send exit information
-  noticeExitPubSub?: string // Send back notification to pub sub
-  rerun?:            bool   // Rerun process when process is closed
-  logBucket?:        string // Upload log files to blob (eg: s3://bucket, gcs://bucket)
+  noticeExitPubSub?: string // gocloud pubsub URL to send exit report (eg: kafka://topic, mem://topic)
+  rerun?:            bool   // Deprecated: same as restart: "always"
+  restart:           *"no" | "on-failure" | "always" // Restart the command when it exits (not when stopped by signal)
+  maxRestarts:       *0 | int // Give up after this number of consecutive restarts (0: unlimited)
+  maxRestarts:       >= 0
+  restartBackoff?:   Backoff // Delay before restarts (default: initial 1 second, max 60 seconds)
+  restartWindow:     *60 | float64 // Restart count and delay are reset if the command runs longer than this seconds
+  restartWindow:     > 0.01
+  logBucket?:        string // Upload output of the command to blob when it exits (eg: s3://bucket, gs://bucket, file:///var/log)
+  logKey:            *"{{.Hostname}}/{{.StartTime}}.log.gz" | string // Object key template of uploaded log
+  signalRewrite?:    [string]: string // Convert forwarded signal like {"TERM": "QUIT"}
+  stopSignal?:       string // Signal to stop the command (default: received signal, or TERM)
+  stopTimeout:       *10 | float64 // Seconds to wait after stop signal before killing the process group
+  stopTimeout:       > 0.01
+  preStop?:          Hook   // Run before sending stop signal
+  user?:             string // Run the command as this user (name or uid)
+  group?:            string // Run the command as this group (name or gid, default: groups of the user)
+  limits?:           Limits // Resource limits applied before the command starts
+  nice?:             int    // Niceness of the command (-20: highest priority, 19: lowest priority)
+  nice?:             >= -20 & <= 19
+  oomScoreAdj?:      int    // oom_score_adj of the command (Linux only)
+  oomScoreAdj?:      >= -1000 & <= 1000
+  timeout?:          float64 // Stop the command gracefully after this seconds from the first start (for batch jobs)
+  timeout?:          > 0.01
+  watchdog?:         float64 // Kill the command if it doesn't write output for this seconds
+  watchdog?:         > 0.01
+}
+
+// Command that docradle supervises with the main command (like metrics exporter and log shipper)
+ProcessEntry :: {
+  $comment?: string
+  name:       =~ "^[a-zA-Z0-9_.-]+$"             // name used as "process" tag of logs
+  command:    [...string]                         // command and arguments (can contain environment variables)
+  stdout:     Log
+  stderr:     Log
+  stdout: defaultLevel: "trace" | "debug" | *"info" | "warn" | "error"
+  stderr: defaultLevel: "trace" | "debug" | "info" | "warn" | *"error"
+  process:    Process                             // restart policy, stop signal, notifications and so on
+  dependsOn?: [...DependsOn] | DependsOn          // wait for them before starting this process
+  onExit:     *"stop" | "none"                    // "stop" stops other processes when this process finishes
 }
 
 // Logging config
@@ -80,12 +221,17 @@ $comment?:      string
 env?:           [...Env]
 file?:          [...File] | File
 dependsOn?:     [...DependsOn] | DependsOn
+dependsOnTimeout?: float64 // overall timeout seconds of waiting for all dependencies
+dependsOnTimeout?: > 0.01
+dependencyMonitor: DependencyMonitor
 stdout:         Log
 stderr:         Log
 logLevel:       "trace" | "debug" | *"info" | "warn" | "error"
 stdout: defaultLevel: "trace" | "debug" | *"info" | "warn" | "error"
 stderr: defaultLevel: "trace" | "debug" | "info" | "warn" | *"error"
-// process:        Process
+process:        Process
+hooks:          Hooks
+processes?:     [...ProcessEntry] // started in order before the main command
 // healthCheck?:   HealthCheck
 
 // version number. you can specify via envvar(${ENVVAR}), other file(@filename)
@@ -1,47 +1,137 @@
 package docradle
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
 	"net"
 	"net/http"
 	"net/url"
-	"os"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/gookit/color"
 	"golang.org/x/sync/errgroup"
 )
 
+// maxResponseBodySize is a limit of response body size to check body conditions
+const maxResponseBodySize = 1024 * 1024
+
 // DependsOnCheckResult is a collection of dependency check
 type DependsOnCheckResult struct {
-	url      *url.URL
-	headers  [][2]string
-	timeout  time.Duration
-	interval time.Duration
-	duration time.Duration
-	error    error
+	name         string
+	after        []string
+	depth        int
+	url          *url.URL
+	headers      [][2]string
+	timeout      time.Duration
+	interval     time.Duration
+	duration     time.Duration
+	warnings     []string
+	resolved     []string
+	attempts     int
+	lastError    error
+	alternatives []DependsOnCheckResult
+	error        error
 }
 
 func (r DependsOnCheckResult) String() string {
 	var builder strings.Builder
-	builder.WriteString("  ")
+	// dependencies that wait for others are indented under them
+	indent := strings.Repeat("  ", r.depth)
+	builder.WriteString(indent + "  ")
 	if r.Error() == nil {
 		builder.WriteString("<bg=black;fg=green;op=reverse;>OK</> ")
 	} else {
 		builder.WriteString("<bg=black;fg=red;op=reverse;>NG</> ")
 	}
-	builder.WriteString("<blue>" + r.url.String() + "</> ")
+	builder.WriteString("<blue>" + r.label() + "</> ")
+	if len(r.after) > 0 {
+		builder.WriteString("<gray>(after " + strings.Join(r.after, ", ") + ")</> ")
+	}
+	var skipped *skippedError
+	if r.alternatives == nil || errors.As(r.error, &skipped) {
+		builder.WriteString(r.status())
+		if len(r.resolved) > 0 {
+			builder.WriteString("\n" + indent + "      <gray>resolved: " + strings.Join(r.resolved, ", ") + "</>")
+		}
+	} else {
+		if r.Error() == nil {
+			builder.WriteString("<gray>(wait " + r.duration.String() + ")</>")
+		} else {
+			builder.WriteString("<red>None of alternatives is ready</>")
+		}
+		for _, alternative := range r.alternatives {
+			if alternative.Error() == nil {
+				builder.WriteString("\n" + indent + "      <green>✔</> ")
+			} else if errors.Is(alternative.error, context.Canceled) {
+				builder.WriteString("\n" + indent + "      <gray>-</> ")
+			} else {
+				builder.WriteString("\n" + indent + "      <red>✘</> ")
+			}
+			builder.WriteString("<blue>" + displayURL(alternative.url) + "</> " + alternative.status())
+		}
+	}
+	for _, warning := range r.warnings {
+		builder.WriteString("\n" + indent + "      <yellow>... warning: " + warning + "</>")
+	}
+	return builder.String()
+}
+
+func (r DependsOnCheckResult) label() string {
+	target := "anyOf"
+	if r.url != nil {
+		target = displayURL(r.url)
+	}
+	if r.name != "" {
+		return r.name + ": " + target
+	}
+	return target
+}
+
+// status returns the description of the check result after URL
+func (r DependsOnCheckResult) status() string {
+	var skipped *skippedError
+	var expired *expiredError
 	if r.Error() == nil {
-		builder.WriteString("<gray>(wait " + r.duration.String() + ")</>")
+		return r.waitText(r.duration, false)
+	} else if errors.As(r.error, &skipped) {
+		return "<yellow>Skipped because '" + skipped.after + "' is not ready</>"
+	} else if errors.As(r.error, &expired) {
+		if r.attempts == 0 {
+			return "<yellow>Not checked because dependsOnTimeout expired</>" + r.waitText(r.duration, false)
+		}
+		return "<red>Target service is not ready until dependsOnTimeout expired</>" + r.waitText(r.duration, true)
+	} else if errors.Is(r.error, context.Canceled) {
+		return "<gray>(canceled)</>"
 	} else if errors.Is(r.error, context.DeadlineExceeded) {
-		builder.WriteString("<red>Target service doesn't exist</>")
-		builder.WriteString("<gray>(wait " + r.timeout.String() + ")</>")
-	} else {
-		builder.WriteString("<red>Error occured: " + r.error.Error() + "</>")
+		var unsatisfied *unsatisfiedError
+		if errors.As(r.error, &unsatisfied) {
+			return "<red>Target service is not ready: " + unsatisfied.reason.Error() + "</>" + r.waitText(r.timeout, false)
+		}
+		return "<red>Target service doesn't exist</>" + r.waitText(r.timeout, true)
+	}
+	return "<red>Error occured: " + r.error.Error() + "</>"
+}
+
+// waitText returns text like "(wait 3s, 4 attempts, last error: connection refused)"
+func (r DependsOnCheckResult) waitText(duration time.Duration, withLastError bool) string {
+	var builder strings.Builder
+	builder.WriteString("<gray>(wait " + duration.String())
+	if r.attempts == 1 {
+		builder.WriteString(", 1 attempt")
+	} else if r.attempts > 1 {
+		builder.WriteString(", " + strconv.Itoa(r.attempts) + " attempts")
 	}
+	if withLastError && r.lastError != nil {
+		builder.WriteString(", last error: " + r.lastError.Error())
+	}
+	builder.WriteString(")</>")
 	return builder.String()
 }
 
@@ -49,6 +139,233 @@ func (r DependsOnCheckResult) Error() error {
 	return r.error
 }
 
+// unsatisfiedError is returned when the target service responds but doesn't satisfy conditions until timeout
+type unsatisfiedError struct {
+	url    *url.URL
+	reason error
+	cause  error
+}
+
+func (e *unsatisfiedError) Error() string {
+	return fmt.Sprintf("timeout for checking server, '%s' (last response: %s): %v", displayURL(e.url), e.reason.Error(), e.cause)
+}
+
+func (e *unsatisfiedError) Unwrap() error {
+	return e.cause
+}
+
+// skippedError is returned when the dependency in "after" is not ready
+type skippedError struct {
+	after string
+}
+
+func (e *skippedError) Error() string {
+	return fmt.Sprintf("skipped because '%s' is not ready", e.after)
+}
+
+// expiredError is returned when dependsOnTimeout (the whole waiting time) expires before the dependency is ready
+//
+// The dependency's own timeout doesn't expire yet, and the dependency may not be checked if it waits for "after".
+type expiredError struct {
+	cause error
+}
+
+func (e *expiredError) Error() string {
+	return "dependsOnTimeout expired: " + e.cause.Error()
+}
+
+func (e *expiredError) Unwrap() error {
+	return e.cause
+}
+
+// markExpired replaces timeout errors of the result with expiredError if ctx is expired by dependsOnTimeout
+func markExpired(ctx context.Context, result *DependsOnCheckResult) {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return
+	}
+	for i := range result.alternatives {
+		markExpired(ctx, &result.alternatives[i])
+	}
+	if errors.Is(result.error, context.DeadlineExceeded) {
+		result.error = &expiredError{cause: result.error}
+	}
+}
+
+func timeoutError(dependsOn DependsOn, lastMismatch, cause error) error {
+	if lastMismatch != nil {
+		return &unsatisfiedError{
+			url:    dependsOn.URL,
+			reason: lastMismatch,
+			cause:  cause,
+		}
+	}
+	return fmt.Errorf("timeout for checking server, '%s': %w", displayURL(dependsOn.URL), cause)
+}
+
+// displayURL returns URL string without password
+func displayURL(u *url.URL) string {
+	if u.User == nil {
+		return u.String()
+	}
+	if _, ok := u.User.Password(); !ok {
+		return u.String()
+	}
+	masked := *u
+	masked.User = url.UserPassword(u.User.Username(), "xxxxx")
+	return masked.String()
+}
+
+// poller controls the timing of attempts of dependency checks
+//
+// It waits for interval between attempts. If backoff is configured, the delay grows
+// exponentially from backoff.initial up to backoff.max and is shortened randomly by jitter.
+// It also records the number of attempts and the last error to the result.
+type poller struct {
+	dependsOn DependsOn
+	result    *DependsOnCheckResult
+	delay     time.Duration
+}
+
+func newPoller(dependsOn DependsOn, result *DependsOnCheckResult) *poller {
+	p := &poller{
+		dependsOn: dependsOn,
+		result:    result,
+		delay:     dependsOn.Interval,
+	}
+	if dependsOn.Backoff != nil && dependsOn.Backoff.Initial > 0 {
+		p.delay = dependsOn.Backoff.Initial
+	}
+	return p
+}
+
+// attemptContext returns context for each attempt. It is limited by attemptTimeout if specified.
+func (p *poller) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.dependsOn.AttemptTimeout > 0 {
+		return context.WithTimeout(ctx, p.dependsOn.AttemptTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// record counts the attempt and keeps its error
+//
+// Errors after ctx is done are ignored because they are caused by the deadline.
+func (p *poller) record(ctx context.Context, err error) {
+	p.result.attempts++
+	if err != nil && ctx.Err() == nil {
+		p.result.lastError = err
+	}
+}
+
+// wait sleeps until the next attempt. It returns error if ctx is done before that.
+func (p *poller) wait(ctx context.Context) error {
+	timer := time.NewTimer(p.nextDelay())
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (p *poller) nextDelay() time.Duration {
+	delay := p.delay
+	backoff := p.dependsOn.Backoff
+	if backoff == nil {
+		return delay
+	}
+	if backoff.Jitter > 0 {
+		delay -= time.Duration(float64(delay) * backoff.Jitter * rand.Float64())
+	}
+	if backoff.Multiplier > 1 {
+		p.delay = time.Duration(float64(p.delay) * backoff.Multiplier)
+		if backoff.Max > 0 && p.delay > backoff.Max {
+			p.delay = backoff.Max
+		}
+	}
+	return delay
+}
+
+// pollUntilReady calls probe until it succeeds or timeout
+//
+// Errors from probe are kept to show the reason in timeout error,
+// except dial errors that mean the target service doesn't exist yet
+// and I/O timeouts of the probe interrupted by the deadline.
+func pollUntilReady(ctx context.Context, dependsOn DependsOn, result *DependsOnCheckResult, probe func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, dependsOn.Timeout)
+	defer cancel()
+	p := newPoller(dependsOn, result)
+	var lastMismatch error
+	for {
+		attemptCtx, attemptCancel := p.attemptContext(ctx)
+		err := probe(attemptCtx)
+		attemptCancel()
+		p.record(ctx, err)
+		if err == nil {
+			return nil
+		}
+		var opErr *net.OpError
+		var netErr net.Error
+		switch {
+		case ctx.Err() != nil:
+		case errors.As(err, &opErr) && opErr.Op == "dial":
+		case lastMismatch != nil && errors.As(err, &netErr) && netErr.Timeout():
+		default:
+			lastMismatch = err
+		}
+		if err := p.wait(ctx); err != nil {
+			if err == context.DeadlineExceeded {
+				return timeoutError(dependsOn, lastMismatch, err)
+			}
+			return err
+		}
+	}
+}
+
+// dialProbe connects to the target for protocol aware checks
+//
+// If tlsConfig is not nil, it starts TLS session just after connection.
+func dialProbe(ctx context.Context, address string, tlsConfig *tls.Config) (net.Conn, error) {
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if tlsConfig != nil {
+		return tls.Client(conn, tlsConfig), nil
+	}
+	return conn, nil
+}
+
+// probeCredential returns user name and password for the target
+//
+// user and password option overwrite user info in URL. Both can contain envvars.
+func probeCredential(dependsOn DependsOn, envvar *EnvVar) (user, password string) {
+	if dependsOn.URL.User != nil {
+		user = envvar.Expand(dependsOn.URL.User.Username())
+		password, _ = dependsOn.URL.User.Password()
+		password = envvar.Expand(password)
+	}
+	if dependsOn.User != "" {
+		user = envvar.Expand(dependsOn.User)
+	}
+	if dependsOn.Password != "" {
+		password = envvar.Expand(dependsOn.Password)
+	}
+	return
+}
+
+// hostWithDefaultPort returns "host:port" of URL
+func hostWithDefaultPort(u *url.URL, port string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
 // DumpAndSummaryDependsOnResult dumps depends-on check result
 func DumpAndSummaryDependsOnResult(results []DependsOnCheckResult) LogOutputs {
 	var outputs LogOutputs = make([]LogOutput, 0, len(results))
@@ -61,131 +378,333 @@ func DumpAndSummaryDependsOnResult(results []DependsOnCheckResult) LogOutputs {
 	return outputs
 }
 
-func WaitForDependencies(ctx context.Context, dependsOns []DependsOn) (result []DependsOnCheckResult) {
-	var eg *errgroup.Group
-	eg, ctx = errgroup.WithContext(ctx)
-	resultChan := make(chan DependsOnCheckResult)
+// WaitForDependencies waits for all dependencies and returns results in declaration order
+//
+// Each result has the depth in the graph of "after" to show the graph order by indentation.
+// Dependencies run in parallel except ones that have "after". They start after all dependencies in "after" become ready.
+// If progress is not nil, it shows dependencies that are not ready yet while waiting.
+func WaitForDependencies(ctx context.Context, dependsOns []DependsOn, envvar *EnvVar, progress *DependsOnProgress) (result []DependsOnCheckResult) {
+	depths, err := dependsOnDepths(dependsOns)
+	if err != nil {
+		result = make([]DependsOnCheckResult, len(dependsOns))
+		for i, dependsOn := range dependsOns {
+			result[i] = newDependsOnCheckResult(dependsOn)
+			result[i].error = err
+		}
+		return result
+	}
+	states := make(map[string]*dependsOnState)
 	for _, dependsOn := range dependsOns {
-		eg.Go(waitFor(ctx, dependsOn, resultChan))
+		if dependsOn.Name != "" {
+			states[dependsOn.Name] = &dependsOnState{
+				done: make(chan struct{}),
+			}
+		}
 	}
+	progress.begin(dependsOns)
+	defer progress.end()
+	parent := ctx
+	var eg *errgroup.Group
+	eg, ctx = errgroup.WithContext(ctx)
 	result = make([]DependsOnCheckResult, len(dependsOns))
-	for i := range dependsOns {
-		result[i] = <-resultChan
+	for i, dependsOn := range dependsOns {
+		i, dependsOn := i, dependsOn
+		eg.Go(func() error {
+			result[i] = waitAfter(ctx, dependsOn, envvar, states)
+			result[i].depth = depths[i]
+			markExpired(parent, &result[i])
+			if state, ok := states[dependsOn.Name]; ok {
+				state.ready = result[i].error == nil
+				close(state.done)
+			}
+			progress.finish(i, result[i])
+			return nil
+		})
 	}
+	eg.Wait()
 	return result
 }
 
-func waitFor(ctx context.Context, dependsOn DependsOn, resultChan chan<- DependsOnCheckResult) func() error {
-	return func() error {
-		u := dependsOn.URL
-		var err error
-		start := time.Now()
-		switch u.Scheme {
-		case "file":
-			err = waitForFile(ctx, dependsOn)
-		case "tcp", "tcp4", "tcp6", "unix":
-			err = waitForSocket(ctx, dependsOn)
-		case "http", "https":
-			err = waitForHTTP(ctx, dependsOn)
-		default:
-			err = fmt.Errorf("invalid host protocol provided: %s. supported protocols are: tcp, tcp4, tcp6 and http", u.Scheme)
+// dependsOnState tells whether the named dependency is ready to others that wait for it
+//
+// ready should be read after done is closed.
+type dependsOnState struct {
+	done  chan struct{}
+	ready bool
+}
+
+// dependsOnDepths returns depth of each dependency in the graph of "after"
+//
+// Dependencies without "after" are 0 and others are deeper than all dependencies in their "after".
+// It returns error if names are duplicated, "after" refers unknown name or there is circular dependency.
+func dependsOnDepths(dependsOns []DependsOn) ([]int, error) {
+	indexes := make(map[string]int)
+	for i, dependsOn := range dependsOns {
+		if dependsOn.Name == "" {
+			continue
 		}
-		resultChan <- DependsOnCheckResult{
-			url:      dependsOn.URL,
-			headers:  dependsOn.Headers,
-			timeout:  dependsOn.Timeout,
-			interval: dependsOn.Interval,
-			duration: time.Now().Sub(start),
-			error:    err,
+		if _, ok := indexes[dependsOn.Name]; ok {
+			return nil, fmt.Errorf("dependsOn's name '%s' is duplicated", dependsOn.Name)
 		}
-		return nil
+		indexes[dependsOn.Name] = i
+	}
+	for _, dependsOn := range dependsOns {
+		for _, after := range dependsOn.After {
+			if _, ok := indexes[after]; !ok {
+				return nil, fmt.Errorf("dependsOn '%s' waits for unknown dependency '%s'", newDependsOnCheckResult(dependsOn).label(), after)
+			}
+		}
+	}
+	depths := make([]int, len(dependsOns))
+	resolved := make([]bool, len(dependsOns))
+	for depth, count := 0, 0; count < len(dependsOns); depth++ {
+		var next []int
+		for i, dependsOn := range dependsOns {
+			if resolved[i] {
+				continue
+			}
+			ready := true
+			for _, after := range dependsOn.After {
+				if !resolved[indexes[after]] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = append(next, i)
+			}
+		}
+		if len(next) == 0 {
+			var names []string
+			for i, dependsOn := range dependsOns {
+				if !resolved[i] {
+					names = append(names, newDependsOnCheckResult(dependsOn).label())
+				}
+			}
+			return nil, fmt.Errorf("dependsOn has circular dependency: %s", strings.Join(names, ", "))
+		}
+		for _, i := range next {
+			resolved[i] = true
+			depths[i] = depth
+		}
+		count += len(next)
 	}
+	return depths, nil
 }
 
-func waitForFile(ctx context.Context, dependsOn DependsOn) error {
-	ticker := time.NewTicker(dependsOn.Interval)
-	defer ticker.Stop()
-	ctx, cancel := context.WithTimeout(ctx, dependsOn.Timeout)
-	defer cancel()
-	for {
-		if _, err := os.Stat(dependsOn.URL.Path); err == nil {
-			return nil
-		} else if !os.IsNotExist(err) {
-			return fmt.Errorf("File check error %s: %w", dependsOn.URL.String(), err)
-		}
+func newDependsOnCheckResult(dependsOn DependsOn) DependsOnCheckResult {
+	return DependsOnCheckResult{
+		name:     dependsOn.Name,
+		after:    dependsOn.After,
+		url:      dependsOn.URL,
+		headers:  dependsOn.Headers,
+		timeout:  dependsOn.Timeout,
+		interval: dependsOn.Interval,
+	}
+}
+
+// waitAfter waits for dependencies in "after" and then checks the dependency
+func waitAfter(ctx context.Context, dependsOn DependsOn, envvar *EnvVar, states map[string]*dependsOnState) DependsOnCheckResult {
+	start := time.Now()
+	for _, after := range dependsOn.After {
+		state := states[after]
 		select {
+		case <-state.done:
 		case <-ctx.Done():
-			if ctx.Err() == context.DeadlineExceeded {
-				return fmt.Errorf("timeout for checking file '%s': %w", dependsOn.URL.String(), ctx.Err())
-			}
-			return ctx.Err()
-		case <-ticker.C:
+			result := newDependsOnCheckResult(dependsOn)
+			result.duration = time.Now().Sub(start)
+			result.error = ctx.Err()
+			return result
+		}
+		if !state.ready {
+			result := newDependsOnCheckResult(dependsOn)
+			result.error = &skippedError{after: after}
+			return result
 		}
 	}
+	if len(dependsOn.AnyOf) > 0 {
+		return waitForAnyOf(ctx, dependsOn, envvar)
+	}
+	return waitFor(ctx, dependsOn, envvar)
 }
 
-func waitForHTTP(ctx context.Context, dependsOn DependsOn) error {
-	ticker := time.NewTicker(dependsOn.Interval)
-	defer ticker.Stop()
-	ctx, cancel := context.WithTimeout(ctx, dependsOn.Timeout)
+// waitForAnyOf checks alternatives in parallel and finishes when one of them becomes ready
+func waitForAnyOf(ctx context.Context, dependsOn DependsOn, envvar *EnvVar) DependsOnCheckResult {
+	result := newDependsOnCheckResult(dependsOn)
+	start := time.Now()
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
-	for {
-		req, _ := http.NewRequest("HEAD", dependsOn.URL.String(), nil)
+	resultChan := make(chan int)
+	result.alternatives = make([]DependsOnCheckResult, len(dependsOn.AnyOf))
+	for i, alternative := range dependsOn.AnyOf {
+		go func(i int, alternative DependsOn) {
+			result.alternatives[i] = waitFor(ctx, alternative, envvar)
+			resultChan <- i
+		}(i, alternative)
+	}
+	satisfied := -1
+	for range dependsOn.AnyOf {
+		i := <-resultChan
+		if satisfied == -1 && result.alternatives[i].error == nil {
+			satisfied = i
+			cancel()
+		}
+	}
+	result.duration = time.Now().Sub(start)
+	if satisfied == -1 {
+		result.error = errors.New("none of alternatives is ready")
+	} else {
+		result.warnings = result.alternatives[satisfied].warnings
+	}
+	return result
+}
+
+func waitFor(ctx context.Context, dependsOn DependsOn, envvar *EnvVar) DependsOnCheckResult {
+	u := dependsOn.URL
+	result := newDependsOnCheckResult(dependsOn)
+	start := time.Now()
+	switch u.Scheme {
+	case "file":
+		result.error = waitForFile(ctx, dependsOn, &result)
+	case "tcp", "tcp4", "tcp6", "unix":
+		result.error = waitForSocket(ctx, dependsOn, &result)
+	case "http", "https":
+		result.error = waitForHTTP(ctx, dependsOn, envvar, &result)
+	case "tls":
+		result.error = waitForTLS(ctx, dependsOn, envvar, &result)
+	case "postgres", "postgresql":
+		result.error = waitForPostgres(ctx, dependsOn, envvar, &result)
+	case "mysql":
+		result.error = waitForMySQL(ctx, dependsOn, envvar, &result)
+	case "redis", "rediss":
+		result.error = waitForRedis(ctx, dependsOn, envvar, &result)
+	case "grpc", "grpcs":
+		result.error = waitForGRPC(ctx, dependsOn, envvar, &result)
+	case "exec":
+		result.error = waitForCommand(ctx, dependsOn, envvar, &result)
+	case "dns":
+		result.error = waitForDNS(ctx, dependsOn, envvar, &result)
+	case "kafka":
+		result.error = waitForKafka(ctx, dependsOn, envvar, &result)
+	case "nats":
+		result.error = waitForNATS(ctx, dependsOn, envvar, &result)
+	case "amqp", "amqps":
+		result.error = waitForAMQP(ctx, dependsOn, envvar, &result)
+	case "udp", "udp4", "udp6":
+		result.error = waitForUDP(ctx, dependsOn, envvar, &result)
+	case "ws", "wss":
+		result.error = waitForWebSocket(ctx, dependsOn, envvar, &result)
+	default:
+		result.error = fmt.Errorf("invalid host protocol provided: %s. supported protocols are: tcp, tcp4, tcp6, udp, udp4, udp6, unix, file, http, https, ws, wss, tls, postgres, mysql, redis, rediss, grpc, grpcs, exec, dns, kafka, nats, amqp and amqps", u.Scheme)
+	}
+	result.duration = time.Now().Sub(start)
+	return result
+}
+
+func waitForHTTP(ctx context.Context, dependsOn DependsOn, envvar *EnvVar, result *DependsOnCheckResult) error {
+	client := http.DefaultClient
+	if dependsOn.TLS != nil {
+		tlsConfig, err := newTLSConfig(dependsOn.TLS, envvar)
+		if err != nil {
+			return err
+		}
+		client = &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+		}
+	}
+	method := dependsOn.Method
+	if method == "" {
+		method = "HEAD"
+	}
+	return pollUntilReady(ctx, dependsOn, result, func(ctx context.Context) error {
+		if dependsOn.AttemptTimeout == 0 {
+			// one hung request shouldn't consume the whole timeout
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, dependsOn.Interval)
+			defer cancel()
+		}
+		req, _ := http.NewRequest(method, dependsOn.URL.String(), nil)
 		req = req.WithContext(ctx)
 		for _, header := range dependsOn.Headers {
 			req.Header.Add(header[0], header[1])
 		}
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := client.Do(req)
 		if err != nil {
-			if ctx.Err() == context.DeadlineExceeded {
-				return fmt.Errorf("timeout for waiting web service '%s': %w", dependsOn.URL.String(), ctx.Err())
-			}
-		} else if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			return nil
+			return err
 		}
-		select {
-		case <-ctx.Done():
-			if ctx.Err() == context.DeadlineExceeded {
-				return fmt.Errorf("timeout for checking server, '%s': %w", dependsOn.URL.String(), ctx.Err())
-			}
-			return ctx.Err()
-		case <-ticker.C:
+		defer resp.Body.Close()
+		if err := checkHTTPResponse(dependsOn, resp); err != nil {
+			return err
 		}
+		checkCertificateExpiry(dependsOn, resp.TLS, result)
+		return nil
+	})
+}
+
+func checkHTTPResponse(dependsOn DependsOn, resp *http.Response) error {
+	if !matchHTTPStatus(dependsOn.ExpectStatus, resp.StatusCode) {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if dependsOn.BodyContains == "" && dependsOn.BodyRegexp == nil && dependsOn.JSONPath == nil {
+		return nil
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	if err != nil {
+		return fmt.Errorf("can't read response body: %w", err)
+	}
+	if dependsOn.BodyContains != "" && !bytes.Contains(body, []byte(dependsOn.BodyContains)) {
+		return fmt.Errorf("response body doesn't contain %q", dependsOn.BodyContains)
 	}
+	if dependsOn.BodyRegexp != nil && !dependsOn.BodyRegexp.Match(body) {
+		return fmt.Errorf("response body doesn't match with pattern %q", dependsOn.BodyRegexp.String())
+	}
+	if dependsOn.JSONPath != nil {
+		return dependsOn.JSONPath.Check(body)
+	}
+	return nil
 }
 
-func waitForSocket(ctx context.Context, dependsOn DependsOn) error {
+func matchHTTPStatus(expectStatus [][2]int, status int) bool {
+	if len(expectStatus) == 0 {
+		return status >= 200 && status < 300
+	}
+	for _, statusRange := range expectStatus {
+		if statusRange[0] <= status && status <= statusRange[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func waitForSocket(ctx context.Context, dependsOn DependsOn, result *DependsOnCheckResult) error {
 	u := dependsOn.URL
-	finish := make(chan struct{})
+	var host string
+	if u.Scheme == "unix" {
+		host = u.Path
+	} else {
+		host = u.Host
+	}
 	ctx, cancel := context.WithTimeout(ctx, dependsOn.Timeout)
 	defer cancel()
-	go func() {
-		ticker := time.NewTicker(dependsOn.Interval)
-		var host string
-		if u.Scheme == "unix" {
-			host = u.Path
-		} else {
-			host = u.Host
-		}
-		defer ticker.Stop()
-		for {
-			conn, _ := net.DialTimeout(u.Scheme, host, dependsOn.Interval)
-			if conn != nil {
-				conn.Close()
-				finish <- struct{}{}
-			}
-			select {
-			case <-ticker.C:
-			case <-ctx.Done():
-				return
+	p := newPoller(dependsOn, result)
+	for {
+		attemptCtx, attemptCancel := p.attemptContext(ctx)
+		dialer := &net.Dialer{Timeout: dependsOn.Interval}
+		conn, err := dialer.DialContext(attemptCtx, u.Scheme, host)
+		attemptCancel()
+		p.record(ctx, err)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if err := p.wait(ctx); err != nil {
+			if err == context.DeadlineExceeded {
+				return fmt.Errorf("timeout to connect %s://%s: %w", u.Scheme, u.Host, err)
 			}
+			return err
 		}
-	}()
-	select {
-	case <-ctx.Done():
-		color.Printf("<red>Connection timeout:</> <yellow>%s</>\n", dependsOn.URL.String())
-		return fmt.Errorf("timeout to connect %s://%s\n", u.Scheme, u.Host)
-	case <-finish:
-		return nil
 	}
 }
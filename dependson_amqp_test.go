@@ -0,0 +1,216 @@
+package docradle
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAMQPServer speaks minimal AMQP 0-9-1 to open virtual host and declare queue passively
+type fakeAMQPServer struct {
+	listener net.Listener
+	user     string
+	password string
+	vhost    string
+	queues   []string
+}
+
+func newFakeAMQPServer(t *testing.T, user, password, vhost string, queues ...string) *fakeAMQPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	s := &fakeAMQPServer{
+		listener: listener,
+		user:     user,
+		password: password,
+		vhost:    vhost,
+		queues:   queues,
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeAMQPServer) Close() {
+	s.listener.Close()
+}
+
+func (s *fakeAMQPServer) close(a *amqpConn, channel, classID uint16, methodID uint16, code uint16, text string) {
+	var args bytes.Buffer
+	binary.Write(&args, binary.BigEndian, code)
+	writeAMQPShortString(&args, text)
+	args.Write([]byte{0, 0, 0, 0})
+	a.writeMethod(channel, classID, methodID, args.Bytes())
+}
+
+func (s *fakeAMQPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	a := &amqpConn{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(a.reader, header); err != nil || string(header) != "AMQP\x00\x00\x09\x01" {
+		return
+	}
+	var start bytes.Buffer
+	start.Write([]byte{0, 9})
+	writeAMQPLongString(&start, "\x00\x00\x00\x00") // server properties (dummy)
+	writeAMQPLongString(&start, "PLAIN AMQPLAIN")
+	writeAMQPLongString(&start, "en_US")
+	a.writeMethod(0, amqpClassConnection, amqpConnectionStart, start.Bytes())
+	for {
+		channel, classID, methodID, args, err := a.readMethod()
+		if err != nil {
+			return
+		}
+		switch {
+		case classID == amqpClassConnection && methodID == amqpConnectionStartOk:
+			// skip client properties and mechanism
+			offset := 4 + int(binary.BigEndian.Uint32(args[0:4]))
+			offset += 1 + int(args[offset])
+			size := int(binary.BigEndian.Uint32(args[offset : offset+4]))
+			response := string(args[offset+4 : offset+4+size])
+			if response != "\x00"+s.user+"\x00"+s.password {
+				// RabbitMQ closes socket without Connection.Close
+				return
+			}
+			a.writeMethod(0, amqpClassConnection, amqpConnectionTune, []byte{0, 0, 0, 2, 0, 0, 0, 60})
+		case classID == amqpClassConnection && methodID == amqpConnectionTuneOk:
+		case classID == amqpClassConnection && methodID == amqpConnectionOpen:
+			vhost := string(args[1 : 1+int(args[0])])
+			if vhost != s.vhost {
+				s.close(a, 0, amqpClassConnection, amqpConnectionClose, 530, "NOT_ALLOWED - vhost "+vhost+" not found")
+				return
+			}
+			a.writeMethod(0, amqpClassConnection, amqpConnectionOpenOk, []byte{0})
+		case classID == amqpClassChannel && methodID == amqpChannelOpen:
+			a.writeMethod(channel, amqpClassChannel, amqpChannelOpenOk, []byte{0, 0, 0, 0})
+		case classID == amqpClassQueue && methodID == amqpQueueDeclare:
+			queue := string(args[3 : 3+int(args[2])])
+			found := false
+			for _, name := range s.queues {
+				found = found || name == queue
+			}
+			if !found {
+				s.close(a, channel, amqpClassChannel, amqpChannelClose, 404, fmt.Sprintf("NOT_FOUND - no queue '%s' in vhost '%s'", queue, s.vhost))
+				continue
+			}
+			var declareOk bytes.Buffer
+			writeAMQPShortString(&declareOk, queue)
+			declareOk.Write([]byte{0, 0, 0, 0, 0, 0, 0, 0})
+			a.writeMethod(channel, amqpClassQueue, amqpQueueDeclareOk, declareOk.Bytes())
+		case classID == amqpClassConnection && methodID == amqpConnectionClose:
+			a.writeMethod(0, amqpClassConnection, amqpConnectionClose+1, nil)
+			return
+		}
+	}
+}
+
+func TestWaitForDependencies_AMQP(t *testing.T) {
+	testcases := []struct {
+		name      string
+		user      string
+		password  string
+		vhost     string
+		url       string
+		ok        bool
+		errorText string
+	}{
+		{
+			name:     "ok: default user",
+			user:     "guest",
+			password: "guest",
+			vhost:    "/",
+			url:      "amqp://%s",
+			ok:       true,
+		},
+		{
+			name:     "ok: user and vhost",
+			user:     "app",
+			password: "secret",
+			vhost:    "production",
+			url:      "amqp://app:$AMQP_PASSWORD@%s/production",
+			ok:       true,
+		},
+		{
+			name:      "ng: wrong password",
+			user:      "app",
+			password:  "secret",
+			vhost:     "/",
+			url:       "amqp://app:wrong@%s",
+			ok:        false,
+			errorText: "authentication failure",
+		},
+		{
+			name:      "ng: unknown vhost",
+			user:      "guest",
+			password:  "guest",
+			vhost:     "/",
+			url:       "amqp://%s/staging",
+			ok:        false,
+			errorText: "530 NOT_ALLOWED - vhost staging not found",
+		},
+		{
+			name:     "ok: queue exists",
+			user:     "guest",
+			password: "guest",
+			vhost:    "/",
+			url:      "amqp://%s?queue=tasks",
+			ok:       true,
+		},
+		{
+			name:      "ng: queue doesn't exist",
+			user:      "guest",
+			password:  "guest",
+			vhost:     "/",
+			url:       "amqp://%s?queue=unknown",
+			ok:        false,
+			errorText: "queue 'unknown' is not available: 404 NOT_FOUND",
+		},
+	}
+	envvar := NewEnvVar()
+	envvar.Register(fromOsEnv, "AMQP_PASSWORD", "secret")
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeAMQPServer(t, tt.user, tt.password, tt.vhost, "tasks")
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					URL:      mustUrlParse(t, strings.Replace(tt.url, "%s", server.listener.Addr().String(), 1)),
+					Timeout:  time.Millisecond * 300,
+					Interval: time.Millisecond * 5,
+				},
+			}, envvar, nil)
+			assert.Len(t, results, 1)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Contains(t, results[0].Error().Error(), tt.errorText)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package docradle
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// waitForDNS waits until the name resolves to enough records
+//
+// URL is like dns://name?type=A&min=2. type is one of IP (default), A, AAAA, CNAME, SRV and TXT.
+// If resolver option ("host:port") is specified, the query is sent to the server instead of system resolver.
+func waitForDNS(ctx context.Context, dependsOn DependsOn, envvar *EnvVar, result *DependsOnCheckResult) error {
+	name := dependsOn.URL.Hostname()
+	query := dependsOn.URL.Query()
+	recordType := strings.ToUpper(query.Get("type"))
+	if recordType == "" {
+		recordType = "IP"
+	}
+	switch recordType {
+	case "IP", "A", "AAAA", "CNAME", "SRV", "TXT":
+	default:
+		return fmt.Errorf("unsupported record type '%s'. supported types are: IP, A, AAAA, CNAME, SRV and TXT", recordType)
+	}
+	min := 1
+	if query.Get("min") != "" {
+		var err error
+		min, err = strconv.Atoi(query.Get("min"))
+		if err != nil {
+			return fmt.Errorf("invalid min parameter '%s': %w", query.Get("min"), err)
+		}
+	}
+	resolver := net.DefaultResolver
+	if dependsOn.Resolver != "" {
+		address := envvar.Expand(dependsOn.Resolver)
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(address, "53")
+		}
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				dialer := &net.Dialer{}
+				return dialer.DialContext(ctx, network, address)
+			},
+		}
+	}
+	return pollUntilReady(ctx, dependsOn, result, func(ctx context.Context) error {
+		records, err := lookupRecords(ctx, resolver, recordType, name)
+		// keep the last records if the lookup is interrupted by the deadline
+		if err == nil || ctx.Err() == nil {
+			result.resolved = records
+		}
+		if err != nil {
+			return err
+		}
+		if len(records) < min {
+			return fmt.Errorf("%s resolves to %d %s records, at least %d records are required", name, len(records), recordType, min)
+		}
+		return nil
+	})
+}
+
+func lookupRecords(ctx context.Context, resolver *net.Resolver, recordType, name string) ([]string, error) {
+	var records []string
+	switch recordType {
+	case "IP", "A", "AAAA":
+		addrs, err := resolver.LookupIPAddr(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			isIPv4 := addr.IP.To4() != nil
+			if (recordType == "A" && !isIPv4) || (recordType == "AAAA" && isIPv4) {
+				continue
+			}
+			records = append(records, addr.IP.String())
+		}
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		// LookupCNAME returns the name itself if it doesn't have CNAME record
+		if !strings.EqualFold(strings.TrimSuffix(cname, "."), strings.TrimSuffix(name, ".")) {
+			records = append(records, cname)
+		}
+	case "SRV":
+		_, srvs, err := resolver.LookupSRV(ctx, "", "", name)
+		if err != nil {
+			return nil, err
+		}
+		for _, srv := range srvs {
+			records = append(records, net.JoinHostPort(strings.TrimSuffix(srv.Target, "."), strconv.Itoa(int(srv.Port))))
+		}
+	case "TXT":
+		txts, err := resolver.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, txts...)
+	}
+	return records, nil
+}
@@ -0,0 +1,235 @@
+package docradle
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dnsServer is a DNS stand-in that answers A, CNAME and SRV records
+type dnsServer struct {
+	conn    net.PacketConn
+	lock    sync.Mutex
+	records map[string][]dnsmessage.ResourceBody
+}
+
+func newDNSServer(t *testing.T) *dnsServer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	s := &dnsServer{
+		conn:    conn,
+		records: make(map[string][]dnsmessage.ResourceBody),
+	}
+	go s.serve()
+	return s
+}
+
+func (s *dnsServer) set(name string, records ...dnsmessage.ResourceBody) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.records[name] = records
+}
+
+func (s *dnsServer) serve() {
+	buffer := make([]byte, 512)
+	for {
+		n, addr, err := s.conn.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+		var parser dnsmessage.Parser
+		header, err := parser.Start(buffer[:n])
+		if err != nil {
+			continue
+		}
+		question, err := parser.Question()
+		if err != nil {
+			continue
+		}
+		s.lock.Lock()
+		records, ok := s.records[question.Name.String()]
+		s.lock.Unlock()
+		response := dnsmessage.Message{
+			Header: dnsmessage.Header{
+				ID:            header.ID,
+				Response:      true,
+				Authoritative: true,
+			},
+			Questions: []dnsmessage.Question{question},
+		}
+		if !ok {
+			response.Header.RCode = dnsmessage.RCodeNameError
+		}
+		for _, record := range records {
+			if recordType(record) != question.Type {
+				continue
+			}
+			response.Answers = append(response.Answers, dnsmessage.Resource{
+				Header: dnsmessage.ResourceHeader{
+					Name:  question.Name,
+					Type:  question.Type,
+					Class: dnsmessage.ClassINET,
+				},
+				Body: record,
+			})
+		}
+		packed, err := response.Pack()
+		if err != nil {
+			continue
+		}
+		s.conn.WriteTo(packed, addr)
+	}
+}
+
+func recordType(record dnsmessage.ResourceBody) dnsmessage.Type {
+	switch record.(type) {
+	case *dnsmessage.AResource:
+		return dnsmessage.TypeA
+	case *dnsmessage.SRVResource:
+		return dnsmessage.TypeSRV
+	case *dnsmessage.CNAMEResource:
+		return dnsmessage.TypeCNAME
+	}
+	return 0
+}
+
+func TestWaitForDependencies_DNS(t *testing.T) {
+	testcases := []struct {
+		name      string
+		url       string
+		records   map[string][]dnsmessage.ResourceBody
+		later     map[string][]dnsmessage.ResourceBody // records after 50ms
+		ok        bool
+		resolved  []string
+		errorText string
+	}{
+		{
+			name: "ok: A",
+			url:  "dns://db.docradle.test.?type=A",
+			records: map[string][]dnsmessage.ResourceBody{
+				"db.docradle.test.": {&dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}},
+			},
+			ok:       true,
+			resolved: []string{"10.0.0.1"},
+		},
+		{
+			name: "ok: wait until resolved",
+			url:  "dns://db.docradle.test.",
+			later: map[string][]dnsmessage.ResourceBody{
+				"db.docradle.test.": {&dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}},
+			},
+			ok:       true,
+			resolved: []string{"10.0.0.1"},
+		},
+		{
+			name: "ok: wait until min records",
+			url:  "dns://db.docradle.test.?type=A&min=2",
+			records: map[string][]dnsmessage.ResourceBody{
+				"db.docradle.test.": {&dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}},
+			},
+			later: map[string][]dnsmessage.ResourceBody{
+				"db.docradle.test.": {
+					&dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}},
+					&dnsmessage.AResource{A: [4]byte{10, 0, 0, 2}},
+				},
+			},
+			ok:       true,
+			resolved: []string{"10.0.0.1", "10.0.0.2"},
+		},
+		{
+			name: "ok: SRV",
+			url:  "dns://_postgres._tcp.db.docradle.test.?type=SRV",
+			records: map[string][]dnsmessage.ResourceBody{
+				"_postgres._tcp.db.docradle.test.": {&dnsmessage.SRVResource{
+					Target: dnsmessage.MustNewName("db-0.docradle.test."),
+					Port:   5432,
+				}},
+			},
+			ok:       true,
+			resolved: []string{"db-0.docradle.test:5432"},
+		},
+		{
+			name: "ok: wait until CNAME exists",
+			url:  "dns://db.docradle.test.?type=CNAME",
+			records: map[string][]dnsmessage.ResourceBody{
+				"db.docradle.test.": {&dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}},
+			},
+			later: map[string][]dnsmessage.ResourceBody{
+				"db.docradle.test.": {&dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName("db-0.docradle.test.")}},
+			},
+			ok:       true,
+			resolved: []string{"db-0.docradle.test."},
+		},
+		{
+			name: "ng: name without CNAME",
+			url:  "dns://db.docradle.test.?type=CNAME",
+			records: map[string][]dnsmessage.ResourceBody{
+				"db.docradle.test.": {&dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}},
+			},
+			ok:        false,
+			errorText: "db.docradle.test. resolves to 0 CNAME records, at least 1 records are required",
+		},
+		{
+			name:      "ng: not found",
+			url:       "dns://db.docradle.test.",
+			ok:        false,
+			errorText: "no such host",
+		},
+		{
+			name: "ng: not enough records",
+			url:  "dns://db.docradle.test.?min=2",
+			records: map[string][]dnsmessage.ResourceBody{
+				"db.docradle.test.": {&dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}},
+			},
+			ok:        false,
+			resolved:  []string{"10.0.0.1"},
+			errorText: "db.docradle.test. resolves to 1 IP records, at least 2 records are required",
+		},
+		{
+			name:      "ng: unsupported type",
+			url:       "dns://db.docradle.test.?type=MX",
+			ok:        false,
+			errorText: "unsupported record type 'MX'",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newDNSServer(t)
+			defer server.conn.Close()
+			for name, records := range tt.records {
+				server.set(name, records...)
+			}
+			timer := time.AfterFunc(50*time.Millisecond, func() {
+				for name, records := range tt.later {
+					server.set(name, records...)
+				}
+			})
+			defer timer.Stop()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					URL:      mustUrlParse(t, tt.url),
+					Resolver: server.conn.LocalAddr().String(),
+					Timeout:  time.Millisecond * 300,
+					Interval: time.Millisecond * 10,
+				},
+			}, NewEnvVar(), nil)
+			assert.Len(t, results, 1)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Contains(t, results[0].Error().Error(), tt.errorText)
+			}
+			assert.Equal(t, tt.resolved, results[0].resolved)
+		})
+	}
+}
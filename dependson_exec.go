@@ -0,0 +1,136 @@
+package docradle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// maxCommandOutputSize is a limit of command output to show in error message
+const maxCommandOutputSize = 1024
+
+// commandURL returns URL to display the dependency that is specified by command option
+func commandURL(command []string) *url.URL {
+	return &url.URL{
+		Scheme: "exec",
+		Opaque: "//" + strings.Join(command, " "),
+	}
+}
+
+// commandLine returns program and arguments of the command check
+//
+// command option is used if exists. Otherwise program is taken from URL (exec://program or exec:///path/to/program).
+func commandLine(dependsOn DependsOn) []string {
+	if len(dependsOn.Command) > 0 {
+		return dependsOn.Command
+	}
+	return []string{dependsOn.URL.Host + dependsOn.URL.Path}
+}
+
+// waitForCommand runs the command until it exits with code 0
+//
+// Arguments can contain envvars. The command is searched in PATH of the envvars that are passed to the main command,
+// runs with them and as process.user (see dependsOnAs). Each attempt runs in its own process group that is killed at its timeout.
+func waitForCommand(ctx context.Context, dependsOn DependsOn, envvar *EnvVar, result *DependsOnCheckResult) error {
+	command := commandLine(dependsOn)
+	args := make([]string, len(command)-1)
+	for i, arg := range command[1:] {
+		args[i] = envvar.Expand(arg)
+	}
+	program := envvar.Expand(command[0])
+	_, path, _, ok := envvar.Get("PATH")
+	if !ok {
+		path = os.Getenv("PATH")
+	}
+	env := envvar.EnvsForExec()
+	return pollUntilReady(ctx, dependsOn, result, func(ctx context.Context) error {
+		// program is looked up on every attempt like other checks because it may be installed
+		// (e.g. by a shared volume) while waiting. A missing program is retried until timeout.
+		programPath, err := lookPath(program, path)
+		if err != nil {
+			return err
+		}
+		cmd := exec.Command(programPath, args...)
+		cmd.Env = env
+		dependsOn.runAs.applyTo(cmd)
+		output := &limitedBuffer{limit: maxCommandOutputSize}
+		// the whole process group is killed at timeout like hooks
+		err = runWithWriter(ctx, cmd, output)
+		if err == nil {
+			return nil
+		}
+		if text := strings.TrimSpace(output.String()); text != "" {
+			return fmt.Errorf("%w: %s", err, text)
+		}
+		return err
+	})
+}
+
+// lookPath is exec.LookPath that searches the PATH of the command instead of docradle's one
+func lookPath(file, path string) (string, error) {
+	if strings.Contains(file, "/") {
+		if err := findExecutable(file); err != nil {
+			return "", &exec.Error{Name: file, Err: err}
+		}
+		return file, nil
+	}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			// Unix shell semantics: empty path element means "."
+			dir = "."
+		}
+		candidate := filepath.Join(dir, file)
+		if findExecutable(candidate) == nil {
+			return candidate, nil
+		}
+	}
+	return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+}
+
+func findExecutable(file string) error {
+	stat, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	if mode := stat.Mode(); mode.IsDir() || mode&0111 == 0 {
+		return os.ErrPermission
+	}
+	return nil
+}
+
+// dependsOnAs returns copies of dependsOns whose exec checks run as the user
+func dependsOnAs(dependsOns []DependsOn, runAs *credential) []DependsOn {
+	if runAs == nil || len(dependsOns) == 0 {
+		return dependsOns
+	}
+	result := make([]DependsOn, len(dependsOns))
+	for i, dependsOn := range dependsOns {
+		dependsOn.runAs = runAs
+		dependsOn.AnyOf = dependsOnAs(dependsOn.AnyOf, runAs)
+		result[i] = dependsOn
+	}
+	return result
+}
+
+// limitedBuffer keeps the beginning of written data up to limit
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if rest := b.limit - b.Len(); rest > 0 {
+		if len(p) > rest {
+			b.Buffer.Write(p[:rest])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	// pretend to write all to keep the command running
+	return len(p), nil
+}
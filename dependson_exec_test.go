@@ -0,0 +1,208 @@
+package docradle
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForDependencies_Command(t *testing.T) {
+	dirPath := filepath.Join(os.TempDir(), "cradle"+xid.New().String())
+	os.MkdirAll(dirPath, 0755)
+	defer os.RemoveAll(dirPath)
+
+	readyFile := filepath.Join(dirPath, "ready")
+	timer := time.AfterFunc(50*time.Millisecond, func() {
+		ioutil.WriteFile(readyFile, nil, 0644)
+	})
+	defer timer.Stop()
+
+	envvar := NewEnvVar()
+	envvar.Register(fromOsEnv, "READY_FILE", readyFile)
+	envvar.Register(fromOsEnv, "STATUS", "migrated")
+
+	testcases := []struct {
+		name           string
+		command        []string
+		attemptTimeout time.Duration
+		ok             bool
+		errorText      string
+	}{
+		{
+			name:    "ok: exit 0",
+			command: []string{"true"},
+			ok:      true,
+		},
+		{
+			name:    "ok: wait until exit 0",
+			command: []string{"test", "-f", "${READY_FILE}"},
+			ok:      true,
+		},
+		{
+			name:    "ok: envvar",
+			command: []string{"sh", "-c", `test "$STATUS" = migrated`},
+			ok:      true,
+		},
+		{
+			name:      "ng: output in error",
+			command:   []string{"sh", "-c", "echo no response; exit 2"},
+			ok:        false,
+			errorText: "exit status 2: no response",
+		},
+		{
+			name:           "ng: attempt timeout",
+			command:        []string{"sleep", "10"},
+			attemptTimeout: 30 * time.Millisecond,
+			ok:             false,
+			errorText:      "signal: killed",
+		},
+		{
+			name:      "ng: not found",
+			command:   []string{"docradle-command-not-found"},
+			ok:        false,
+			errorText: "executable file not found",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					URL:            commandURL(tt.command),
+					Command:        tt.command,
+					Timeout:        time.Millisecond * 300,
+					Interval:       time.Millisecond * 10,
+					AttemptTimeout: tt.attemptTimeout,
+				},
+			}, envvar, nil)
+			assert.Len(t, results, 1)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Contains(t, results[0].Error().Error(), tt.errorText)
+			}
+		})
+	}
+}
+
+func TestWaitForDependencies_CommandPath(t *testing.T) {
+	dirPath := filepath.Join(os.TempDir(), "cradle"+xid.New().String())
+	os.MkdirAll(dirPath, 0755)
+	defer os.RemoveAll(dirPath)
+
+	// the command exists only in PATH of the main command and is installed while waiting
+	timer := time.AfterFunc(50*time.Millisecond, func() {
+		ioutil.WriteFile(filepath.Join(dirPath, "docradle-ready"), []byte("#!/bin/sh\nexit 0\n"), 0755)
+	})
+	defer timer.Stop()
+
+	envvar := NewEnvVar()
+	envvar.Register(fromOsEnv, "PATH", dirPath+string(filepath.ListSeparator)+os.Getenv("PATH"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	command := []string{"docradle-ready"}
+	results := WaitForDependencies(ctx, []DependsOn{
+		{
+			URL:      commandURL(command),
+			Command:  command,
+			Timeout:  time.Millisecond * 500,
+			Interval: time.Millisecond * 10,
+		},
+	}, envvar, nil)
+	assert.Len(t, results, 1)
+	assert.NoError(t, results[0].Error())
+	assert.True(t, results[0].attempts > 1, "attempts %d", results[0].attempts)
+}
+
+func TestWaitForDependencies_CommandNotFound(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// missing program is retried like other failures until timeout
+	command := []string{"docradle-command-not-found"}
+	results := WaitForDependencies(ctx, []DependsOn{
+		{
+			URL:      commandURL(command),
+			Command:  command,
+			Timeout:  time.Millisecond * 100,
+			Interval: time.Millisecond * 10,
+		},
+	}, NewEnvVar(), nil)
+	assert.Len(t, results, 1)
+	assert.Error(t, results[0].Error())
+	assert.Contains(t, results[0].Error().Error(), "executable file not found")
+	assert.True(t, results[0].attempts > 1, "attempts %d", results[0].attempts)
+}
+
+func TestWaitForDependencies_CommandWithChild(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// sleep is a child of sh that keeps the output open
+	command := []string{"sh", "-c", "sleep 5; exit 1"}
+	start := time.Now()
+	results := WaitForDependencies(ctx, []DependsOn{
+		{
+			URL:            commandURL(command),
+			Command:        command,
+			Timeout:        time.Second,
+			Interval:       time.Millisecond * 10,
+			AttemptTimeout: time.Millisecond * 300,
+		},
+	}, NewEnvVar(), nil)
+	assert.Len(t, results, 1)
+	assert.Error(t, results[0].Error())
+	assert.True(t, time.Since(start) < 2*time.Second, "elapsed %s", time.Since(start))
+}
+
+func TestWaitForDependencies_CommandURL(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	results := WaitForDependencies(ctx, []DependsOn{
+		{
+			URL:      mustUrlParse(t, "exec://true"),
+			Timeout:  time.Millisecond * 100,
+			Interval: time.Millisecond * 10,
+		},
+	}, NewEnvVar(), nil)
+	assert.Len(t, results, 1)
+	assert.NoError(t, results[0].Error())
+	assert.Contains(t, results[0].String(), "exec://true")
+}
+
+func TestWaitForDependencies_CommandAsUser(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("changing user requires root privilege")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	command := []string{"sh", "-c", `test "$(id -u):$(printenv HOME)" = 65534:/nonexistent`}
+	runAs := &credential{uid: 65534, gid: 65534, groups: []uint32{65534}, home: "/nonexistent"}
+	results := WaitForDependencies(ctx, dependsOnAs([]DependsOn{
+		{
+			AnyOf: []DependsOn{
+				{
+					URL:      commandURL(command),
+					Command:  command,
+					Timeout:  time.Millisecond * 100,
+					Interval: time.Millisecond * 10,
+				},
+			},
+		},
+	}, runAs), NewEnvVar(), nil)
+	assert.Len(t, results, 1)
+	assert.NoError(t, results[0].Error())
+}
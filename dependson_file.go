@@ -0,0 +1,136 @@
+package docradle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// waitForFile waits until the file exists and satisfies conditions
+//
+// Path can be a glob pattern like file:///var/run/app/*.ready. It is satisfied when one of the matched files satisfies conditions.
+// If notExists option is true, it waits until no file matches instead.
+func waitForFile(ctx context.Context, dependsOn DependsOn, result *DependsOnCheckResult) error {
+	ctx, cancel := context.WithTimeout(ctx, dependsOn.Timeout)
+	defer cancel()
+	path := filePath(dependsOn.URL)
+	p := newPoller(dependsOn, result)
+	var lastMismatch error
+	for {
+		paths, err := matchFiles(path)
+		if err != nil {
+			p.record(ctx, err)
+			return fmt.Errorf("File check error %s: %w", dependsOn.URL.String(), err)
+		}
+		ready, mismatch := checkFiles(dependsOn, paths)
+		if ready {
+			p.record(ctx, nil)
+			return nil
+		} else if mismatch != nil {
+			lastMismatch = mismatch
+			p.record(ctx, mismatch)
+		} else {
+			p.record(ctx, fmt.Errorf("'%s' doesn't exist", path))
+		}
+		if err := p.wait(ctx); err != nil {
+			if err == context.DeadlineExceeded {
+				if lastMismatch != nil {
+					return fmt.Errorf("timeout for checking file '%s' (%s): %w", dependsOn.URL.String(), lastMismatch.Error(), err)
+				}
+				return fmt.Errorf("timeout for checking file '%s': %w", dependsOn.URL.String(), err)
+			}
+			return err
+		}
+	}
+}
+
+// checkFiles returns true if the matched files satisfy conditions
+//
+// mismatch is nil when no file matches, so the caller can distinguish it from unsatisfied conditions.
+func checkFiles(dependsOn DependsOn, paths []string) (ready bool, mismatch error) {
+	if dependsOn.NotExists {
+		if len(paths) == 0 {
+			return true, nil
+		}
+		return false, fmt.Errorf("'%s' still exists", paths[0])
+	}
+	for _, path := range paths {
+		if mismatch = checkFile(dependsOn, path); mismatch == nil {
+			return true, nil
+		}
+	}
+	return false, mismatch
+}
+
+// filePath returns the path of file URL
+//
+// "?" of glob pattern starts query in URL, so the query is joined to the path again.
+func filePath(u *url.URL) string {
+	if u.RawQuery == "" && !u.ForceQuery {
+		return u.Path
+	}
+	query, err := url.PathUnescape(u.RawQuery)
+	if err != nil {
+		query = u.RawQuery
+	}
+	return u.Path + "?" + query
+}
+
+// matchFiles returns existing files of the path. The path can be glob pattern.
+func matchFiles(path string) ([]string, error) {
+	if strings.ContainsAny(path, "*?[") {
+		return filepath.Glob(path)
+	}
+	_, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return []string{path}, nil
+}
+
+// checkFile checks size, modified time and content of the file
+func checkFile(dependsOn DependsOn, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Size() < dependsOn.MinSize {
+		return fmt.Errorf("size of '%s' is %d bytes (minSize: %d)", path, info.Size(), dependsOn.MinSize)
+	}
+	if dependsOn.MaxAge > 0 {
+		if age := time.Since(info.ModTime()); age > dependsOn.MaxAge {
+			return fmt.Errorf("'%s' was modified %s ago (maxAge: %s)", path, age.Truncate(time.Second), dependsOn.MaxAge)
+		}
+	}
+	if dependsOn.Contains == "" && dependsOn.Regexp == nil {
+		return nil
+	}
+	if info.IsDir() {
+		return fmt.Errorf("'%s' is a directory", path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	content, err := ioutil.ReadAll(io.LimitReader(f, maxResponseBodySize))
+	if err != nil {
+		return err
+	}
+	if dependsOn.Contains != "" && !bytes.Contains(content, []byte(dependsOn.Contains)) {
+		return fmt.Errorf("'%s' doesn't contain %q", path, dependsOn.Contains)
+	}
+	if dependsOn.Regexp != nil && !dependsOn.Regexp.Match(content) {
+		return fmt.Errorf("'%s' doesn't match with pattern %q", path, dependsOn.Regexp.String())
+	}
+	return nil
+}
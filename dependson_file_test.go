@@ -0,0 +1,184 @@
+package docradle
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForDependencies_FileConditions(t *testing.T) {
+	testcases := []struct {
+		name      string
+		path      string
+		pattern   string
+		content   string
+		age       time.Duration
+		dependsOn DependsOn
+		ok        bool
+		errorText string
+	}{
+		{
+			name:      "ok: contains",
+			path:      "marker.txt",
+			content:   "status: READY\n",
+			dependsOn: DependsOn{Contains: "READY"},
+			ok:        true,
+		},
+		{
+			name:      "ng: doesn't contain",
+			path:      "marker.txt",
+			content:   "status: STARTING\n",
+			dependsOn: DependsOn{Contains: "READY"},
+			ok:        false,
+			errorText: `doesn't contain "READY"`,
+		},
+		{
+			name:      "ok: regexp",
+			path:      "marker.txt",
+			content:   "status=ok\n",
+			dependsOn: DependsOn{Regexp: regexp.MustCompile(`(?m)^status=(ok|ready)$`)},
+			ok:        true,
+		},
+		{
+			name:      "ng: regexp",
+			path:      "marker.txt",
+			content:   "status=ng\n",
+			dependsOn: DependsOn{Regexp: regexp.MustCompile(`(?m)^status=(ok|ready)$`)},
+			ok:        false,
+			errorText: "doesn't match with pattern",
+		},
+		{
+			name:      "ok: min size",
+			path:      "data.csv",
+			content:   "a,b,c\n",
+			dependsOn: DependsOn{MinSize: 1},
+			ok:        true,
+		},
+		{
+			name:      "ng: empty file",
+			path:      "data.csv",
+			dependsOn: DependsOn{MinSize: 1},
+			ok:        false,
+			errorText: "is 0 bytes (minSize: 1)",
+		},
+		{
+			name:      "ok: fresh file",
+			path:      "heartbeat",
+			dependsOn: DependsOn{MaxAge: time.Minute},
+			ok:        true,
+		},
+		{
+			name:      "ng: stale file",
+			path:      "heartbeat",
+			age:       time.Hour,
+			dependsOn: DependsOn{MaxAge: time.Minute},
+			ok:        false,
+			errorText: "maxAge: 1m0s",
+		},
+		{
+			name:      "ok: glob",
+			path:      "batch-20200101.done",
+			pattern:   "batch-*.done",
+			dependsOn: DependsOn{},
+			ok:        true,
+		},
+		{
+			name:      "ng: glob doesn't match",
+			path:      "batch-20200101.running",
+			pattern:   "batch-*.done",
+			dependsOn: DependsOn{},
+			ok:        false,
+			errorText: "timeout for checking file",
+		},
+		{
+			name:      "ng: file exists",
+			path:      "app.lock",
+			dependsOn: DependsOn{NotExists: true},
+			ok:        false,
+			errorText: "still exists",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			dirPath, err := ioutil.TempDir("", "cradle")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dirPath)
+
+			filePath := filepath.Join(dirPath, tt.path)
+			assert.NoError(t, ioutil.WriteFile(filePath, []byte(tt.content), 0644))
+			if tt.age > 0 {
+				modified := time.Now().Add(-tt.age)
+				assert.NoError(t, os.Chtimes(filePath, modified, modified))
+			}
+			target := filePath
+			if tt.pattern != "" {
+				target = filepath.Join(dirPath, tt.pattern)
+			}
+
+			dependsOn := tt.dependsOn
+			dependsOn.URL = mustUrlParse(t, toUrl(t, "file", target))
+			dependsOn.Timeout = time.Millisecond * 50
+			dependsOn.Interval = time.Millisecond * 5
+			results := WaitForDependencies(context.Background(), []DependsOn{dependsOn}, NewEnvVar(), nil)
+			assert.Len(t, results, 1)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Contains(t, results[0].Error().Error(), tt.errorText)
+			}
+		})
+	}
+}
+
+func TestWaitForDependencies_FileNotExists(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "cradle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+
+	lockFile := filepath.Join(dirPath, "app.lock")
+	assert.NoError(t, ioutil.WriteFile(lockFile, nil, 0644))
+	go func() {
+		time.Sleep(time.Millisecond * 20)
+		os.Remove(lockFile)
+	}()
+
+	results := WaitForDependencies(context.Background(), []DependsOn{
+		{
+			URL:       mustUrlParse(t, toUrl(t, "file", lockFile)),
+			NotExists: true,
+			Timeout:   time.Second,
+			Interval:  time.Millisecond * 5,
+		},
+	}, NewEnvVar(), nil)
+	assert.Len(t, results, 1)
+	assert.NoError(t, results[0].Error())
+}
+
+func TestWaitForDependencies_FileQuestionMark(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "cradle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dirPath, "batch-1.done"), nil, 0644))
+
+	// "?" is parsed as the beginning of query
+	for _, pattern := range []string{"batch-?.done", "batch-%3F.done", "batch-?.d?ne"} {
+		t.Run(pattern, func(t *testing.T) {
+			results := WaitForDependencies(context.Background(), []DependsOn{
+				{
+					URL:      mustUrlParse(t, "file://"+filepath.ToSlash(dirPath)+"/"+pattern),
+					Timeout:  time.Millisecond * 50,
+					Interval: time.Millisecond * 5,
+				},
+			}, NewEnvVar(), nil)
+			assert.Len(t, results, 1)
+			assert.NoError(t, results[0].Error())
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package docradle
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// waitForGRPC calls grpc.health.v1.Health/Check until the service becomes SERVING
+//
+// Service name is taken from URL path (grpc://host:port/service). Empty name means the server's overall health.
+func waitForGRPC(ctx context.Context, dependsOn DependsOn, envvar *EnvVar, result *DependsOnCheckResult) error {
+	tlsConfig, err := probeTLSConfig(dependsOn, envvar, dependsOn.URL.Scheme == "grpcs")
+	if err != nil {
+		return err
+	}
+	defaultPort := "80"
+	if tlsConfig != nil {
+		defaultPort = "443"
+	}
+	address := hostWithDefaultPort(dependsOn.URL, defaultPort)
+	service := strings.TrimPrefix(dependsOn.URL.Path, "/")
+	md := metadata.MD{}
+	for _, header := range dependsOn.Headers {
+		md.Append(header[0], header[1])
+	}
+	return pollUntilReady(ctx, dependsOn, result, func(ctx context.Context) error {
+		state, err := probeGRPC(metadata.NewOutgoingContext(ctx, md), address, tlsConfig, service)
+		if err == nil && state != nil {
+			checkCertificateExpiry(dependsOn, state, result)
+		}
+		return err
+	})
+}
+
+func probeGRPC(ctx context.Context, address string, tlsConfig *tls.Config, service string) (*tls.ConnectionState, error) {
+	// dial error is returned as is to tell that the server doesn't exist yet
+	dialErrs := make(chan error, 1)
+	options := []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+			dialer := &net.Dialer{}
+			conn, err := dialer.DialContext(ctx, "tcp", address)
+			if err != nil {
+				select {
+				case dialErrs <- err:
+				default:
+				}
+			}
+			return conn, err
+		}),
+	}
+	if tlsConfig != nil {
+		options = append(options, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		options = append(options, grpc.WithInsecure())
+	}
+	conn, err := grpc.DialContext(ctx, address, options...)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var p peer.Peer
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{
+		Service: service,
+	}, grpc.Peer(&p))
+	if err != nil {
+		if status.Code(err) == codes.Unavailable {
+			select {
+			case dialErr := <-dialErrs:
+				return nil, dialErr
+			default:
+			}
+		}
+		return nil, err
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		return nil, fmt.Errorf("service status is %s", resp.Status)
+	}
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		return &tlsInfo.State, nil
+	}
+	return nil, nil
+}
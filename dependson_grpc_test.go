@@ -0,0 +1,182 @@
+package docradle
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authHealthServer rejects requests without authorization metadata
+type authHealthServer struct {
+	*health.Server
+	token string
+}
+
+func (s *authHealthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("authorization"); len(values) == 0 || values[0] != "Bearer "+s.token {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return s.Server.Check(ctx, req)
+}
+
+func TestWaitForDependencies_GRPC(t *testing.T) {
+	dirPath := filepath.Join(os.TempDir(), "cradle"+xid.New().String())
+	os.MkdirAll(dirPath, 0755)
+	defer os.RemoveAll(dirPath)
+
+	certPath, _, cert := writeCertificate(t, dirPath, "server", time.Now().Add(24*time.Hour))
+
+	testcases := []struct {
+		name      string
+		service   string
+		status    grpc_health_v1.HealthCheckResponse_ServingStatus
+		serveAt   time.Duration // status becomes SERVING after this duration
+		token     string
+		headers   [][2]string
+		tls       bool
+		ok        bool
+		errorText string
+	}{
+		{
+			name:   "ok: server",
+			status: grpc_health_v1.HealthCheckResponse_SERVING,
+			ok:     true,
+		},
+		{
+			name:    "ok: service",
+			service: "app.Greeter",
+			status:  grpc_health_v1.HealthCheckResponse_SERVING,
+			ok:      true,
+		},
+		{
+			name:    "ok: wait for serving",
+			service: "app.Greeter",
+			status:  grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+			serveAt: 50 * time.Millisecond,
+			ok:      true,
+		},
+		{
+			name:      "ng: not serving",
+			service:   "app.Greeter",
+			status:    grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+			ok:        false,
+			errorText: "service status is NOT_SERVING",
+		},
+		{
+			name:      "ng: unknown service",
+			service:   "app.Unknown",
+			status:    grpc_health_v1.HealthCheckResponse_SERVING,
+			ok:        false,
+			errorText: "code = NotFound",
+		},
+		{
+			name:    "ok: metadata",
+			status:  grpc_health_v1.HealthCheckResponse_SERVING,
+			token:   "12345",
+			headers: [][2]string{{"Authorization", "Bearer 12345"}},
+			ok:      true,
+		},
+		{
+			name:      "ng: without metadata",
+			status:    grpc_health_v1.HealthCheckResponse_SERVING,
+			token:     "12345",
+			ok:        false,
+			errorText: "code = Unauthenticated",
+		},
+		{
+			name:   "ok: tls",
+			status: grpc_health_v1.HealthCheckResponse_SERVING,
+			tls:    true,
+			ok:     true,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			assert.NoError(t, err)
+			var options []grpc.ServerOption
+			if tt.tls {
+				options = append(options, grpc.Creds(credentials.NewTLS(&tls.Config{
+					Certificates: []tls.Certificate{cert},
+				})))
+			}
+			server := grpc.NewServer(options...)
+			healthServer := health.NewServer()
+			healthServer.SetServingStatus("", tt.status)
+			healthServer.SetServingStatus("app.Greeter", tt.status)
+			if tt.token != "" {
+				grpc_health_v1.RegisterHealthServer(server, &authHealthServer{Server: healthServer, token: tt.token})
+			} else {
+				grpc_health_v1.RegisterHealthServer(server, healthServer)
+			}
+			go server.Serve(listener)
+			defer server.Stop()
+			if tt.serveAt > 0 {
+				timer := time.AfterFunc(tt.serveAt, func() {
+					healthServer.SetServingStatus("app.Greeter", grpc_health_v1.HealthCheckResponse_SERVING)
+				})
+				defer timer.Stop()
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			dependsOn := DependsOn{
+				URL:      mustUrlParse(t, "grpc://"+listener.Addr().String()+"/"+tt.service),
+				Headers:  tt.headers,
+				Timeout:  time.Millisecond * 300,
+				Interval: time.Millisecond * 10,
+			}
+			if tt.tls {
+				dependsOn.TLS = &TLSConfig{CA: certPath, WarnExpiry: 30 * 24 * time.Hour}
+			}
+			results := WaitForDependencies(ctx, []DependsOn{dependsOn}, NewEnvVar(), nil)
+			assert.Len(t, results, 1)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Contains(t, results[0].Error().Error(), tt.errorText)
+			}
+			if tt.tls {
+				assert.Len(t, results[0].warnings, 1)
+			}
+		})
+	}
+}
+
+func TestWaitForDependencies_GRPCNotExist(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	results := WaitForDependencies(ctx, []DependsOn{
+		{
+			URL:      mustUrlParse(t, "grpc://"+address),
+			Timeout:  time.Millisecond * 100,
+			Interval: time.Millisecond * 10,
+		},
+	}, NewEnvVar(), nil)
+	assert.Len(t, results, 1)
+	assert.Error(t, results[0].Error())
+	assert.Contains(t, results[0].String(), "Target service doesn't exist")
+}
@@ -0,0 +1,112 @@
+package docradle
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// waitForKafka fetches cluster metadata until it succeeds
+//
+// URL is like kafka://broker1:9092,broker2:9092?topic=logs. If topic is specified, it waits until the topic exists.
+// User and password are sent by SASL/PLAIN.
+func waitForKafka(ctx context.Context, dependsOn DependsOn, envvar *EnvVar, result *DependsOnCheckResult) error {
+	tlsConfig, err := probeTLSConfig(dependsOn, envvar, false)
+	if err != nil {
+		return err
+	}
+	if tlsConfig != nil && dependsOn.TLS.ServerName == "" {
+		// sarama uses the host name of each broker
+		tlsConfig.ServerName = ""
+	}
+	var brokers []string
+	for _, host := range strings.Split(dependsOn.URL.Host, ",") {
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(host, "9092")
+		}
+		brokers = append(brokers, host)
+	}
+	user, password := probeCredential(dependsOn, envvar)
+	topic := dependsOn.URL.Query().Get("topic")
+	return pollUntilReady(ctx, dependsOn, result, func(ctx context.Context) error {
+		return probeKafka(ctx, brokers, tlsConfig, user, password, topic)
+	})
+}
+
+// probeKafka fetches metadata of the cluster (and the topic)
+//
+// sarama doesn't accept context, so the probe runs in background. Its timeouts come from ctx deadline
+// and the client is closed when ctx is done not to leave broker connections.
+func probeKafka(ctx context.Context, brokers []string, tlsConfig *tls.Config, user, password, topic string) error {
+	timeout := 10 * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+		if timeout <= 0 {
+			// sarama rejects non-positive timeouts
+			return context.DeadlineExceeded
+		}
+	}
+	config := sarama.NewConfig()
+	config.ClientID = "docradle"
+	config.Net.DialTimeout = timeout
+	config.Net.ReadTimeout = timeout
+	config.Net.WriteTimeout = timeout
+	config.Metadata.Retry.Max = 0
+	if tlsConfig != nil {
+		config.Net.TLS.Enable = true
+		config.Net.TLS.Config = tlsConfig
+	}
+	if user != "" {
+		config.Net.SASL.Enable = true
+		config.Net.SASL.User = user
+		config.Net.SASL.Password = password
+	}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- fetchKafkaMetadata(ctx, brokers, config, topic)
+	}()
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func fetchKafkaMetadata(ctx context.Context, brokers []string, config *sarama.Config, topic string) error {
+	client, err := sarama.NewClient(brokers, config)
+	if err != nil {
+		return err
+	}
+	var closeOnce sync.Once
+	closeClient := func() {
+		closeOnce.Do(func() {
+			client.Close()
+		})
+	}
+	defer closeClient()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			closeClient()
+		case <-done:
+		}
+	}()
+	if topic == "" {
+		return nil
+	}
+	partitions, err := client.Partitions(topic)
+	if errors.Is(err, sarama.ErrUnknownTopicOrPartition) || (err == nil && len(partitions) == 0) {
+		return fmt.Errorf("topic '%s' doesn't exist", topic)
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package docradle
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForDependencies_Kafka(t *testing.T) {
+	testcases := []struct {
+		name      string
+		url       string
+		deadFirst bool
+		ok        bool
+		errorText string
+	}{
+		{
+			name: "ok: metadata",
+			url:  "kafka://%s",
+			ok:   true,
+		},
+		{
+			name: "ok: topic exists",
+			url:  "kafka://%s?topic=logs",
+			ok:   true,
+		},
+		{
+			name:      "ng: topic doesn't exist",
+			url:       "kafka://%s?topic=unknown",
+			ok:        false,
+			errorText: "topic 'unknown' doesn't exist",
+		},
+		{
+			name:      "ok: one of brokers is alive",
+			url:       "kafka://%s",
+			deadFirst: true,
+			ok:        true,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := sarama.NewMockBroker(t, 1)
+			defer broker.Close()
+			broker.SetHandlerByMap(map[string]sarama.MockResponse{
+				"MetadataRequest": sarama.NewMockMetadataResponse(t).
+					SetBroker(broker.Addr(), broker.BrokerID()).
+					SetLeader("logs", 0, broker.BrokerID()),
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			brokers := broker.Addr()
+			if tt.deadFirst {
+				brokers = closedAddress(t) + "," + brokers
+			}
+			u := fmt.Sprintf(tt.url, brokers)
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					URL:      mustUrlParse(t, u),
+					Timeout:  time.Millisecond * 300,
+					Interval: time.Millisecond * 5,
+				},
+			}, NewEnvVar(), nil)
+			assert.Len(t, results, 1)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Contains(t, results[0].Error().Error(), tt.errorText)
+			}
+		})
+	}
+}
+
+func TestWaitForDependencies_KafkaNotRunning(t *testing.T) {
+	results := WaitForDependencies(context.Background(), []DependsOn{
+		{
+			URL:      mustUrlParse(t, "kafka://"+closedAddress(t)),
+			Timeout:  time.Millisecond * 100,
+			Interval: time.Millisecond * 10,
+		},
+	}, NewEnvVar(), nil)
+	assert.Len(t, results, 1)
+	assert.Error(t, results[0].Error())
+}
+
+// closedAddress returns address that nobody listens
+func closedAddress(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+	return addr
+}
@@ -0,0 +1,366 @@
+package docradle
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+const (
+	mysqlClientLongPassword   = 0x00000001
+	mysqlClientConnectWithDB  = 0x00000008
+	mysqlClientProtocol41     = 0x00000200
+	mysqlClientSSL            = 0x00000800
+	mysqlClientTransactions   = 0x00002000
+	mysqlClientSecureConn     = 0x00008000
+	mysqlClientPluginAuth     = 0x00080000
+	mysqlMaxPacketSize        = 16*1024*1024 - 1
+	mysqlCharsetUTF8MB4       = 45
+	mysqlComQuit              = 0x01
+	mysqlComQuery             = 0x03
+	mysqlNativePassword       = "mysql_native_password"
+	mysqlCachingSHA2Password  = "caching_sha2_password"
+	mysqlFastAuthSuccess      = 0x03
+	mysqlPerformFullAuth      = 0x04
+	mysqlRequestPublicKeyByte = 0x02
+)
+
+func waitForMySQL(ctx context.Context, dependsOn DependsOn, envvar *EnvVar, result *DependsOnCheckResult) error {
+	tlsConfig, err := probeTLSConfig(dependsOn, envvar, false)
+	if err != nil {
+		return err
+	}
+	user, password := probeCredential(dependsOn, envvar)
+	if user == "" {
+		user = "root"
+	}
+	database := strings.TrimPrefix(dependsOn.URL.Path, "/")
+	address := hostWithDefaultPort(dependsOn.URL, "3306")
+	return pollUntilReady(ctx, dependsOn, result, func(ctx context.Context) error {
+		return probeMySQL(ctx, address, tlsConfig, user, password, database, envvar.Expand(dependsOn.Query))
+	})
+}
+
+type mysqlConn struct {
+	conn     net.Conn
+	reader   *bufio.Reader
+	sequence byte
+	secure   bool
+}
+
+func probeMySQL(ctx context.Context, address string, tlsConfig *tls.Config, user, password, database, query string) error {
+	conn, err := dialProbe(ctx, address, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	my := &mysqlConn{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+	handshake, err := my.receive()
+	if err != nil {
+		return err
+	}
+	if len(handshake) > 0 && handshake[0] == 0xff {
+		return mysqlError(handshake)
+	}
+	scramble, plugin, capabilities, err := parseMySQLHandshake(handshake)
+	if err != nil {
+		return err
+	}
+
+	flags := uint32(mysqlClientLongPassword | mysqlClientProtocol41 | mysqlClientTransactions | mysqlClientSecureConn | mysqlClientPluginAuth)
+	if database != "" {
+		flags |= mysqlClientConnectWithDB
+	}
+	if tlsConfig != nil {
+		if capabilities&mysqlClientSSL == 0 {
+			return errors.New("server doesn't support SSL")
+		}
+		flags |= mysqlClientSSL
+		if err := my.send(mysqlHandshakeHeader(flags)); err != nil {
+			return err
+		}
+		my.conn = tls.Client(conn, tlsConfig)
+		my.reader = bufio.NewReader(my.conn)
+		my.secure = true
+	}
+
+	authResponse, err := mysqlAuthResponse(plugin, password, scramble)
+	if err != nil {
+		return err
+	}
+	var response bytes.Buffer
+	response.Write(mysqlHandshakeHeader(flags))
+	response.WriteString(user)
+	response.WriteByte(0)
+	response.WriteByte(byte(len(authResponse)))
+	response.Write(authResponse)
+	if database != "" {
+		response.WriteString(database)
+		response.WriteByte(0)
+	}
+	response.WriteString(plugin)
+	response.WriteByte(0)
+	if err := my.send(response.Bytes()); err != nil {
+		return err
+	}
+	if err := my.authenticate(plugin, password, scramble); err != nil {
+		return err
+	}
+	defer func() {
+		my.sequence = 0
+		my.send([]byte{mysqlComQuit})
+	}()
+	if query == "" {
+		return nil
+	}
+	my.sequence = 0
+	if err := my.send(append([]byte{mysqlComQuery}, query...)); err != nil {
+		return err
+	}
+	if err := my.readQueryResult(); err != nil {
+		return fmt.Errorf("probe query failed: %w", err)
+	}
+	return nil
+}
+
+func mysqlHandshakeHeader(flags uint32) []byte {
+	header := make([]byte, 32)
+	binary.LittleEndian.PutUint32(header[0:4], flags)
+	binary.LittleEndian.PutUint32(header[4:8], mysqlMaxPacketSize)
+	header[8] = mysqlCharsetUTF8MB4
+	return header
+}
+
+// parseMySQLHandshake parses initial handshake packet (protocol version 10)
+func parseMySQLHandshake(packet []byte) (scramble []byte, plugin string, capabilities uint32, err error) {
+	if len(packet) < 1 || packet[0] != 10 {
+		return nil, "", 0, errors.New("unsupported MySQL protocol version")
+	}
+	pos := bytes.IndexByte(packet[1:], 0)
+	if pos == -1 {
+		return nil, "", 0, errors.New("invalid MySQL handshake packet")
+	}
+	pos += 2 // server version and terminator
+	pos += 4 // connection id
+	if len(packet) < pos+8+1+2+1+2+2+1+10 {
+		return nil, "", 0, errors.New("invalid MySQL handshake packet")
+	}
+	scramble = append(scramble, packet[pos:pos+8]...)
+	pos += 8 + 1 // auth-plugin-data-part-1 and filler
+	capabilities = uint32(binary.LittleEndian.Uint16(packet[pos : pos+2]))
+	pos += 2 + 1 + 2 // capability flags (lower), character set and status flags
+	capabilities |= uint32(binary.LittleEndian.Uint16(packet[pos:pos+2])) << 16
+	pos += 2
+	authDataLength := int(packet[pos])
+	pos += 1 + 10 // auth data length and reserved
+	if capabilities&mysqlClientSecureConn != 0 {
+		length := authDataLength - 8
+		if length < 13 {
+			length = 13
+		}
+		if len(packet) < pos+length {
+			return nil, "", 0, errors.New("invalid MySQL handshake packet")
+		}
+		scramble = append(scramble, bytes.TrimRight(packet[pos:pos+length], "\x00")...)
+		pos += length
+	}
+	plugin = mysqlNativePassword
+	if capabilities&mysqlClientPluginAuth != 0 && pos < len(packet) {
+		plugin = string(bytes.TrimRight(packet[pos:], "\x00"))
+	}
+	return scramble, plugin, capabilities, nil
+}
+
+func mysqlAuthResponse(plugin, password string, scramble []byte) ([]byte, error) {
+	if password == "" {
+		return nil, nil
+	}
+	switch plugin {
+	case mysqlNativePassword:
+		// SHA1(password) XOR SHA1(scramble + SHA1(SHA1(password)))
+		stage1 := sha1.Sum([]byte(password))
+		stage2 := sha1.Sum(stage1[:])
+		h := sha1.New()
+		h.Write(scramble)
+		h.Write(stage2[:])
+		result := h.Sum(nil)
+		for i := range result {
+			result[i] ^= stage1[i]
+		}
+		return result, nil
+	case mysqlCachingSHA2Password:
+		// SHA256(password) XOR SHA256(SHA256(SHA256(password)) + scramble)
+		stage1 := sha256.Sum256([]byte(password))
+		stage2 := sha256.Sum256(stage1[:])
+		h := sha256.New()
+		h.Write(stage2[:])
+		h.Write(scramble)
+		result := h.Sum(nil)
+		for i := range result {
+			result[i] ^= stage1[i]
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("unsupported authentication plugin '%s'", plugin)
+}
+
+func (my *mysqlConn) authenticate(plugin, password string, scramble []byte) error {
+	for {
+		packet, err := my.receive()
+		if err != nil {
+			return err
+		}
+		if len(packet) == 0 {
+			return errors.New("empty packet during authentication")
+		}
+		switch packet[0] {
+		case 0x00: // OK
+			return nil
+		case 0xff: // ERR
+			return mysqlError(packet)
+		case 0xfe: // AuthSwitchRequest
+			end := bytes.IndexByte(packet[1:], 0)
+			if end == -1 {
+				return errors.New("invalid auth switch request")
+			}
+			plugin = string(packet[1 : end+1])
+			scramble = bytes.TrimRight(packet[end+2:], "\x00")
+			response, err := mysqlAuthResponse(plugin, password, scramble)
+			if err != nil {
+				return err
+			}
+			if err := my.send(response); err != nil {
+				return err
+			}
+		case 0x01: // AuthMoreData
+			if plugin != mysqlCachingSHA2Password || len(packet) < 2 {
+				return errors.New("unexpected auth more data")
+			}
+			switch packet[1] {
+			case mysqlFastAuthSuccess:
+				// OK packet follows
+			case mysqlPerformFullAuth:
+				if my.secure {
+					if err := my.send(append([]byte(password), 0)); err != nil {
+						return err
+					}
+				} else if err := my.send([]byte{mysqlRequestPublicKeyByte}); err != nil {
+					return err
+				}
+			default:
+				// public key for full authentication
+				encrypted, err := mysqlEncryptPassword(packet[1:], password, scramble)
+				if err != nil {
+					return err
+				}
+				if err := my.send(encrypted); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("unexpected packet 0x%02x during authentication", packet[0])
+		}
+	}
+}
+
+func mysqlEncryptPassword(publicKeyPEM []byte, password string, scramble []byte) ([]byte, error) {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return nil, errors.New("invalid public key from server")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key from server: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key from server is not RSA")
+	}
+	if len(scramble) == 0 {
+		return nil, errors.New("no scramble from server to encrypt password")
+	}
+	plain := append([]byte(password), 0)
+	for i := range plain {
+		plain[i] ^= scramble[i%len(scramble)]
+	}
+	return rsa.EncryptOAEP(sha1.New(), rand.Reader, rsaKey, plain, nil)
+}
+
+// readQueryResult reads response of COM_QUERY. Result set is skipped.
+func (my *mysqlConn) readQueryResult() error {
+	eofCount := 0
+	for {
+		packet, err := my.receive()
+		if err != nil {
+			return err
+		}
+		if len(packet) == 0 {
+			continue
+		}
+		switch {
+		case packet[0] == 0xff:
+			return mysqlError(packet)
+		case packet[0] == 0x00 && eofCount == 0:
+			return nil
+		case packet[0] == 0xfe && len(packet) < 9:
+			eofCount++
+			if eofCount == 2 {
+				return nil
+			}
+		}
+	}
+}
+
+func (my *mysqlConn) send(payload []byte) error {
+	header := make([]byte, 4)
+	header[0] = byte(len(payload))
+	header[1] = byte(len(payload) >> 8)
+	header[2] = byte(len(payload) >> 16)
+	header[3] = my.sequence
+	my.sequence++
+	_, err := my.conn.Write(append(header, payload...))
+	return err
+}
+
+func (my *mysqlConn) receive() ([]byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(my.reader, header); err != nil {
+		return nil, err
+	}
+	length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	my.sequence = header[3] + 1
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(my.reader, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// mysqlError converts ERR packet to error like "ERROR 1045 (28000): Access denied"
+func mysqlError(packet []byte) error {
+	if len(packet) < 3 {
+		return errors.New("unknown MySQL error")
+	}
+	code := binary.LittleEndian.Uint16(packet[1:3])
+	message := packet[3:]
+	if len(message) > 6 && message[0] == '#' {
+		return fmt.Errorf("ERROR %d (%s): %s", code, message[1:6], message[6:])
+	}
+	return fmt.Errorf("ERROR %d: %s", code, message)
+}
@@ -0,0 +1,298 @@
+package docradle
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMySQLServer speaks minimal MySQL client/server protocol
+type fakeMySQLServer struct {
+	listener net.Listener
+	plugin   string
+	user     string
+	password string
+	starting int32 // returns "Too many connections" error for first n connections
+	fullAuth bool  // caching_sha2_password requires full authentication via RSA
+	switched bool  // sends AuthSwitchRequest to caching_sha2_password without plugin data
+	key      *rsa.PrivateKey
+}
+
+var fakeMySQLScramble = []byte("abcdefghijklmnopqrst")
+
+func newFakeMySQLServer(t *testing.T, plugin, user, password string, starting int32, fullAuth, switched bool) *fakeMySQLServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	s := &fakeMySQLServer{
+		listener: listener,
+		plugin:   plugin,
+		user:     user,
+		password: password,
+		starting: starting,
+		fullAuth: fullAuth,
+		switched: switched,
+	}
+	if fullAuth || switched {
+		s.key, err = rsa.GenerateKey(rand.Reader, 1024)
+		if err != nil {
+			panic(err)
+		}
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeMySQLServer) Close() {
+	s.listener.Close()
+}
+
+func (s *fakeMySQLServer) send(w io.Writer, sequence byte, payload []byte) {
+	header := []byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), sequence}
+	w.Write(append(header, payload...))
+}
+
+func (s *fakeMySQLServer) receive(r io.Reader) (byte, []byte) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil
+	}
+	payload := make([]byte, int(header[0])|int(header[1])<<8|int(header[2])<<16)
+	io.ReadFull(r, payload)
+	return header[3], payload
+}
+
+func (s *fakeMySQLServer) sendError(w io.Writer, sequence byte, code uint16, state, message string) {
+	payload := []byte{0xff, byte(code), byte(code >> 8)}
+	payload = append(payload, "#"+state+message...)
+	s.send(w, sequence, payload)
+}
+
+func (s *fakeMySQLServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	if atomic.AddInt32(&s.starting, -1) >= 0 {
+		s.sendError(conn, 0, 1040, "08004", "Too many connections")
+		return
+	}
+	var handshake bytes.Buffer
+	handshake.WriteByte(10)
+	handshake.WriteString("8.0.19\x00")
+	handshake.Write([]byte{1, 0, 0, 0})
+	handshake.Write(fakeMySQLScramble[:8])
+	handshake.WriteByte(0)
+	capabilities := uint32(mysqlClientLongPassword | mysqlClientConnectWithDB | mysqlClientProtocol41 | mysqlClientTransactions | mysqlClientSecureConn | mysqlClientPluginAuth)
+	binary.Write(&handshake, binary.LittleEndian, uint16(capabilities))
+	handshake.WriteByte(mysqlCharsetUTF8MB4)
+	handshake.Write([]byte{2, 0})
+	binary.Write(&handshake, binary.LittleEndian, uint16(capabilities>>16))
+	handshake.WriteByte(21)
+	handshake.Write(make([]byte, 10))
+	handshake.Write(fakeMySQLScramble[8:])
+	handshake.WriteByte(0)
+	handshake.WriteString(s.plugin + "\x00")
+	s.send(conn, 0, handshake.Bytes())
+
+	sequence, response := s.receive(reader)
+	if len(response) < 33 {
+		return
+	}
+	pos := 32
+	end := bytes.IndexByte(response[pos:], 0)
+	user := string(response[pos : pos+end])
+	pos += end + 1
+	authResponse := response[pos+1 : pos+1+int(response[pos])]
+
+	if s.switched {
+		s.send(conn, sequence+1, []byte("\xfe"+mysqlCachingSHA2Password+"\x00"))
+		sequence, _ = s.receive(reader)
+		s.send(conn, sequence+1, []byte{0x01, mysqlPerformFullAuth})
+		sequence, _ = s.receive(reader)
+		der, _ := x509.MarshalPKIXPublicKey(&s.key.PublicKey)
+		s.send(conn, sequence+1, append([]byte{0x01}, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})...))
+		s.receive(reader)
+		return
+	}
+	ok := user == s.user
+	switch s.plugin {
+	case mysqlNativePassword:
+		expected, _ := mysqlAuthResponse(mysqlNativePassword, s.password, fakeMySQLScramble)
+		ok = ok && bytes.Equal(authResponse, expected)
+	case mysqlCachingSHA2Password:
+		expected, _ := mysqlAuthResponse(mysqlCachingSHA2Password, s.password, fakeMySQLScramble)
+		ok = ok && bytes.Equal(authResponse, expected)
+		if ok && s.fullAuth {
+			s.send(conn, sequence+1, []byte{0x01, mysqlPerformFullAuth})
+			sequence, response = s.receive(reader)
+			if !bytes.Equal(response, []byte{mysqlRequestPublicKeyByte}) {
+				ok = false
+				break
+			}
+			der, _ := x509.MarshalPKIXPublicKey(&s.key.PublicKey)
+			s.send(conn, sequence+1, append([]byte{0x01}, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})...))
+			sequence, response = s.receive(reader)
+			plain, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, s.key, response, nil)
+			if err != nil {
+				ok = false
+				break
+			}
+			for i := range plain {
+				plain[i] ^= fakeMySQLScramble[i%len(fakeMySQLScramble)]
+			}
+			ok = string(plain) == s.password+"\x00"
+		} else if ok {
+			s.send(conn, sequence+1, []byte{0x01, mysqlFastAuthSuccess})
+			sequence++
+		}
+	}
+	if !ok {
+		s.sendError(conn, sequence+1, 1045, "28000", "Access denied for user '"+user+"'")
+		return
+	}
+	s.send(conn, sequence+1, []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00})
+	for {
+		_, command := s.receive(reader)
+		if len(command) == 0 || command[0] != mysqlComQuery {
+			return
+		}
+		query := string(command[1:])
+		switch {
+		case strings.Contains(query, "missing_table"):
+			s.sendError(conn, 1, 1146, "42S02", "Table 'app.missing_table' doesn't exist")
+		case strings.HasPrefix(query, "SELECT"):
+			s.send(conn, 1, []byte{0x01})
+			s.send(conn, 2, []byte("\x03def\x00\x00\x00\x011\x00\x0c\x3f\x00\x01\x00\x00\x00\x08\x81\x00\x00\x00\x00"))
+			s.send(conn, 3, []byte{0xfe, 0x00, 0x00, 0x02, 0x00})
+			s.send(conn, 4, []byte{0x01, '1'})
+			s.send(conn, 5, []byte{0xfe, 0x00, 0x00, 0x02, 0x00})
+		default:
+			s.send(conn, 1, []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00})
+		}
+	}
+}
+
+func TestWaitForDependencies_MySQL(t *testing.T) {
+	testcases := []struct {
+		name      string
+		plugin    string
+		url       string
+		user      string
+		query     string
+		starting  int32
+		fullAuth  bool
+		switched  bool
+		ok        bool
+		errorText string
+	}{
+		{
+			name:   "ok: mysql_native_password",
+			plugin: mysqlNativePassword,
+			url:    "mysql://app:secret@%s/app",
+			ok:     true,
+		},
+		{
+			name:   "ok: caching_sha2_password fast auth",
+			plugin: mysqlCachingSHA2Password,
+			url:    "mysql://:$DB_PASSWORD@%s/app",
+			user:   "${DB_USER}",
+			ok:     true,
+		},
+		{
+			name:     "ok: caching_sha2_password full auth",
+			plugin:   mysqlCachingSHA2Password,
+			url:      "mysql://app:secret@%s/app",
+			fullAuth: true,
+			ok:       true,
+		},
+		{
+			name:      "ng: wrong password",
+			plugin:    mysqlNativePassword,
+			url:       "mysql://app:wrong@%s/app",
+			ok:        false,
+			errorText: "ERROR 1045 (28000): Access denied",
+		},
+		{
+			name:      "ng: auth switch request without plugin data",
+			plugin:    mysqlNativePassword,
+			url:       "mysql://app:secret@%s/app",
+			switched:  true,
+			ok:        false,
+			errorText: "no scramble from server",
+		},
+		{
+			name:     "ok: wait for connection",
+			plugin:   mysqlNativePassword,
+			url:      "mysql://app:secret@%s/app",
+			starting: 2,
+			ok:       true,
+		},
+		{
+			name:   "ok: probe query",
+			plugin: mysqlNativePassword,
+			url:    "mysql://app:secret@%s/app",
+			query:  "SELECT 1",
+			ok:     true,
+		},
+		{
+			name:      "ng: probe query failed",
+			plugin:    mysqlNativePassword,
+			url:       "mysql://app:secret@%s/app",
+			query:     "SELECT count(*) FROM missing_table",
+			ok:        false,
+			errorText: "probe query failed: ERROR 1146 (42S02)",
+		},
+	}
+	envvar := NewEnvVar()
+	envvar.Register(fromOsEnv, "DB_USER", "app")
+	envvar.Register(fromOsEnv, "DB_PASSWORD", "secret")
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeMySQLServer(t, tt.plugin, "app", "secret", tt.starting, tt.fullAuth, tt.switched)
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					URL:      mustUrlParse(t, strings.Replace(tt.url, "%s", server.listener.Addr().String(), 1)),
+					User:     tt.user,
+					Query:    tt.query,
+					Timeout:  time.Millisecond * 300,
+					Interval: time.Millisecond * 5,
+				},
+			}, envvar, nil)
+			assert.Len(t, results, 1)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Contains(t, results[0].Error().Error(), tt.errorText)
+			}
+		})
+	}
+}
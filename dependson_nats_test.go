@@ -0,0 +1,156 @@
+package docradle
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeNATSServer speaks minimal NATS client protocol and JetStream stream info API
+type fakeNATSServer struct {
+	listener net.Listener
+	token    string
+	streams  []string
+}
+
+func newFakeNATSServer(t *testing.T, token string, streams ...string) *fakeNATSServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	s := &fakeNATSServer{
+		listener: listener,
+		token:    token,
+		streams:  streams,
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeNATSServer) Close() {
+	s.listener.Close()
+}
+
+func (s *fakeNATSServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	io.WriteString(conn, `INFO {"server_id":"fake","version":"2.2.0","auth_required":`+fmt.Sprint(s.token != "")+`}`+"\r\n")
+	inbox := ""
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "CONNECT":
+			var options natsConnectOptions
+			json.Unmarshal([]byte(strings.TrimPrefix(strings.TrimSpace(line), "CONNECT ")), &options)
+			if s.token != "" && options.AuthToken != s.token {
+				io.WriteString(conn, "-ERR 'Authorization Violation'\r\n")
+				return
+			}
+		case "PING":
+			io.WriteString(conn, "PING\r\nPONG\r\n")
+		case "PONG":
+		case "SUB":
+			inbox = fields[1]
+		case "PUB":
+			reader.ReadString('\n') // empty payload
+			stream := strings.TrimPrefix(fields[1], "$JS.API.STREAM.INFO.")
+			response := `{"error":{"code":404,"description":"stream not found"}}`
+			for _, name := range s.streams {
+				if name == stream {
+					response = `{"config":{"name":"` + name + `"}}`
+				}
+			}
+			fmt.Fprintf(conn, "MSG %s 1 %d\r\n%s\r\n", inbox, len(response), response)
+		}
+	}
+}
+
+func TestWaitForDependencies_NATS(t *testing.T) {
+	testcases := []struct {
+		name      string
+		token     string
+		url       string
+		ok        bool
+		errorText string
+	}{
+		{
+			name: "ok: no auth",
+			url:  "nats://%s",
+			ok:   true,
+		},
+		{
+			name:  "ok: token from envvar",
+			token: "s3cr3t",
+			url:   "nats://$NATS_TOKEN@%s",
+			ok:    true,
+		},
+		{
+			name:      "ng: wrong token",
+			token:     "s3cr3t",
+			url:       "nats://wrong@%s",
+			ok:        false,
+			errorText: "Authorization Violation",
+		},
+		{
+			name: "ok: stream exists",
+			url:  "nats://%s?stream=ORDERS",
+			ok:   true,
+		},
+		{
+			name:      "ng: stream doesn't exist",
+			url:       "nats://%s?stream=UNKNOWN",
+			ok:        false,
+			errorText: "stream 'UNKNOWN' is not available: stream not found",
+		},
+	}
+	envvar := NewEnvVar()
+	envvar.Register(fromOsEnv, "NATS_TOKEN", "s3cr3t")
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeNATSServer(t, tt.token, "ORDERS")
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					URL:      mustUrlParse(t, fmt.Sprintf(tt.url, server.listener.Addr().String())),
+					Timeout:  time.Millisecond * 300,
+					Interval: time.Millisecond * 5,
+				},
+			}, envvar, nil)
+			assert.Len(t, results, 1)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Contains(t, results[0].Error().Error(), tt.errorText)
+			}
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package docradle
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	postgresProtocolVersion = 196608
+	postgresSSLRequestCode  = 80877103
+)
+
+// Limits to protect docradle from broken servers. Probe messages are small.
+const (
+	maxPostgresMessageLength = 16 * 1024 * 1024
+	// PostgreSQL uses 4096 by default
+	maxScramIterations = 100000
+)
+
+func waitForPostgres(ctx context.Context, dependsOn DependsOn, envvar *EnvVar, result *DependsOnCheckResult) error {
+	sslMode := dependsOn.URL.Query().Get("sslmode")
+	tlsConfig, err := probeTLSConfig(dependsOn, envvar, sslMode == "require" || sslMode == "verify-ca" || sslMode == "verify-full")
+	if err != nil {
+		return err
+	}
+	if tlsConfig != nil && dependsOn.TLS == nil && sslMode == "require" {
+		// sslmode=require doesn't verify server certificate like libpq
+		tlsConfig.InsecureSkipVerify = true
+	}
+	user, password := probeCredential(dependsOn, envvar)
+	if user == "" {
+		user = "postgres"
+	}
+	database := strings.TrimPrefix(dependsOn.URL.Path, "/")
+	if database == "" {
+		database = user
+	}
+	address := hostWithDefaultPort(dependsOn.URL, "5432")
+	return pollUntilReady(ctx, dependsOn, result, func(ctx context.Context) error {
+		return probePostgres(ctx, address, tlsConfig, user, password, database, envvar.Expand(dependsOn.Query))
+	})
+}
+
+type postgresConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func probePostgres(ctx context.Context, address string, tlsConfig *tls.Config, user, password, database, query string) error {
+	conn, err := dialProbe(ctx, address, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if tlsConfig != nil {
+		conn, err = startPostgresTLS(conn, tlsConfig)
+		if err != nil {
+			return err
+		}
+	}
+	pg := &postgresConn{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+	defer pg.send('X', nil)
+
+	var startup bytes.Buffer
+	binary.Write(&startup, binary.BigEndian, int32(postgresProtocolVersion))
+	for _, param := range []string{"user", user, "database", database, "application_name", "docradle"} {
+		startup.WriteString(param)
+		startup.WriteByte(0)
+	}
+	startup.WriteByte(0)
+	if err := pg.send(0, startup.Bytes()); err != nil {
+		return err
+	}
+	if err := pg.authenticate(user, password); err != nil {
+		return err
+	}
+	if err := pg.waitReady(); err != nil {
+		return err
+	}
+	if query == "" {
+		return nil
+	}
+	if err := pg.send('Q', append([]byte(query), 0)); err != nil {
+		return err
+	}
+	if err := pg.waitReady(); err != nil {
+		return fmt.Errorf("probe query failed: %w", err)
+	}
+	return nil
+}
+
+func startPostgresTLS(conn net.Conn, tlsConfig *tls.Config) (net.Conn, error) {
+	request := make([]byte, 8)
+	binary.BigEndian.PutUint32(request[0:4], 8)
+	binary.BigEndian.PutUint32(request[4:8], postgresSSLRequestCode)
+	if _, err := conn.Write(request); err != nil {
+		return nil, err
+	}
+	response := make([]byte, 1)
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return nil, err
+	}
+	if response[0] != 'S' {
+		return nil, errors.New("server doesn't support SSL")
+	}
+	return tls.Client(conn, tlsConfig), nil
+}
+
+// send sends message. If messageType is 0, it sends message without type (for startup message)
+func (pg *postgresConn) send(messageType byte, body []byte) error {
+	var message []byte
+	if messageType != 0 {
+		message = append(message, messageType)
+	}
+	length := make([]byte, 4)
+	binary.BigEndian.PutUint32(length, uint32(len(body)+4))
+	message = append(message, length...)
+	message = append(message, body...)
+	_, err := pg.conn.Write(message)
+	return err
+}
+
+func (pg *postgresConn) receive() (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(pg.reader, header); err != nil {
+		return 0, nil, err
+	}
+	length := int(binary.BigEndian.Uint32(header[1:5]))
+	if length < 4 {
+		return 0, nil, fmt.Errorf("invalid message length %d", length)
+	} else if length > maxPostgresMessageLength {
+		return 0, nil, fmt.Errorf("message is too large: %d bytes", length)
+	}
+	body := make([]byte, length-4)
+	if _, err := io.ReadFull(pg.reader, body); err != nil {
+		return 0, nil, err
+	}
+	return header[0], body, nil
+}
+
+func (pg *postgresConn) authenticate(user, password string) error {
+	var scram *scramClient
+	for {
+		messageType, body, err := pg.receive()
+		if err != nil {
+			return err
+		}
+		switch messageType {
+		case 'E':
+			return postgresError(body)
+		case 'R':
+		default:
+			return fmt.Errorf("unexpected message '%c' during authentication", messageType)
+		}
+		if len(body) < 4 {
+			return errors.New("invalid authentication message")
+		}
+		switch code := binary.BigEndian.Uint32(body[0:4]); code {
+		case 0: // AuthenticationOk
+			return nil
+		case 3: // AuthenticationCleartextPassword
+			if err := pg.send('p', append([]byte(password), 0)); err != nil {
+				return err
+			}
+		case 5: // AuthenticationMD5Password
+			if len(body) < 8 {
+				return errors.New("invalid md5 authentication message")
+			}
+			inner := md5.Sum([]byte(password + user))
+			outer := md5.Sum(append([]byte(hex.EncodeToString(inner[:])), body[4:8]...))
+			if err := pg.send('p', append([]byte("md5"+hex.EncodeToString(outer[:])), 0)); err != nil {
+				return err
+			}
+		case 10: // AuthenticationSASL
+			if !bytes.Contains(body[4:], []byte("SCRAM-SHA-256\x00")) {
+				return errors.New("server requires unsupported SASL mechanism")
+			}
+			scram = newScramClient(password)
+			first := scram.clientFirstMessage()
+			var message bytes.Buffer
+			message.WriteString("SCRAM-SHA-256")
+			message.WriteByte(0)
+			binary.Write(&message, binary.BigEndian, int32(len(first)))
+			message.WriteString(first)
+			if err := pg.send('p', message.Bytes()); err != nil {
+				return err
+			}
+		case 11: // AuthenticationSASLContinue
+			if scram == nil {
+				return errors.New("unexpected SASL continue message")
+			}
+			final, err := scram.clientFinalMessage(string(body[4:]))
+			if err != nil {
+				return err
+			}
+			if err := pg.send('p', []byte(final)); err != nil {
+				return err
+			}
+		case 12: // AuthenticationSASLFinal
+			if scram == nil {
+				return errors.New("unexpected SASL final message")
+			}
+			if err := scram.verifyServerFinalMessage(string(body[4:])); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported authentication method (%d)", code)
+		}
+	}
+}
+
+// waitReady reads messages until ReadyForQuery
+func (pg *postgresConn) waitReady() error {
+	var lastError error
+	for {
+		messageType, body, err := pg.receive()
+		if err != nil {
+			return err
+		}
+		switch messageType {
+		case 'E':
+			lastError = postgresError(body)
+		case 'Z':
+			return lastError
+		}
+	}
+}
+
+// postgresError converts ErrorResponse to error like "FATAL: the database system is starting up (57P03)"
+func postgresError(body []byte) error {
+	fields := make(map[byte]string)
+	for len(body) > 1 {
+		end := bytes.IndexByte(body[1:], 0)
+		if end == -1 {
+			break
+		}
+		fields[body[0]] = string(body[1 : end+1])
+		body = body[end+2:]
+	}
+	return fmt.Errorf("%s: %s (%s)", fields['S'], fields['M'], fields['C'])
+}
+
+// scramClient implements client side of SCRAM-SHA-256 (RFC 7677)
+type scramClient struct {
+	password        string
+	clientNonce     string
+	clientFirstBare string
+	saltedPassword  []byte
+	authMessage     string
+}
+
+func newScramClient(password string) *scramClient {
+	nonce := make([]byte, 18)
+	rand.Read(nonce)
+	return &scramClient{
+		password:    password,
+		clientNonce: base64.StdEncoding.EncodeToString(nonce),
+	}
+}
+
+func (s *scramClient) clientFirstMessage() string {
+	// PostgreSQL uses user name in startup message
+	s.clientFirstBare = "n=,r=" + s.clientNonce
+	return "n,," + s.clientFirstBare
+}
+
+func (s *scramClient) clientFinalMessage(serverFirst string) (string, error) {
+	var nonce, salt string
+	var iterations int
+	for _, attr := range strings.Split(serverFirst, ",") {
+		if len(attr) < 2 || attr[1] != '=' {
+			continue
+		}
+		switch attr[0] {
+		case 'r':
+			nonce = attr[2:]
+		case 's':
+			salt = attr[2:]
+		case 'i':
+			iterations, _ = strconv.Atoi(attr[2:])
+		}
+	}
+	if !strings.HasPrefix(nonce, s.clientNonce) || salt == "" || iterations < 1 {
+		return "", errors.New("invalid SCRAM server first message")
+	} else if iterations > maxScramIterations {
+		return "", fmt.Errorf("SCRAM iteration count is too large: %d", iterations)
+	}
+	saltBytes, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return "", fmt.Errorf("invalid SCRAM salt: %w", err)
+	}
+	s.saltedPassword = pbkdf2SHA256([]byte(s.password), saltBytes, iterations)
+	clientFinalWithoutProof := "c=biws,r=" + nonce
+	s.authMessage = s.clientFirstBare + "," + serverFirst + "," + clientFinalWithoutProof
+	clientKey := hmacSHA256(s.saltedPassword, []byte("Client Key"))
+	storedKey := sha256.Sum256(clientKey)
+	signature := hmacSHA256(storedKey[:], []byte(s.authMessage))
+	proof := make([]byte, len(clientKey))
+	for i := range clientKey {
+		proof[i] = clientKey[i] ^ signature[i]
+	}
+	return clientFinalWithoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof), nil
+}
+
+func (s *scramClient) verifyServerFinalMessage(serverFinal string) error {
+	if !strings.HasPrefix(serverFinal, "v=") {
+		return fmt.Errorf("SCRAM authentication error: %s", serverFinal)
+	}
+	serverKey := hmacSHA256(s.saltedPassword, []byte("Server Key"))
+	expected := base64.StdEncoding.EncodeToString(hmacSHA256(serverKey, []byte(s.authMessage)))
+	if serverFinal[2:] != expected {
+		return errors.New("SCRAM server signature mismatch")
+	}
+	return nil
+}
+
+func hmacSHA256(key, message []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(message)
+	return h.Sum(nil)
+}
+
+// pbkdf2SHA256 is PBKDF2 with HMAC-SHA256 that generates 32 bytes key
+func pbkdf2SHA256(password, salt []byte, iterations int) []byte {
+	u := hmacSHA256(password, append(append([]byte{}, salt...), 0, 0, 0, 1))
+	result := append([]byte{}, u...)
+	for i := 1; i < iterations; i++ {
+		u = hmacSHA256(password, u)
+		for j := range result {
+			result[j] ^= u[j]
+		}
+	}
+	return result
+}
@@ -0,0 +1,322 @@
+package docradle
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePostgresServer speaks minimal PostgreSQL wire protocol
+type fakePostgresServer struct {
+	listener net.Listener
+	auth     string // "trust", "password", "md5", "scram", "huge" (too large message), "scramhuge" (too many iterations)
+	user     string
+	password string
+	starting int32 // returns "starting up" error for first n connections
+}
+
+func newFakePostgresServer(t *testing.T, auth, user, password string, starting int32) *fakePostgresServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	s := &fakePostgresServer{
+		listener: listener,
+		auth:     auth,
+		user:     user,
+		password: password,
+		starting: starting,
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakePostgresServer) Close() {
+	s.listener.Close()
+}
+
+func (s *fakePostgresServer) send(w io.Writer, messageType byte, body []byte) {
+	length := make([]byte, 4)
+	binary.BigEndian.PutUint32(length, uint32(len(body)+4))
+	w.Write(append(append([]byte{messageType}, length...), body...))
+}
+
+func (s *fakePostgresServer) sendAuth(w io.Writer, code uint32, data []byte) {
+	body := make([]byte, 4)
+	binary.BigEndian.PutUint32(body, code)
+	s.send(w, 'R', append(body, data...))
+}
+
+func (s *fakePostgresServer) sendError(w io.Writer, severity, code, message string) {
+	var body bytes.Buffer
+	body.WriteString("S" + severity + "\x00C" + code + "\x00M" + message + "\x00\x00")
+	s.send(w, 'E', body.Bytes())
+}
+
+func (s *fakePostgresServer) receive(r io.Reader) (byte, []byte) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil
+	}
+	body := make([]byte, binary.BigEndian.Uint32(header[1:])-4)
+	io.ReadFull(r, body)
+	return header[0], body
+}
+
+func (s *fakePostgresServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	length := make([]byte, 4)
+	if _, err := io.ReadFull(reader, length); err != nil {
+		return
+	}
+	startup := make([]byte, binary.BigEndian.Uint32(length)-4)
+	io.ReadFull(reader, startup)
+	params := strings.Split(string(startup[4:]), "\x00")
+	user := ""
+	for i := 0; i+1 < len(params); i += 2 {
+		if params[i] == "user" {
+			user = params[i+1]
+		}
+	}
+	if atomic.AddInt32(&s.starting, -1) >= 0 {
+		s.sendError(conn, "FATAL", "57P03", "the database system is starting up")
+		return
+	}
+	ok := user == s.user
+	switch s.auth {
+	case "huge":
+		conn.Write([]byte{'R', 0xff, 0xff, 0xff, 0xff})
+		return
+	case "scramhuge":
+		s.sendAuth(conn, 10, []byte("SCRAM-SHA-256\x00\x00"))
+		_, body := s.receive(reader)
+		clientFirst := string(body)
+		clientNonce := clientFirst[strings.LastIndex(clientFirst, "r=")+2:]
+		s.sendAuth(conn, 11, []byte("r="+clientNonce+"server,s=ZmFrZXNhbHQ=,i=2147483647"))
+		s.receive(reader)
+		return
+	case "password":
+		s.sendAuth(conn, 3, nil)
+		_, body := s.receive(reader)
+		ok = ok && string(body) == s.password+"\x00"
+	case "md5":
+		salt := []byte{1, 2, 3, 4}
+		s.sendAuth(conn, 5, salt)
+		_, body := s.receive(reader)
+		inner := md5.Sum([]byte(s.password + s.user))
+		outer := md5.Sum(append([]byte(hex.EncodeToString(inner[:])), salt...))
+		ok = ok && string(body) == "md5"+hex.EncodeToString(outer[:])+"\x00"
+	case "scram":
+		s.sendAuth(conn, 10, []byte("SCRAM-SHA-256\x00\x00"))
+		_, body := s.receive(reader)
+		mechanismEnd := bytes.IndexByte(body, 0)
+		if mechanismEnd == -1 {
+			return
+		}
+		clientFirst := string(body[mechanismEnd+5:])
+		clientFirstBare := strings.TrimPrefix(clientFirst, "n,,")
+		clientNonce := strings.TrimPrefix(strings.Split(clientFirstBare, ",")[1], "r=")
+		salt := []byte("fakesalt")
+		serverFirst := "r=" + clientNonce + "server,s=" + base64.StdEncoding.EncodeToString(salt) + ",i=4096"
+		s.sendAuth(conn, 11, []byte(serverFirst))
+		_, body = s.receive(reader)
+		clientFinal := string(body)
+		proofIndex := strings.LastIndex(clientFinal, ",p=")
+		if proofIndex == -1 {
+			return
+		}
+		proof, _ := base64.StdEncoding.DecodeString(clientFinal[proofIndex+3:])
+		authMessage := clientFirstBare + "," + serverFirst + "," + clientFinal[:proofIndex]
+		saltedPassword := pbkdf2SHA256([]byte(s.password), salt, 4096)
+		storedKey := sha256.Sum256(hmacSHA256(saltedPassword, []byte("Client Key")))
+		signature := hmacSHA256(storedKey[:], []byte(authMessage))
+		clientKey := make([]byte, len(proof))
+		for i := range proof {
+			clientKey[i] = proof[i] ^ signature[i]
+		}
+		if sum := sha256.Sum256(clientKey); ok && bytes.Equal(sum[:], storedKey[:]) {
+			serverKey := hmacSHA256(saltedPassword, []byte("Server Key"))
+			s.sendAuth(conn, 12, []byte("v="+base64.StdEncoding.EncodeToString(hmacSHA256(serverKey, []byte(authMessage)))))
+		} else {
+			ok = false
+		}
+	}
+	if !ok {
+		s.sendError(conn, "FATAL", "28P01", "password authentication failed for user \""+user+"\"")
+		return
+	}
+	s.sendAuth(conn, 0, nil)
+	s.send(conn, 'S', []byte("server_version\x0012.1\x00"))
+	s.send(conn, 'Z', []byte("I"))
+	for {
+		messageType, body := s.receive(reader)
+		switch messageType {
+		case 'Q':
+			query := strings.TrimRight(string(body), "\x00")
+			if strings.Contains(query, "missing_table") {
+				s.sendError(conn, "ERROR", "42P01", "relation \"missing_table\" does not exist")
+			} else {
+				s.send(conn, 'C', []byte("SELECT 1\x00"))
+			}
+			s.send(conn, 'Z', []byte("I"))
+		default:
+			return
+		}
+	}
+}
+
+func TestWaitForDependencies_Postgres(t *testing.T) {
+	testcases := []struct {
+		name      string
+		auth      string
+		url       string
+		password  string
+		query     string
+		starting  int32
+		ok        bool
+		errorText string
+	}{
+		{
+			name: "ok: trust",
+			auth: "trust",
+			url:  "postgres://app@%s/app",
+			ok:   true,
+		},
+		{
+			name: "ok: cleartext password",
+			auth: "password",
+			url:  "postgres://app:secret@%s/app",
+			ok:   true,
+		},
+		{
+			name:     "ok: md5 password from envvar",
+			auth:     "md5",
+			url:      "postgres://app@%s/app",
+			password: "${DB_PASSWORD}",
+			ok:       true,
+		},
+		{
+			name: "ok: scram-sha-256",
+			auth: "scram",
+			url:  "postgresql://app:$DB_PASSWORD@%s/app",
+			ok:   true,
+		},
+		{
+			name:      "ng: wrong password",
+			auth:      "scram",
+			url:       "postgres://app:wrong@%s/app",
+			ok:        false,
+			errorText: "password authentication failed",
+		},
+		{
+			name:      "ng: too large message",
+			auth:      "huge",
+			url:       "postgres://app@%s/app",
+			ok:        false,
+			errorText: "message is too large",
+		},
+		{
+			name:      "ng: too many scram iterations",
+			auth:      "scramhuge",
+			url:       "postgres://app:secret@%s/app",
+			ok:        false,
+			errorText: "SCRAM iteration count is too large",
+		},
+		{
+			name:     "ok: wait for starting up",
+			auth:     "md5",
+			url:      "postgres://app:secret@%s/app",
+			starting: 2,
+			ok:       true,
+		},
+		{
+			name:      "ng: still starting up",
+			auth:      "trust",
+			url:       "postgres://app@%s/app",
+			starting:  1000,
+			ok:        false,
+			errorText: "the database system is starting up (57P03)",
+		},
+		{
+			name:  "ok: probe query",
+			auth:  "trust",
+			url:   "postgres://app@%s/app",
+			query: "SELECT 1",
+			ok:    true,
+		},
+		{
+			name:      "ng: probe query failed",
+			auth:      "trust",
+			url:       "postgres://app@%s/app",
+			query:     "SELECT count(*) FROM missing_table",
+			ok:        false,
+			errorText: "probe query failed",
+		},
+	}
+	envvar := NewEnvVar()
+	envvar.Register(fromOsEnv, "DB_PASSWORD", "secret")
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakePostgresServer(t, tt.auth, "app", "secret", tt.starting)
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					URL:      mustUrlParse(t, strings.Replace(tt.url, "%s", server.listener.Addr().String(), 1)),
+					Password: tt.password,
+					Query:    tt.query,
+					Timeout:  time.Millisecond * 300,
+					Interval: time.Millisecond * 5,
+				},
+			}, envvar, nil)
+			assert.Len(t, results, 1)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Contains(t, results[0].Error().Error(), tt.errorText)
+				assert.NotContains(t, results[0].String(), "secret")
+			}
+		})
+	}
+}
+
+func TestScramClient(t *testing.T) {
+	// test vector from RFC 7677
+	s := &scramClient{
+		password:        "pencil",
+		clientNonce:     "rOprNGfwEbeRWgbNEkqO",
+		clientFirstBare: "n=user,r=rOprNGfwEbeRWgbNEkqO",
+	}
+	final, err := s.clientFinalMessage("r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096")
+	assert.NoError(t, err)
+	assert.Equal(t, "c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=", final)
+	assert.NoError(t, s.verifyServerFinalMessage("v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4="))
+	assert.Error(t, s.verifyServerFinalMessage("v=AAAA"))
+}
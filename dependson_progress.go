@@ -0,0 +1,167 @@
+package docradle
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gookit/color"
+	"github.com/mattn/go-isatty"
+	"github.com/rs/zerolog"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+const (
+	spinnerInterval        = 100 * time.Millisecond
+	defaultWaitingInterval = 5 * time.Second
+)
+
+// DependsOnProgress shows progress of dependency checks while waiting
+//
+// On terminal, it redraws spinner and elapsed time of each dependency and clears them when all checks finish.
+// Otherwise, it writes "waiting" log periodically.
+type DependsOnProgress struct {
+	writer   io.Writer
+	terminal bool
+	interval time.Duration
+
+	lock    sync.Mutex
+	start   time.Time
+	labels  []string
+	results []*DependsOnCheckResult
+	lines   int
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewDependsOnProgress creates DependsOnProgress that writes to writer
+func NewDependsOnProgress(writer io.Writer) *DependsOnProgress {
+	terminal := false
+	if f, ok := writer.(*os.File); ok {
+		if isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd()) {
+			terminal = true
+		}
+	}
+	return &DependsOnProgress{
+		writer:   writer,
+		terminal: terminal,
+		interval: defaultWaitingInterval,
+	}
+}
+
+func (p *DependsOnProgress) begin(dependsOns []DependsOn) {
+	if p == nil || len(dependsOns) == 0 {
+		return
+	}
+	p.start = time.Now()
+	p.labels = make([]string, len(dependsOns))
+	p.results = make([]*DependsOnCheckResult, len(dependsOns))
+	for i, dependsOn := range dependsOns {
+		p.labels[i] = newDependsOnCheckResult(dependsOn).label()
+	}
+	p.stop = make(chan struct{})
+	p.stopped = make(chan struct{})
+	if p.terminal {
+		p.draw(0)
+	}
+	go p.run()
+}
+
+func (p *DependsOnProgress) finish(i int, result DependsOnCheckResult) {
+	if p == nil || p.stop == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.results[i] = &result
+}
+
+func (p *DependsOnProgress) end() {
+	if p == nil || p.stop == nil {
+		return
+	}
+	close(p.stop)
+	<-p.stopped
+	p.stop = nil
+}
+
+func (p *DependsOnProgress) run() {
+	defer close(p.stopped)
+	interval := p.interval
+	if p.terminal {
+		interval = spinnerInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for frame := 1; ; frame++ {
+		select {
+		case <-p.stop:
+			if p.terminal {
+				p.clear()
+			}
+			return
+		case <-ticker.C:
+			if p.terminal {
+				p.draw(frame)
+			} else {
+				p.log()
+			}
+		}
+	}
+}
+
+// draw redraws status lines of all dependencies
+func (p *DependsOnProgress) draw(frame int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.lines > 0 {
+		fmt.Fprintf(p.writer, "\x1b[%dA", p.lines)
+	}
+	elapsed := time.Now().Sub(p.start).Truncate(100 * time.Millisecond)
+	for i, label := range p.labels {
+		io.WriteString(p.writer, "\x1b[2K")
+		result := p.results[i]
+		if result == nil {
+			color.Fprintf(p.writer, "  <cyan>%s</> <blue>%s</> <gray>%s</>\n", spinnerFrames[frame%len(spinnerFrames)], label, elapsed)
+		} else if result.Error() == nil {
+			color.Fprintf(p.writer, "  <green>✔</> <blue>%s</> <gray>%s</>\n", label, result.duration.Truncate(100*time.Millisecond))
+		} else {
+			color.Fprintf(p.writer, "  <red>✘</> <blue>%s</> <gray>%s</>\n", label, result.duration.Truncate(100*time.Millisecond))
+		}
+	}
+	p.lines = len(p.labels)
+}
+
+// clear removes status lines to show the final result
+func (p *DependsOnProgress) clear() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.lines > 0 {
+		fmt.Fprintf(p.writer, "\x1b[%dA\x1b[J", p.lines)
+		p.lines = 0
+	}
+}
+
+// log writes dependencies that are not ready yet
+func (p *DependsOnProgress) log() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var waiting []string
+	for i, label := range p.labels {
+		if p.results[i] == nil {
+			waiting = append(waiting, label)
+		}
+	}
+	if len(waiting) == 0 {
+		return
+	}
+	logger := zerolog.New(p.writer).With().Timestamp().Logger()
+	logger.Info().
+		Str(LogDocradleLogKey, "waiting").
+		Strs("dependencies", waiting).
+		Dur("elapsed", time.Now().Sub(p.start)).
+		Msg("still waiting for dependencies")
+}
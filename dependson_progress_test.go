@@ -0,0 +1,88 @@
+package docradle
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependsOnProgress(t *testing.T) {
+	testcases := []struct {
+		name     string
+		terminal bool
+		check    func(t *testing.T, output string)
+	}{
+		{
+			name:     "terminal",
+			terminal: true,
+			check: func(t *testing.T, output string) {
+				assert.Contains(t, output, "⠋")
+				assert.Contains(t, output, "late: file://")
+				assert.Contains(t, output, "✔")
+				// status lines are cleared at last
+				assert.True(t, strings.HasSuffix(output, "\x1b[2A\x1b[J"))
+			},
+		},
+		{
+			name:     "log",
+			terminal: false,
+			check: func(t *testing.T, output string) {
+				lines := strings.Split(strings.TrimSpace(output), "\n")
+				assert.True(t, len(lines) > 0)
+				assert.Contains(t, lines[0], `"level":"info","docradle-log":"waiting","dependencies":["late: file://`)
+				assert.NotContains(t, lines[0], "early")
+				assert.Contains(t, lines[0], `"message":"still waiting for dependencies"`)
+			},
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			dirPath := filepath.Join(os.TempDir(), "cradle"+xid.New().String())
+			os.MkdirAll(dirPath, 0755)
+			defer os.RemoveAll(dirPath)
+
+			earlyFile := filepath.Join(dirPath, "early.txt")
+			lateFile := filepath.Join(dirPath, "late.txt")
+			ioutil.WriteFile(earlyFile, nil, 0644)
+			timer := time.AfterFunc(300*time.Millisecond, func() {
+				ioutil.WriteFile(lateFile, nil, 0644)
+			})
+			defer timer.Stop()
+
+			var buffer bytes.Buffer
+			progress := NewDependsOnProgress(&buffer)
+			progress.terminal = tt.terminal
+			progress.interval = 100 * time.Millisecond
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					Name:     "early",
+					URL:      mustUrlParse(t, toUrl(t, "file", earlyFile)),
+					Timeout:  time.Millisecond * 500,
+					Interval: time.Millisecond * 10,
+				},
+				{
+					Name:     "late",
+					URL:      mustUrlParse(t, toUrl(t, "file", lateFile)),
+					Timeout:  time.Millisecond * 500,
+					Interval: time.Millisecond * 10,
+				},
+			}, NewEnvVar(), progress)
+			assert.Len(t, results, 2)
+			assert.NoError(t, results[0].Error())
+			assert.NoError(t, results[1].Error())
+			tt.check(t, buffer.String())
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package docradle
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+func waitForRedis(ctx context.Context, dependsOn DependsOn, envvar *EnvVar, result *DependsOnCheckResult) error {
+	tlsConfig, err := probeTLSConfig(dependsOn, envvar, dependsOn.URL.Scheme == "rediss")
+	if err != nil {
+		return err
+	}
+	user, password := probeCredential(dependsOn, envvar)
+	database := strings.TrimPrefix(dependsOn.URL.Path, "/")
+	address := hostWithDefaultPort(dependsOn.URL, "6379")
+	return pollUntilReady(ctx, dependsOn, result, func(ctx context.Context) error {
+		return probeRedis(ctx, address, tlsConfig, user, password, database, envvar.Expand(dependsOn.Query))
+	})
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func probeRedis(ctx context.Context, address string, tlsConfig *tls.Config, user, password, database, query string) error {
+	conn, err := dialProbe(ctx, address, tlsConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	r := &redisConn{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+	if password != "" {
+		if user != "" {
+			_, err = r.do("AUTH", user, password)
+		} else {
+			_, err = r.do("AUTH", password)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if database != "" {
+		if _, err := r.do("SELECT", database); err != nil {
+			return err
+		}
+	}
+	reply, err := r.do("PING")
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("unexpected reply for PING: %s", reply)
+	}
+	if args := strings.Fields(query); len(args) > 0 {
+		if _, err := r.do(args...); err != nil {
+			return fmt.Errorf("probe query failed: %w", err)
+		}
+	}
+	r.do("QUIT")
+	return nil
+}
+
+// Limits of RESP bulk strings and arrays in replies
+const (
+	maxRedisBulkLength = 16 * 1024 * 1024
+	maxRedisArrayCount = 1024 * 1024
+)
+
+// do sends command and returns reply. Error reply like "-LOADING ..." is returned as error.
+func (r *redisConn) do(args ...string) (string, error) {
+	var builder strings.Builder
+	builder.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		builder.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n")
+	}
+	if _, err := io.WriteString(r.conn, builder.String()); err != nil {
+		return "", err
+	}
+	return r.readReply()
+}
+
+func (r *redisConn) readReply() (string, error) {
+	line, err := r.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", errors.New("empty reply")
+	}
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+	case '-':
+		return "", errors.New(line[1:])
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return "", fmt.Errorf("invalid bulk string length: %s", line)
+		}
+		if length < 0 {
+			return "", nil
+		} else if length > maxRedisBulkLength {
+			return "", fmt.Errorf("bulk string is too large: %s", line)
+		}
+		content := make([]byte, length+2)
+		if _, err := io.ReadFull(r.reader, content); err != nil {
+			return "", err
+		}
+		return string(content[:length]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return "", fmt.Errorf("invalid array length: %s", line)
+		}
+		if count < 0 {
+			// null array like timeout of BLPOP
+			return "", nil
+		} else if count > maxRedisArrayCount {
+			return "", fmt.Errorf("array is too large: %s", line)
+		}
+		items := make([]string, 0, count)
+		for i := 0; i < count; i++ {
+			item, err := r.readReply()
+			if err != nil {
+				return "", err
+			}
+			items = append(items, item)
+		}
+		return strings.Join(items, " "), nil
+	}
+	return "", fmt.Errorf("unknown reply: %s", line)
+}
@@ -0,0 +1,258 @@
+package docradle
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRedisServer speaks minimal RESP protocol
+type fakeRedisServer struct {
+	listener net.Listener
+	user     string
+	password string
+	loading  int32 // returns LOADING error for first n PING commands
+}
+
+func newFakeRedisServer(t *testing.T, user, password string, loading int32) *fakeRedisServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	s := &fakeRedisServer{
+		listener: listener,
+		user:     user,
+		password: password,
+		loading:  loading,
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeRedisServer) Close() {
+	s.listener.Close()
+}
+
+func (s *fakeRedisServer) readCommand(reader *bufio.Reader) []string {
+	line, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "*") {
+		return nil
+	}
+	count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	args := make([]string, count)
+	for i := range args {
+		line, _ = reader.ReadString('\n')
+		length, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		arg := make([]byte, length+2)
+		io.ReadFull(reader, arg)
+		args[i] = string(arg[:length])
+	}
+	return args
+}
+
+func (s *fakeRedisServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authenticated := s.password == ""
+	for {
+		args := s.readCommand(reader)
+		if len(args) == 0 {
+			return
+		}
+		switch strings.ToUpper(args[0]) {
+		case "AUTH":
+			user, password := "default", args[len(args)-1]
+			if len(args) == 3 {
+				user = args[1]
+			}
+			if user == s.user && password == s.password {
+				authenticated = true
+				io.WriteString(conn, "+OK\r\n")
+			} else {
+				io.WriteString(conn, "-WRONGPASS invalid username-password pair\r\n")
+			}
+		case "QUIT":
+			io.WriteString(conn, "+OK\r\n")
+			return
+		default:
+			if !authenticated {
+				io.WriteString(conn, "-NOAUTH Authentication required.\r\n")
+				continue
+			}
+			switch strings.ToUpper(args[0]) {
+			case "SELECT":
+				if n, err := strconv.Atoi(args[1]); err != nil || n > 15 {
+					io.WriteString(conn, "-ERR DB index is out of range\r\n")
+				} else {
+					io.WriteString(conn, "+OK\r\n")
+				}
+			case "PING":
+				if atomic.AddInt32(&s.loading, -1) >= 0 {
+					io.WriteString(conn, "-LOADING Redis is loading the dataset in memory\r\n")
+				} else {
+					io.WriteString(conn, "+PONG\r\n")
+				}
+			case "EXISTS":
+				if args[1] == "ready" {
+					io.WriteString(conn, ":1\r\n")
+				} else {
+					io.WriteString(conn, ":0\r\n")
+				}
+			case "BLPOP":
+				io.WriteString(conn, "*-1\r\n")
+			case "DEBUG":
+				io.WriteString(conn, "$1099511627776\r\n")
+			case "INFO":
+				info := "# Server\r\nredis_version:5.0.7\r\n"
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(info), info)
+			default:
+				fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+			}
+		}
+	}
+}
+
+func TestWaitForDependencies_Redis(t *testing.T) {
+	testcases := []struct {
+		name      string
+		user      string
+		url       string
+		query     string
+		loading   int32
+		ok        bool
+		errorText string
+	}{
+		{
+			name: "ok: no auth",
+			url:  "redis://%s",
+			ok:   true,
+		},
+		{
+			name: "ok: password",
+			user: "default",
+			url:  "redis://:secret@%s/0",
+			ok:   true,
+		},
+		{
+			name: "ok: acl user from envvar",
+			user: "app",
+			url:  "redis://$REDIS_USER:$REDIS_PASSWORD@%s",
+			ok:   true,
+		},
+		{
+			name:      "ng: wrong password",
+			user:      "default",
+			url:       "redis://:wrong@%s",
+			ok:        false,
+			errorText: "WRONGPASS",
+		},
+		{
+			name:      "ng: no password",
+			user:      "default",
+			url:       "redis://%s",
+			ok:        false,
+			errorText: "NOAUTH",
+		},
+		{
+			name:    "ok: wait for loading",
+			url:     "redis://%s",
+			loading: 2,
+			ok:      true,
+		},
+		{
+			name:      "ng: still loading",
+			url:       "redis://%s",
+			loading:   1000,
+			ok:        false,
+			errorText: "LOADING",
+		},
+		{
+			name:      "ng: invalid database",
+			url:       "redis://%s/99",
+			ok:        false,
+			errorText: "DB index is out of range",
+		},
+		{
+			name:  "ok: probe query",
+			url:   "redis://%s",
+			query: "INFO server",
+			ok:    true,
+		},
+		{
+			name:  "ok: blank probe query is ignored",
+			url:   "redis://%s",
+			query: "  ",
+			ok:    true,
+		},
+		{
+			name:  "ok: null array reply",
+			url:   "redis://%s",
+			query: "BLPOP queue 1",
+			ok:    true,
+		},
+		{
+			name:      "ng: too large reply",
+			url:       "redis://%s",
+			query:     "DEBUG",
+			ok:        false,
+			errorText: "bulk string is too large",
+		},
+		{
+			name:      "ng: probe query failed",
+			url:       "redis://%s",
+			query:     "CLUSTER INFO",
+			ok:        false,
+			errorText: "probe query failed: ERR unknown command",
+		},
+	}
+	envvar := NewEnvVar()
+	envvar.Register(fromOsEnv, "REDIS_USER", "app")
+	envvar.Register(fromOsEnv, "REDIS_PASSWORD", "secret")
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			password := ""
+			if tt.user != "" {
+				password = "secret"
+			}
+			server := newFakeRedisServer(t, tt.user, password, tt.loading)
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					URL:      mustUrlParse(t, strings.Replace(tt.url, "%s", server.listener.Addr().String(), 1)),
+					Query:    tt.query,
+					Timeout:  time.Millisecond * 300,
+					Interval: time.Millisecond * 5,
+				},
+			}, envvar, nil)
+			assert.Len(t, results, 1)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Contains(t, results[0].Error().Error(), tt.errorText)
+			}
+		})
+	}
+}
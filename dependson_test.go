@@ -2,14 +2,19 @@ package docradle
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -33,7 +38,7 @@ func TestWaitForDependencies_UnknownSchema(t *testing.T) {
 			Timeout:  time.Second,
 			Interval: time.Second,
 		},
-	})
+	}, NewEnvVar(), nil)
 	assert.Len(t, results, 1)
 	assert.Error(t, results[0].Error())
 }
@@ -90,7 +95,7 @@ func TestWaitForDependencies_File(t *testing.T) {
 					Timeout:  time.Millisecond * 15,
 					Interval: time.Millisecond * 5,
 				},
-			})
+			}, NewEnvVar(), nil)
 			assert.Len(t, results, 1)
 			if tt.ok {
 				assert.NoError(t, results[0].Error())
@@ -179,7 +184,7 @@ func TestWaitForDependencies_TCP(t *testing.T) {
 					Timeout:  time.Millisecond * 15,
 					Interval: time.Millisecond * 5,
 				},
-			})
+			}, NewEnvVar(), nil)
 			assert.Len(t, results, 1)
 			if tt.ok {
 				assert.NoError(t, results[0].Error())
@@ -237,7 +242,7 @@ func TestWaitForDependencies_UnixDomainSocket(t *testing.T) {
 					Timeout:  time.Millisecond * 15,
 					Interval: time.Millisecond * 5,
 				},
-			})
+			}, NewEnvVar(), nil)
 			assert.Len(t, results, 1)
 			if tt.ok {
 				assert.NoError(t, results[0].Error())
@@ -332,7 +337,7 @@ func TestWaitForDependencies_HTTP(t *testing.T) {
 					Timeout:  time.Millisecond * 30,
 					Interval: time.Millisecond * 5,
 				},
-			})
+			}, NewEnvVar(), nil)
 			assert.Len(t, results, 1)
 			if tt.ok {
 				assert.NoError(t, results[0].Error())
@@ -342,3 +347,502 @@ func TestWaitForDependencies_HTTP(t *testing.T) {
 		})
 	}
 }
+
+func TestWaitForDependencies_HTTPConditions(t *testing.T) {
+	testcases := []struct {
+		name      string
+		port      string
+		dependsOn DependsOn
+		status    int
+		body      string
+		ok        bool
+		errorText string
+	}{
+		{
+			name:      "method not allowed",
+			port:      ":19889",
+			dependsOn: DependsOn{Method: "HEAD"},
+			status:    http.StatusOK,
+			ok:        false,
+			errorText: "405",
+		},
+		{
+			name:      "method match",
+			port:      ":19890",
+			dependsOn: DependsOn{Method: "GET"},
+			status:    http.StatusOK,
+			ok:        true,
+		},
+		{
+			name:      "expect status match",
+			port:      ":19891",
+			dependsOn: DependsOn{Method: "GET", ExpectStatus: [][2]int{{401, 401}, {500, 599}}},
+			status:    http.StatusServiceUnavailable,
+			ok:        true,
+		},
+		{
+			name:      "expect status not match",
+			port:      ":19892",
+			dependsOn: DependsOn{Method: "GET", ExpectStatus: [][2]int{{401, 401}}},
+			status:    http.StatusOK,
+			ok:        false,
+			errorText: "unexpected status 200 OK",
+		},
+		{
+			name:      "body contains",
+			port:      ":19893",
+			dependsOn: DependsOn{Method: "GET", BodyContains: "READY"},
+			status:    http.StatusOK,
+			body:      "status: READY",
+			ok:        true,
+		},
+		{
+			name:      "body doesn't contain",
+			port:      ":19894",
+			dependsOn: DependsOn{Method: "GET", BodyContains: "READY"},
+			status:    http.StatusOK,
+			body:      "status: STARTING",
+			ok:        false,
+			errorText: `response body doesn't contain "READY"`,
+		},
+		{
+			name:      "body regexp match",
+			port:      ":19895",
+			dependsOn: DependsOn{Method: "GET", BodyRegexp: regexp.MustCompile(`"status":\s*"(UP|OK)"`)},
+			status:    http.StatusOK,
+			body:      `{"status": "OK"}`,
+			ok:        true,
+		},
+		{
+			name:      "body regexp not match",
+			port:      ":19896",
+			dependsOn: DependsOn{Method: "GET", BodyRegexp: regexp.MustCompile(`"status":\s*"(UP|OK)"`)},
+			status:    http.StatusOK,
+			body:      `{"status": "DOWN"}`,
+			ok:        false,
+			errorText: "doesn't match with pattern",
+		},
+		{
+			name:      "json path match",
+			port:      ":19897",
+			dependsOn: DependsOn{Method: "GET", JSONPath: mustJSONPath(t, `$.status == "UP"`)},
+			status:    http.StatusOK,
+			body:      `{"status": "UP"}`,
+			ok:        true,
+		},
+		{
+			name:      "json path not match",
+			port:      ":19898",
+			dependsOn: DependsOn{Method: "GET", JSONPath: mustJSONPath(t, `$.status == "UP"`)},
+			status:    http.StatusOK,
+			body:      `{"status": "STARTING"}`,
+			ok:        false,
+			errorText: `(actual: "STARTING")`,
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &http.Server{
+				Addr: tt.port,
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method != "GET" {
+						w.WriteHeader(http.StatusMethodNotAllowed)
+						return
+					}
+					w.WriteHeader(tt.status)
+					io.WriteString(w, tt.body)
+				}),
+			}
+			go server.ListenAndServe()
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			dependsOn := tt.dependsOn
+			dependsOn.URL = mustUrlParse(t, "http://localhost"+tt.port+"/health")
+			dependsOn.Timeout = time.Millisecond * 50
+			dependsOn.Interval = time.Millisecond * 5
+			results := WaitForDependencies(ctx, []DependsOn{dependsOn}, NewEnvVar(), nil)
+			assert.Len(t, results, 1)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Contains(t, results[0].Error().Error(), tt.errorText)
+				assert.Contains(t, results[0].String(), "Target service is not ready")
+			}
+		})
+	}
+}
+
+func mustJSONPath(t *testing.T, src string) *JSONPathAssertion {
+	t.Helper()
+	a, err := ParseJSONPathAssertion(src)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func TestDependsOnDepths(t *testing.T) {
+	testcases := []struct {
+		name      string
+		dependsOn []DependsOn
+		depths    []int
+		errorText string
+	}{
+		{
+			name: "no after",
+			dependsOn: []DependsOn{
+				{Name: "a"},
+				{Name: "b"},
+				{Name: "c"},
+			},
+			depths: []int{0, 0, 0},
+		},
+		{
+			name: "after dependencies are deeper",
+			dependsOn: []DependsOn{
+				{Name: "db", After: []string{"vault"}},
+				{Name: "app", After: []string{"db", "cache"}},
+				{Name: "vault"},
+				{Name: "cache"},
+			},
+			depths: []int{1, 2, 0, 0},
+		},
+		{
+			name: "duplicated name",
+			dependsOn: []DependsOn{
+				{Name: "db"},
+				{Name: "db"},
+			},
+			errorText: "dependsOn's name 'db' is duplicated",
+		},
+		{
+			name: "unknown name",
+			dependsOn: []DependsOn{
+				{Name: "db", After: []string{"vault"}},
+			},
+			errorText: "dependsOn 'db: anyOf' waits for unknown dependency 'vault'",
+		},
+		{
+			name: "circular dependency",
+			dependsOn: []DependsOn{
+				{Name: "vault"},
+				{Name: "a", After: []string{"b"}},
+				{Name: "b", After: []string{"a", "vault"}},
+			},
+			errorText: "dependsOn has circular dependency: a: anyOf, b: anyOf",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			depths, err := dependsOnDepths(tt.dependsOn)
+			if tt.errorText != "" {
+				assert.EqualError(t, err, tt.errorText)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.depths, depths)
+			}
+		})
+	}
+}
+
+func TestWaitForDependencies_After(t *testing.T) {
+	testcases := []struct {
+		name  string
+		ready bool
+	}{
+		{
+			name:  "ok: wait for vault",
+			ready: true,
+		},
+		{
+			name:  "ng: vault is not ready",
+			ready: false,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			dirPath := filepath.Join(os.TempDir(), "cradle"+xid.New().String())
+			os.MkdirAll(dirPath, 0755)
+			defer os.RemoveAll(dirPath)
+
+			vaultFile := filepath.Join(dirPath, "vault.txt")
+			dbFile := filepath.Join(dirPath, "db.txt")
+			ioutil.WriteFile(dbFile, nil, 0644)
+			if tt.ready {
+				go func() {
+					time.Sleep(time.Millisecond * 20)
+					ioutil.WriteFile(vaultFile, nil, 0644)
+				}()
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					Name:     "db",
+					After:    []string{"vault"},
+					URL:      mustUrlParse(t, toUrl(t, "file", dbFile)),
+					Timeout:  time.Millisecond * 100,
+					Interval: time.Millisecond * 5,
+				},
+				{
+					Name:     "vault",
+					URL:      mustUrlParse(t, toUrl(t, "file", vaultFile)),
+					Timeout:  time.Millisecond * 100,
+					Interval: time.Millisecond * 5,
+				},
+			}, NewEnvVar(), nil)
+			assert.Len(t, results, 2)
+			// results are in declaration order
+			assert.Equal(t, "db", results[0].name)
+			assert.Equal(t, "vault", results[1].name)
+			assert.Contains(t, results[0].String(), "(after vault)")
+			// graph order is shown by indentation
+			assert.Equal(t, 1, results[0].depth)
+			assert.True(t, strings.HasPrefix(results[0].String(), "    <bg"), results[0].String())
+			assert.True(t, strings.HasPrefix(results[1].String(), "  <bg"), results[1].String())
+			if tt.ready {
+				assert.NoError(t, results[0].Error())
+				assert.NoError(t, results[1].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Error(t, results[1].Error())
+				assert.Contains(t, results[0].String(), "Skipped because 'vault' is not ready")
+			}
+		})
+	}
+}
+
+func TestWaitForDependencies_DependsOnTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	// dependsOnTimeout is shorter than timeout of each dependency
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	results := WaitForDependencies(ctx, []DependsOn{
+		{
+			Name:     "db",
+			URL:      mustUrlParse(t, "tcp://"+address),
+			Timeout:  10 * time.Second,
+			Interval: 10 * time.Millisecond,
+		},
+		{
+			Name:     "app",
+			After:    []string{"db"},
+			URL:      mustUrlParse(t, "tcp://"+address),
+			Timeout:  10 * time.Second,
+			Interval: 10 * time.Millisecond,
+		},
+	}, NewEnvVar(), nil)
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		var expired *expiredError
+		assert.True(t, errors.As(result.Error(), &expired))
+		assert.True(t, errors.Is(result.Error(), context.DeadlineExceeded))
+		assert.True(t, result.duration < time.Second, result.duration.String())
+		assert.NotContains(t, result.String(), "wait 10s")
+	}
+	assert.Contains(t, results[0].String(), "Target service is not ready until dependsOnTimeout expired")
+	assert.Contains(t, results[1].String(), "Not checked because dependsOnTimeout expired")
+}
+
+func TestWaitForDependencies_AnyOf(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ready     []bool
+		ok        bool
+		satisfied string
+	}{
+		{
+			name:      "ok: primary",
+			ready:     []bool{true, false},
+			ok:        true,
+			satisfied: "primary.txt",
+		},
+		{
+			name:      "ok: replica",
+			ready:     []bool{false, true},
+			ok:        true,
+			satisfied: "replica.txt",
+		},
+		{
+			name:  "ng: none",
+			ready: []bool{false, false},
+			ok:    false,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			dirPath := filepath.Join(os.TempDir(), "cradle"+xid.New().String())
+			os.MkdirAll(dirPath, 0755)
+			defer os.RemoveAll(dirPath)
+
+			var alternatives []DependsOn
+			for i, name := range []string{"primary.txt", "replica.txt"} {
+				filePath := filepath.Join(dirPath, name)
+				if tt.ready[i] {
+					ioutil.WriteFile(filePath, nil, 0644)
+				}
+				alternatives = append(alternatives, DependsOn{
+					URL:      mustUrlParse(t, toUrl(t, "file", filePath)),
+					Timeout:  time.Millisecond * 50,
+					Interval: time.Millisecond * 5,
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					Name:  "db",
+					AnyOf: alternatives,
+				},
+			}, NewEnvVar(), nil)
+			assert.Len(t, results, 1)
+			assert.Len(t, results[0].alternatives, 2)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+				assert.Regexp(t, regexp.MustCompile(`✔</> <blue>[^<]*`+regexp.QuoteMeta(tt.satisfied)), results[0].String())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Contains(t, results[0].String(), "None of alternatives is ready")
+			}
+		})
+	}
+}
+
+func TestPoller_NextDelay(t *testing.T) {
+	testcases := []struct {
+		name    string
+		backoff *Backoff
+		delays  []time.Duration
+	}{
+		{
+			name:   "fixed interval",
+			delays: []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name: "exponential",
+			backoff: &Backoff{
+				Multiplier: 2,
+			},
+			delays: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+		},
+		{
+			name: "initial and max",
+			backoff: &Backoff{
+				Initial:    100 * time.Millisecond,
+				Max:        time.Second,
+				Multiplier: 3,
+			},
+			delays: []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 900 * time.Millisecond, time.Second, time.Second},
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPoller(DependsOn{
+				Interval: time.Second,
+				Backoff:  tt.backoff,
+			}, &DependsOnCheckResult{})
+			for _, expected := range tt.delays {
+				assert.Equal(t, expected, p.nextDelay())
+			}
+		})
+	}
+}
+
+func TestPoller_Jitter(t *testing.T) {
+	p := newPoller(DependsOn{
+		Interval: time.Second,
+		Backoff: &Backoff{
+			Multiplier: 1,
+			Jitter:     0.5,
+		},
+	}, &DependsOnCheckResult{})
+	for i := 0; i < 100; i++ {
+		delay := p.nextDelay()
+		assert.True(t, delay > 500*time.Millisecond && delay <= time.Second, delay.String())
+	}
+}
+
+func TestWaitForDependencies_AttemptTimeout(t *testing.T) {
+	testcases := []struct {
+		name           string
+		attemptTimeout time.Duration
+	}{
+		{
+			name:           "ok: retry after hung request",
+			attemptTimeout: 50 * time.Millisecond,
+		},
+		{
+			name:           "ok: hung request is limited by interval without attemptTimeout",
+			attemptTimeout: 0,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			var count int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&count, 1) == 1 {
+					<-r.Context().Done()
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					URL:            mustUrlParse(t, server.URL),
+					Timeout:        time.Millisecond * 300,
+					Interval:       time.Millisecond * 10,
+					AttemptTimeout: tt.attemptTimeout,
+				},
+			}, NewEnvVar(), nil)
+			assert.Len(t, results, 1)
+			assert.NoError(t, results[0].Error())
+			assert.Equal(t, 2, results[0].attempts)
+			assert.Contains(t, results[0].String(), "2 attempts")
+		})
+	}
+}
+
+func TestWaitForDependencies_LastError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	results := WaitForDependencies(ctx, []DependsOn{
+		{
+			URL:      mustUrlParse(t, "tcp://"+address),
+			Timeout:  time.Millisecond * 100,
+			Interval: time.Millisecond * 10,
+			Backoff: &Backoff{
+				Initial:    time.Millisecond * 5,
+				Multiplier: 2,
+			},
+		},
+	}, NewEnvVar(), nil)
+	assert.Len(t, results, 1)
+	assert.Error(t, results[0].Error())
+	assert.True(t, results[0].attempts > 1)
+	assert.Error(t, results[0].lastError)
+	assert.Regexp(t, `Target service doesn't exist.*\(wait 100ms, \d+ attempts, last error: dial tcp .*connection refused\)`, results[0].String())
+}
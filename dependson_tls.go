@@ -0,0 +1,113 @@
+package docradle
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"time"
+)
+
+// newTLSConfig creates tls.Config for dependency check from config
+//
+// Paths and server name can contain environment variables like ${CERT_DIR}.
+// It returns nil if there is no TLS setting.
+func newTLSConfig(config *TLSConfig, envvar *EnvVar) (*tls.Config, error) {
+	if config == nil {
+		return nil, nil
+	}
+	result := &tls.Config{
+		ServerName:         envvar.Expand(config.ServerName),
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+	if config.CA != "" {
+		caPath := envvar.Expand(config.CA)
+		content, err := ioutil.ReadFile(caPath)
+		if err != nil {
+			return nil, fmt.Errorf("can't read CA file '%s': %w", caPath, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(content) {
+			return nil, fmt.Errorf("CA file '%s' doesn't contain any PEM certificates", caPath)
+		}
+		result.RootCAs = pool
+	}
+	if config.Cert != "" || config.Key != "" {
+		if config.Cert == "" || config.Key == "" {
+			return nil, errors.New("both of cert and key are needed for client certificate")
+		}
+		certPath := envvar.Expand(config.Cert)
+		keyPath := envvar.Expand(config.Key)
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("can't load client certificate '%s' and key '%s': %w", certPath, keyPath, err)
+		}
+		result.Certificates = []tls.Certificate{cert}
+	}
+	return result, nil
+}
+
+// checkCertificateExpiry adds warning to the result if the server certificate expires soon
+func checkCertificateExpiry(dependsOn DependsOn, state *tls.ConnectionState, result *DependsOnCheckResult) {
+	if dependsOn.TLS == nil || dependsOn.TLS.WarnExpiry == 0 || state == nil || len(state.PeerCertificates) == 0 {
+		return
+	}
+	leaf := state.PeerCertificates[0]
+	remaining := time.Until(leaf.NotAfter)
+	if remaining < 0 {
+		result.warnings = append(result.warnings, fmt.Sprintf("certificate of '%s' was expired at %s", leaf.Subject.CommonName, leaf.NotAfter.Format(time.RFC3339)))
+	} else if remaining < dependsOn.TLS.WarnExpiry {
+		result.warnings = append(result.warnings, fmt.Sprintf("certificate of '%s' will expire at %s", leaf.Subject.CommonName, leaf.NotAfter.Format(time.RFC3339)))
+	}
+}
+
+// probeTLSConfig returns TLS config for protocol aware checks
+//
+// It returns nil if TLS is not required by URL and there is no tls option.
+func probeTLSConfig(dependsOn DependsOn, envvar *EnvVar, required bool) (*tls.Config, error) {
+	tlsConfig, err := newTLSConfig(dependsOn.TLS, envvar)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		if !required {
+			return nil, nil
+		}
+		tlsConfig = &tls.Config{}
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = dependsOn.URL.Hostname()
+	}
+	return tlsConfig, nil
+}
+
+func waitForTLS(ctx context.Context, dependsOn DependsOn, envvar *EnvVar, result *DependsOnCheckResult) error {
+	tlsConfig, err := probeTLSConfig(dependsOn, envvar, true)
+	if err != nil {
+		return err
+	}
+	return pollUntilReady(ctx, dependsOn, result, func(ctx context.Context) error {
+		dialer := &net.Dialer{Timeout: dependsOn.Interval}
+		conn, err := dialer.DialContext(ctx, "tcp", dependsOn.URL.Host)
+		if err != nil {
+			return err
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		defer tlsConn.Close()
+		if deadline, ok := ctx.Deadline(); ok && dependsOn.AttemptTimeout > 0 {
+			tlsConn.SetDeadline(deadline)
+		} else {
+			tlsConn.SetDeadline(time.Now().Add(dependsOn.Interval))
+		}
+		if err := tlsConn.Handshake(); err != nil {
+			// the server is listening but handshake fails
+			return fmt.Errorf("handshake error: %w", err)
+		}
+		state := tlsConn.ConnectionState()
+		checkCertificateExpiry(dependsOn, &state, result)
+		return nil
+	})
+}
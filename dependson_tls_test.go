@@ -0,0 +1,243 @@
+package docradle
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeCertificate generates self-signed certificate for localhost and writes it as PEM files
+func writeCertificate(t *testing.T, dir, name string, notAfter time.Time) (certPath, keyPath string, cert tls.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		panic(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	certPath = filepath.Join(dir, name+".pem")
+	keyPath = filepath.Join(dir, name+"-key.pem")
+	if err := ioutil.WriteFile(certPath, certPEM, 0644); err != nil {
+		panic(err)
+	}
+	if err := ioutil.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		panic(err)
+	}
+	cert, err = tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+func TestWaitForDependencies_HTTPS(t *testing.T) {
+	dirPath := filepath.Join(os.TempDir(), "cradle"+xid.New().String())
+	os.MkdirAll(dirPath, 0755)
+	defer os.RemoveAll(dirPath)
+
+	serverCertPath, _, serverCert := writeCertificate(t, dirPath, "server", time.Now().Add(365*24*time.Hour))
+	clientCertPath, clientKeyPath, clientCert := writeCertificate(t, dirPath, "client", time.Now().Add(365*24*time.Hour))
+	clientLeaf, err := x509.ParseCertificate(clientCert.Certificate[0])
+	if err != nil {
+		panic(err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientLeaf)
+
+	envvar := NewEnvVar()
+	envvar.Register(fromOsEnv, "CERT_DIR", dirPath)
+
+	testcases := []struct {
+		name       string
+		clientAuth tls.ClientAuthType
+		tls        *TLSConfig
+		ok         bool
+	}{
+		{
+			name: "ok: custom CA",
+			tls:  &TLSConfig{CA: serverCertPath},
+			ok:   true,
+		},
+		{
+			name: "ok: path with envvar",
+			tls:  &TLSConfig{CA: "${CERT_DIR}/server.pem"},
+			ok:   true,
+		},
+		{
+			name: "ng: unknown authority",
+			ok:   false,
+		},
+		{
+			name: "ok: insecure",
+			tls:  &TLSConfig{InsecureSkipVerify: true},
+			ok:   true,
+		},
+		{
+			name:       "ok: client certificate",
+			clientAuth: tls.RequireAndVerifyClientCert,
+			tls:        &TLSConfig{CA: serverCertPath, Cert: clientCertPath, Key: clientKeyPath},
+			ok:         true,
+		},
+		{
+			name:       "ng: client certificate is missing",
+			clientAuth: tls.RequireAndVerifyClientCert,
+			tls:        &TLSConfig{CA: serverCertPath},
+			ok:         false,
+		},
+		{
+			name: "ng: server name mismatch",
+			tls:  &TLSConfig{CA: serverCertPath, ServerName: "example.com"},
+			ok:   false,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			server.TLS = &tls.Config{
+				Certificates: []tls.Certificate{serverCert},
+				ClientAuth:   tt.clientAuth,
+				ClientCAs:    clientCAs,
+			}
+			server.StartTLS()
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					URL:      mustUrlParse(t, server.URL),
+					Timeout:  time.Millisecond * 300,
+					Interval: time.Millisecond * 10,
+					TLS:      tt.tls,
+				},
+			}, envvar, nil)
+			assert.Len(t, results, 1)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+			}
+		})
+	}
+}
+
+func TestWaitForDependencies_TLS(t *testing.T) {
+	dirPath := filepath.Join(os.TempDir(), "cradle"+xid.New().String())
+	os.MkdirAll(dirPath, 0755)
+	defer os.RemoveAll(dirPath)
+
+	longCertPath, _, longCert := writeCertificate(t, dirPath, "long", time.Now().Add(365*24*time.Hour))
+	shortCertPath, _, shortCert := writeCertificate(t, dirPath, "short", time.Now().Add(24*time.Hour))
+
+	testcases := []struct {
+		name    string
+		cert    tls.Certificate
+		tls     *TLSConfig
+		ok      bool
+		warning bool
+	}{
+		{
+			name: "ok",
+			cert: longCert,
+			tls:  &TLSConfig{CA: longCertPath, WarnExpiry: 30 * 24 * time.Hour},
+			ok:   true,
+		},
+		{
+			name:    "ok with expiry warning",
+			cert:    shortCert,
+			tls:     &TLSConfig{CA: shortCertPath, WarnExpiry: 30 * 24 * time.Hour},
+			ok:      true,
+			warning: true,
+		},
+		{
+			name: "ng: handshake error",
+			cert: longCert,
+			tls:  &TLSConfig{CA: shortCertPath},
+			ok:   false,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+				Certificates: []tls.Certificate{tt.cert},
+			})
+			assert.NoError(t, err)
+			defer listener.Close()
+			go func() {
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+					conn.(*tls.Conn).Handshake()
+					conn.Close()
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					URL:      mustUrlParse(t, "tls://"+listener.Addr().String()),
+					Timeout:  time.Millisecond * 300,
+					Interval: time.Millisecond * 10,
+					TLS:      tt.tls,
+				},
+			}, NewEnvVar(), nil)
+			assert.Len(t, results, 1)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Contains(t, results[0].Error().Error(), "handshake error")
+			}
+			if tt.warning {
+				assert.Len(t, results[0].warnings, 1)
+				assert.Contains(t, results[0].String(), "warning: certificate of 'short' will expire")
+			} else {
+				assert.Len(t, results[0].warnings, 0)
+			}
+		})
+	}
+}
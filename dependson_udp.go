@@ -0,0 +1,65 @@
+package docradle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// udpRejectWait is the time to wait for ICMP port unreachable when no response is expected
+const udpRejectWait = 300 * time.Millisecond
+
+// waitForUDP sends datagram until the target answers or stops rejecting it
+//
+// UDP doesn't have handshake. If expect option exists, the response should contain it.
+// Otherwise the target is ready if ICMP port unreachable doesn't arrive.
+func waitForUDP(ctx context.Context, dependsOn DependsOn, envvar *EnvVar, result *DependsOnCheckResult) error {
+	payload := []byte(envvar.Expand(dependsOn.Send))
+	expect := []byte(envvar.Expand(dependsOn.Expect))
+	return pollUntilReady(ctx, dependsOn, result, func(ctx context.Context) error {
+		if len(expect) > 0 && dependsOn.AttemptTimeout == 0 {
+			// UDP is lossy. Send the payload again at the next attempt instead of waiting for the lost response
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, dependsOn.Interval)
+			defer cancel()
+		}
+		return probeUDP(ctx, dependsOn.URL.Scheme, dependsOn.URL.Host, payload, expect)
+	})
+}
+
+func probeUDP(ctx context.Context, network, address string, payload, expect []byte) error {
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	deadline, ok := ctx.Deadline()
+	if len(expect) == 0 && (!ok || time.Until(deadline) > udpRejectWait) {
+		deadline = time.Now().Add(udpRejectWait)
+	}
+	conn.SetDeadline(deadline)
+	if _, err := conn.Write(payload); err != nil {
+		return err
+	}
+	response := make([]byte, 64*1024) // max size of UDP datagram
+	n, err := conn.Read(response)
+	var netErr net.Error
+	if len(expect) == 0 {
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			// silence means the datagram is accepted
+			return nil
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if !bytes.Contains(response[:n], expect) {
+		return fmt.Errorf("response doesn't contain %q", string(expect))
+	}
+	return nil
+}
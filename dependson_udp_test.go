@@ -0,0 +1,142 @@
+package docradle
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newUDPEchoServer answers "pong" to "ping" and ignores other datagrams and the first drops datagrams
+func newUDPEchoServer(t *testing.T, drops int) net.PacketConn {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	go func() {
+		buffer := make([]byte, 1024)
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			if drops > 0 {
+				drops--
+				continue
+			}
+			if string(buffer[:n]) == "ping" {
+				conn.WriteTo([]byte("pong"), addr)
+			}
+		}
+	}()
+	return conn
+}
+
+func TestWaitForDependencies_UDP(t *testing.T) {
+	testcases := []struct {
+		name      string
+		url       string
+		send      string
+		expect    string
+		ok        bool
+		errorText string
+	}{
+		{
+			name: "ok: not rejected",
+			url:  "udp://%s",
+			ok:   true,
+		},
+		{
+			name:   "ok: expected response",
+			url:    "udp://%s",
+			send:   "${UDP_PAYLOAD}",
+			expect: "pong",
+			ok:     true,
+		},
+		{
+			name:      "ng: unexpected response",
+			url:       "udp4://%s",
+			send:      "ping",
+			expect:    "PONG",
+			ok:        false,
+			errorText: `response doesn't contain "PONG"`,
+		},
+		{
+			name:   "ng: no response",
+			url:    "udp://%s",
+			send:   "hello",
+			expect: "pong",
+			ok:     false,
+		},
+	}
+	envvar := NewEnvVar()
+	envvar.Register(fromOsEnv, "UDP_PAYLOAD", "ping")
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newUDPEchoServer(t, 0)
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					URL:            mustUrlParse(t, strings.Replace(tt.url, "%s", server.LocalAddr().String(), 1)),
+					Send:           tt.send,
+					Expect:         tt.expect,
+					Timeout:        time.Millisecond * 300,
+					Interval:       time.Millisecond * 5,
+					AttemptTimeout: time.Millisecond * 50,
+				},
+			}, envvar, nil)
+			assert.Len(t, results, 1)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Contains(t, results[0].Error().Error(), tt.errorText)
+			}
+		})
+	}
+}
+
+func TestWaitForDependencies_UDPLost(t *testing.T) {
+	server := newUDPEchoServer(t, 1)
+	defer server.Close()
+
+	// the first datagram is lost. It is sent again after interval without attemptTimeout
+	results := WaitForDependencies(context.Background(), []DependsOn{
+		{
+			URL:      mustUrlParse(t, "udp://"+server.LocalAddr().String()),
+			Send:     "ping",
+			Expect:   "pong",
+			Timeout:  time.Millisecond * 500,
+			Interval: time.Millisecond * 20,
+		},
+	}, NewEnvVar(), nil)
+	assert.Len(t, results, 1)
+	assert.NoError(t, results[0].Error())
+	assert.Equal(t, 2, results[0].attempts)
+}
+
+func TestWaitForDependencies_UDPRejected(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := conn.LocalAddr().String()
+	conn.Close()
+
+	results := WaitForDependencies(context.Background(), []DependsOn{
+		{
+			URL:      mustUrlParse(t, "udp://"+addr),
+			Timeout:  time.Millisecond * 200,
+			Interval: time.Millisecond * 10,
+		},
+	}, NewEnvVar(), nil)
+	assert.Len(t, results, 1)
+	assert.Error(t, results[0].Error())
+	assert.Contains(t, results[0].Error().Error(), "connection refused")
+}
@@ -0,0 +1,91 @@
+package docradle
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// websocketGUID is the magic string to calculate Sec-WebSocket-Accept (RFC 6455)
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// waitForWebSocket completes opening handshake of WebSocket until the server accepts upgrade
+//
+// headers option is sent with the upgrade request. wss:// enables TLS.
+func waitForWebSocket(ctx context.Context, dependsOn DependsOn, envvar *EnvVar, result *DependsOnCheckResult) error {
+	tlsConfig, err := probeTLSConfig(dependsOn, envvar, dependsOn.URL.Scheme == "wss")
+	if err != nil {
+		return err
+	}
+	defaultPort := "80"
+	if tlsConfig != nil {
+		defaultPort = "443"
+	}
+	address := hostWithDefaultPort(dependsOn.URL, defaultPort)
+	return pollUntilReady(ctx, dependsOn, result, func(ctx context.Context) error {
+		state, err := probeWebSocket(ctx, address, dependsOn.URL, dependsOn.Headers, tlsConfig)
+		if err == nil {
+			checkCertificateExpiry(dependsOn, state, result)
+		}
+		return err
+	})
+}
+
+// probeWebSocket sends upgrade request and closes the connection after handshake
+func probeWebSocket(ctx context.Context, address string, u *url.URL, headers [][2]string, tlsConfig *tls.Config) (*tls.ConnectionState, error) {
+	conn, err := dialProbe(ctx, address, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	target := *u
+	target.Scheme = "http"
+	target.User = nil
+	req, err := http.NewRequest("GET", target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	for _, header := range headers {
+		req.Header.Add(header[0], header[1])
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") {
+		return nil, fmt.Errorf("unexpected upgrade header %q", resp.Header.Get("Upgrade"))
+	}
+	hash := sha1.Sum([]byte(key + websocketGUID))
+	if resp.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(hash[:]) {
+		return nil, fmt.Errorf("invalid Sec-WebSocket-Accept %q", resp.Header.Get("Sec-WebSocket-Accept"))
+	}
+	// close frame with status 1000 (normal closure). Client frames should be masked.
+	conn.Write([]byte{0x88, 0x82, 0, 0, 0, 0, 0x03, 0xe8})
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		state := tlsConn.ConnectionState()
+		return &state, nil
+	}
+	return nil, nil
+}
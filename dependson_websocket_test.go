@@ -0,0 +1,110 @@
+package docradle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+func newWebSocketServer(token string) http.Handler {
+	echo := websocket.Server{
+		Handler: func(conn *websocket.Conn) {
+			var message string
+			websocket.Message.Receive(conn, &message)
+		},
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ws" {
+			http.NotFound(w, r)
+			return
+		}
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		echo.ServeHTTP(w, r)
+	})
+}
+
+func TestWaitForDependencies_WebSocket(t *testing.T) {
+	testcases := []struct {
+		name      string
+		token     string
+		url       string
+		headers   [][2]string
+		tls       bool
+		ok        bool
+		errorText string
+	}{
+		{
+			name: "ok: ws",
+			url:  "ws://%s/ws",
+			ok:   true,
+		},
+		{
+			name:    "ok: with header",
+			token:   "12345",
+			url:     "ws://%s/ws",
+			headers: [][2]string{{"Authorization", "Bearer 12345"}},
+			ok:      true,
+		},
+		{
+			name:      "ng: no header",
+			token:     "12345",
+			url:       "ws://%s/ws",
+			ok:        false,
+			errorText: "unexpected status 401 Unauthorized",
+		},
+		{
+			name:      "ng: not websocket endpoint",
+			url:       "ws://%s/",
+			ok:        false,
+			errorText: "unexpected status 404 Not Found",
+		},
+		{
+			name: "ok: wss",
+			url:  "wss://%s/ws",
+			tls:  true,
+			ok:   true,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			var server *httptest.Server
+			var tlsConfig *TLSConfig
+			if tt.tls {
+				server = httptest.NewTLSServer(newWebSocketServer(tt.token))
+				tlsConfig = &TLSConfig{InsecureSkipVerify: true}
+			} else {
+				server = httptest.NewServer(newWebSocketServer(tt.token))
+			}
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			results := WaitForDependencies(ctx, []DependsOn{
+				{
+					URL:      mustUrlParse(t, strings.Replace(tt.url, "%s", server.Listener.Addr().String(), 1)),
+					Headers:  tt.headers,
+					TLS:      tlsConfig,
+					Timeout:  time.Millisecond * 300,
+					Interval: time.Millisecond * 5,
+				},
+			}, NewEnvVar(), nil)
+			assert.Len(t, results, 1)
+			if tt.ok {
+				assert.NoError(t, results[0].Error())
+			} else {
+				assert.Error(t, results[0].Error())
+				assert.Contains(t, results[0].Error().Error(), tt.errorText)
+			}
+		})
+	}
+}
@@ -2,21 +2,24 @@ package docradle
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/gookit/color"
 	"io"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"sync"
 	"syscall"
+	"text/template"
 	"time"
 
 	"github.com/shirou/gopsutil/process"
+	"gocloud.dev/blob"
 	"golang.org/x/sync/errgroup"
 )
 
-var signals = make(chan os.Signal, 1)
-
 func DumpCommand(stdout io.Writer, command string, args []string, dryRun bool) {
 	color.Fprintln(stdout, "<bg=black;fg=lightBlue;op=reverse;>  Execute Command  </>\n")
 	if dryRun {
@@ -36,63 +39,422 @@ func DumpCommand(stdout io.Writer, command string, args []string, dryRun bool) {
 }
 
 // Exec executes command
+//
+// docradle behaves as init process of the container: it reaps orphaned zombie processes
+// and forwards signals to the process group of the command.
+// If a critical dependency is down while the command runs, the command is stopped or restarted
+// according to the dependency monitor policy. When the command exits by itself, it is restarted
+// according to process.restart policy. hooks.preStart runs before the first start of the command,
+// and hooks.postExit runs after the command finishes.
+// Commands in processes section are started in order before the command. command can be empty if processes exist.
 func Exec(stdout, stderr io.Writer, config *Config, command string, args []string, envvar *EnvVar) error {
 	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	stdoutLogger, err := NewLogger(ctx, StdOut, stdout, config.LogLevel, config.Stdout, envvar)
 	if err != nil {
 		return err
 	}
+	defer stdoutLogger.Close()
 	stderrLogger, err := NewLogger(ctx, StdErr, stderr, config.LogLevel, config.Stderr, envvar)
 	if err != nil {
 		return err
 	}
+	defer stderrLogger.Close()
+
+	var executions []*execution
+	defer func() {
+		for _, e := range executions {
+			e.Close()
+		}
+	}()
+	for _, entry := range config.Processes {
+		e, err := newProcessExecution(ctx, stdout, stderr, config, entry, envvar)
+		if err != nil {
+			return err
+		}
+		executions = append(executions, e)
+	}
+	if command != "" {
+		mainStdoutLogger, mainStderrLogger := stdoutLogger, stderrLogger
+		name := ""
+		if len(config.Processes) > 0 {
+			name = mainProcessName
+			mainStdoutLogger = stdoutLogger.withTag(LogProcessKey, name)
+			mainStderrLogger = stderrLogger.withTag(LogProcessKey, name)
+		}
+		e, err := newExecution(ctx, stdout, stderr, config, command, args, envvar, mainStdoutLogger, mainStderrLogger)
+		if err != nil {
+			return err
+		}
+		e.name = name
+		executions = append(executions, e)
+	}
+	if len(executions) == 0 {
+		return errors.New("no command to run")
+	}
 
-	cmd := exec.CommandContext(ctx, command, args...)
-	cmd.Env = envvar.EnvsForExec()
+	stopReaper := startReaper(ctx)
+	defer stopReaper()
 
 	// Setup signaling
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL)
+	sigs := make(chan os.Signal, len(forwardedSignals))
+	signal.Notify(sigs, forwardedSignals...)
+	defer signal.Stop(sigs)
+
+	hooks := &execution{
+		stdout:       stdout,
+		stderr:       stderr,
+		envvar:       envvar,
+		stdoutLogger: stdoutLogger,
+		stderrLogger: stderrLogger,
+		sigs:         sigs,
+	}
+	// hooks run as process.user like the command
+	hooks.credential, err = lookupCredential(config.Process.User, config.Process.Group)
+	if err != nil {
+		return err
+	}
+	if err := hooks.runHooks(ctx, config.Hooks.PreStart); err != nil {
+		return err
+	}
+	broadcastCtx, stopBroadcast := context.WithCancel(ctx)
+	broadcasted := make(chan struct{})
+	go func() {
+		broadcastSignals(broadcastCtx, sigs, executions)
+		close(broadcasted)
+	}()
+	err = superviseAll(ctx, executions)
+	stopBroadcast()
+	<-broadcasted
+	if hookErr := hooks.runHooks(ctx, config.Hooks.PostExit); hookErr != nil && err == nil {
+		err = hookErr
+	}
+	return err
+}
+
+// newExecution creates the execution of the command
+//
+// It opens targets of exit notifications and log bucket of the config. They should be closed by Close().
+func newExecution(ctx context.Context, stdout, stderr io.Writer, config *Config, command string, args []string, envvar *EnvVar, stdoutLogger, stderrLogger *Logger) (*execution, error) {
+	e := &execution{
+		stdout:       stdout,
+		stderr:       stderr,
+		config:       config,
+		command:      command,
+		args:         args,
+		envvar:       envvar,
+		stdoutLogger: stdoutLogger,
+		stderrLogger: stderrLogger,
+		sigs:         make(chan os.Signal, len(forwardedSignals)),
+		onExit:       "stop",
+		stopRequest:  make(chan struct{}),
+		started:      make(chan struct{}),
+	}
+	var err error
+	e.credential, err = lookupCredential(config.Process.User, config.Process.Group)
+	if err != nil {
+		return nil, err
+	}
+	notifier, err := newExitNotifier(ctx, config.Process, envvar)
+	if err != nil {
+		return nil, err
+	}
+	if notifier != nil {
+		e.notifier = notifier
+		e.tail = newLogTail(exitReportLogLines)
+		stdoutLogger.tail = e.tail
+		stderrLogger.tail = e.tail
+	}
+	if config.Process.LogBucket != "" {
+		e.logKey, err = parseLogKey(config.Process.LogKey)
+		if err != nil {
+			e.Close()
+			return nil, fmt.Errorf("process's logKey is invalid: %w", err)
+		}
+		e.bucket, err = blob.OpenBucket(ctx, envvar.Expand(config.Process.LogBucket))
+		if err != nil {
+			e.Close()
+			return nil, fmt.Errorf("Can't open process's logBucket: %w", err)
+		}
+	}
+	return e, nil
+}
+
+// Close closes targets of exit notifications and log bucket (and loggers of the entry in processes section)
+func (e *execution) Close() {
+	if e.ownLoggers {
+		e.stdoutLogger.Close()
+		e.stderrLogger.Close()
+	}
+	if e.notifier != nil {
+		e.notifier.Close()
+	}
+	if e.bucket != nil {
+		e.bucket.Close()
+	}
+}
+
+// supervise runs the command until it exits, and restarts it according to policies
+func (e *execution) supervise(ctx context.Context) error {
+	restarts := &restartTracker{process: e.config.Process}
+	var err error
+	for {
+		if e.stopRequested() {
+			return err
+		}
+		var result runResult
+		result, err = e.run(ctx)
+		if down := result.down; down != nil {
+			if e.config.DependencyMonitor.Policy != "restart" {
+				return fmt.Errorf("stopped because dependency %s is down for %s", down.label(), down.duration)
+			}
+			// wait for recovery of the dependency before restarting
+			recovery := *down
+			recovery.After = nil
+			waitCtx, waitCancel, received := e.waitContext(ctx)
+			results := WaitForDependencies(waitCtx, []DependsOn{recovery.DependsOn}, e.envvar, NewDependsOnProgress(e.stdout))
+			waitCancel()
+			if sig := received(); sig != nil {
+				return &CanceledError{Signal: sig}
+			} else if e.stopRequested() {
+				return nil
+			}
+			color.Fprintln(e.stdout, "\n<bg=black;fg=lightBlue;op=reverse;>  Restart Execution  </>\n")
+			color.Fprintln(e.stdout, results[0].String())
+			if err := results[0].Error(); err != nil {
+				return fmt.Errorf("dependency %s is not recovered: %w: %v", down.label(), ErrDependencyNotReady, err)
+			}
+			continue
+		}
+		delay, restart := restarts.next(result, err)
+		if restart && e.config.Process.Timeout > 0 && !e.firstStart.IsZero() && time.Since(e.firstStart) >= e.config.Process.Timeout {
+			// restarts don't reset process.timeout
+			restart = false
+		}
+		if !restart {
+			if restarts.exhausted {
+				color.Fprintf(e.stderr, "<red>Gave up restarting after %d restarts</>\n", restarts.attempts)
+			}
+			return err
+		}
+		e.stdoutLogger.WriteRestart(time.Now(), restarts.attempts, result.status, delay)
+		color.Fprintf(e.stdout, "\n<bg=black;fg=lightBlue;op=reverse;>  Restart Execution  </> <gray>(attempt %d after %s)</>\n\n", restarts.attempts, delay)
+		waitCtx, waitCancel, received := e.waitContext(ctx)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-waitCtx.Done():
+		}
+		timer.Stop()
+		waitCancel()
+		if sig := received(); sig != nil {
+			return &CanceledError{Signal: sig}
+		}
+	}
+}
+
+// waitContext returns the context that is canceled by received signal or stop request from other processes
+func (e *execution) waitContext(ctx context.Context) (context.Context, context.CancelFunc, func() os.Signal) {
+	ctx, cancel, received := signalContext(ctx, e.sigs)
+	go func() {
+		select {
+		case <-e.stopRequest:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel, received
+}
+
+// runHooks runs hooks in order
+//
+// Received signal cancels the hooks. If a hook fails, the rest of hooks are skipped unless its failurePolicy is "ignore".
+func (e *execution) runHooks(ctx context.Context, hooks []*Hook) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+	ctx, cancel, received := signalContext(ctx, e.sigs)
+	defer cancel()
+	for _, hook := range hooks {
+		color.Fprintf(e.stdout, "<bg=black;fg=lightBlue;op=reverse;>  Run Hook  </> <cyan>%s</>\n\n", hook.Name)
+		err := runHook(ctx, hook, e.envvar, e.credential, e.stdoutLogger, e.stderrLogger)
+		if sig := received(); sig != nil {
+			return &CanceledError{Signal: sig}
+		}
+		if err != nil {
+			color.Fprintf(e.stderr, "<red>Error: %s</>\n", err.Error())
+			if hook.FailurePolicy != "ignore" {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// downDependency is a critical dependency that is down longer than the threshold
+type downDependency struct {
+	DependsOn
+	duration time.Duration
+}
+
+func (d downDependency) label() string {
+	return newDependsOnCheckResult(d.DependsOn).label()
+}
+
+// execution runs command and restarts it according to policies
+type execution struct {
+	name         string // name in processes section ("main" for the command if processes exist)
+	stdout       io.Writer
+	stderr       io.Writer
+	config       *Config
+	command      string
+	args         []string
+	envvar       *EnvVar
+	stdoutLogger *Logger
+	stderrLogger *Logger
+	sigs         chan os.Signal
+	notifier     *exitNotifier
+	tail         *logTail
+	bucket       *blob.Bucket
+	logKey       *template.Template
+	credential   *credential // process.user and process.group
+	ownLoggers   bool        // loggers are closed with the execution
+	waitFor      []DependsOn // dependencies to wait for before the first start
+	onExit       string      // "stop" stops other processes when this execution finishes
+	stopRequest  chan struct{}
+	stopOnce     sync.Once
+	started      chan struct{}
+	startOnce    sync.Once
+	firstStart   time.Time // process.timeout is measured from it to limit the total run time over restarts
+}
+
+// requestStop stops the command gracefully (and cancels restart) when other process finishes
+func (e *execution) requestStop() {
+	e.stopOnce.Do(func() {
+		close(e.stopRequest)
+	})
+}
+
+func (e *execution) stopRequested() bool {
+	select {
+	case <-e.stopRequest:
+		return true
+	default:
+		return false
+	}
+}
+
+// markStarted lets the next process in processes section start
+func (e *execution) markStarted() {
+	e.startOnce.Do(func() {
+		close(e.started)
+	})
+}
+
+// run executes command and waits for its exit
+//
+// The result has the down dependency if the command is stopped by the dependency monitor.
+// termination of the result is empty if the command fails to start.
+func (e *execution) run(ctx context.Context) (runResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, e.command, e.args...)
+	cmd.Env = e.envvar.EnvsForExec()
+	if e.credential != nil {
+		cmd.Env = withHome(cmd.Env, e.credential.home)
+	}
+	// signals are forwarded to the whole process group (including grandchildren like "sh -c")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Credential: e.credential.sysCredential()}
 
 	eg, _ := errgroup.WithContext(ctx)
 
-	stdoutPipe, err := cmd.StdoutPipe()
+	var archive *logArchive
+	if e.bucket != nil {
+		var err error
+		archive, err = newLogArchive()
+		if err != nil {
+			return runResult{}, err
+		}
+		defer archive.Close()
+		e.stdoutLogger.archive = archive
+		e.stderrLogger.archive = archive
+	}
+
+	// cmd.StdoutPipe() can't be used because cmd.Wait() closes it before the last output is read
+	stdoutReader, stdoutWriter, err := os.Pipe()
 	if err != nil {
-		return err
+		return runResult{}, err
 	}
-	stderrPipe, err := cmd.StderrPipe()
+	defer stdoutReader.Close()
+	stderrReader, stderrWriter, err := os.Pipe()
 	if err != nil {
-		return err
+		stdoutWriter.Close()
+		return runResult{}, err
+	}
+	defer stderrReader.Close()
+	cmd.Stdout = stdoutWriter
+	cmd.Stderr = stderrWriter
+	var activity *outputActivity
+	if e.config.Process.Watchdog > 0 {
+		activity = newOutputActivity()
+		e.stdoutLogger.activity = activity
+		e.stderrLogger.activity = activity
 	}
-	stdoutLogger.StartOutput(eg, stdoutPipe)
-	stderrLogger.StartOutput(eg, stderrPipe)
+	var outputs errgroup.Group
+	e.stdoutLogger.StartOutput(&outputs, stdoutReader)
+	e.stderrLogger.StartOutput(&outputs, stderrReader)
 
+	started := make(chan struct{})
+	p := &runningProcess{
+		cmd:    cmd,
+		exited: make(chan struct{}),
+	}
 	eg.Go(func() error {
 		select {
-		case sig := <-sigs:
-			signalProcessWithTimeout(cmd, sig, stderr)
-			cancel()
+		case <-started:
+			e.forwardSignals(ctx, eg, p)
 		case <-ctx.Done():
-			// exit when context is done
+			// command failed to start
 		}
 		return nil
 	})
 
+	var result runResult
+	var down *downDependency
+	var timedOut error
 	eg.Go(func() error {
-		color.Fprintln(stdout, "<bg=black;fg=lightBlue;op=reverse;>  Start Execution  </>\n")
+		if e.name != "" {
+			color.Fprintf(e.stdout, "<bg=black;fg=lightBlue;op=reverse;>  Start Execution  </> <cyan>%s</>\n\n", e.name)
+		} else {
+			color.Fprintln(e.stdout, "<bg=black;fg=lightBlue;op=reverse;>  Start Execution  </>\n")
+		}
 
-		defer stdoutLogger.Close()
-		defer stderrLogger.Close()
 		start := time.Now()
-		err := cmd.Start()
+		err := startCommandWithResources(cmd, e.config.Process)
 		defer cancel()
+		// the command has its own copies
+		stdoutWriter.Close()
+		stderrWriter.Close()
 		if err != nil {
-			color.Fprintf(stderr, "<red>Error: %s</>\n\n", err.Error())
+			color.Fprintf(e.stderr, "<red>Error: %s</>\n\n", err.Error())
 			return err
 		}
+		defer releaseCommand(cmd)
+		close(started)
+		e.markStarted()
+		if e.firstStart.IsZero() {
+			e.firstStart = start
+		}
 		cwd, _ := filepath.Abs(".")
-		stdoutLogger.WriteProcessStart(start, cmd.Process.Pid, cwd, command, args)
+		e.stdoutLogger.WriteProcessStart(start, cmd.Process.Pid, cwd, e.command, e.args, effectiveResources(cmd.Process.Pid, e.config.Process))
+		if e.config.Process.Timeout > 0 || e.config.Process.Watchdog > 0 {
+			eg.Go(func() error {
+				timedOut = e.stopAtTimeout(ctx, p, e.firstStart, activity)
+				return nil
+			})
+		}
 		proc, err := process.NewProcess(int32(cmd.Process.Pid))
 		if err == nil {
 			eg.Go(func() error {
@@ -112,44 +474,237 @@ func Exec(stdout, stderr io.Writer, config *Config, command string, args []strin
 						if err != nil {
 							return nil
 						}
-						stdoutLogger.WriteMetrics(mem.RSS, mp, cp)
+						e.stdoutLogger.WriteMetrics(mem.RSS, mp, cp)
 					case <-ctx.Done():
 						return nil
 					}
 				}
 			})
 		}
-		result := cmd.Wait()
+		eg.Go(func() error {
+			MonitorDependencies(ctx, dependsOnAs(e.config.DependsOn, e.credential), e.config.DependencyMonitor, e.envvar, e.stdoutLogger, func(dependsOn DependsOn, downtime time.Duration) {
+				policy := e.config.DependencyMonitor.Policy
+				if policy != "stop" && policy != "restart" {
+					return
+				}
+				down = &downDependency{DependsOn: dependsOn, duration: downtime}
+				color.Fprintf(e.stderr, "<red>Dependency %s is down for %s. Stopping command.</>\n", down.label(), downtime)
+				if p.beginStop() {
+					e.stopProcess(ctx, p, e.stopSignal(nil), e.config.DependencyMonitor.GracePeriod)
+				}
+				cancel()
+			})
+			return nil
+		})
+		waitErr := cmd.Wait()
+		close(p.exited)
 		exit := time.Now()
-		stdoutLogger.WriteProcessResult(exit, cmd.ProcessState.String(),
-			exit.Sub(start), cmd.ProcessState.UserTime(), cmd.ProcessState.SystemTime())
-		color.Fprintln(stdout, "\n<bg=black;fg=lightBlue;op=reverse;>  Process Result  </>\n")
+		result.status = cmd.ProcessState.String()
+		result.termination = p.termination()
+		result.duration = exit.Sub(start)
+		waitOutputs(&outputs, outputFlushTimeout, stdoutReader, stderrReader)
+		e.stdoutLogger.WriteProcessResult(exit, result.status, result.termination,
+			result.duration, cmd.ProcessState.UserTime(), cmd.ProcessState.SystemTime())
+		color.Fprintln(e.stdout, "\n<bg=black;fg=lightBlue;op=reverse;>  Process Result  </>\n")
 		if cmd.ProcessState.Success() {
-			color.Fprintf(stdout, "    <fg=lightGreen;op=underscore,bold;>%s</>\n", cmd.ProcessState.String())
+			color.Fprintf(e.stdout, "    <fg=lightGreen;op=underscore,bold;>%s</>\n", cmd.ProcessState.String())
 		} else {
-			color.Fprintf(stdout, "    <fg=red;op=underscore,bold;>%s</>\n", cmd.ProcessState.String())
+			color.Fprintf(e.stdout, "    <fg=red;op=underscore,bold;>%s</>\n", cmd.ProcessState.String())
 		}
 		if err != nil {
-			color.Fprintf(stderr, "<red>Error: %s</>\n\n", err.Error())
+			color.Fprintf(e.stderr, "<red>Error: %s</>\n\n", err.Error())
+		}
+		if archive != nil {
+			if err := e.uploadLog(archive, cmd.Process.Pid, start); err != nil {
+				color.Fprintf(e.stderr, "<red>Error: can't upload log to process's logBucket: %s</>\n", err.Error())
+			}
+		}
+		if e.notifier != nil {
+			report := newExitReport(e.command, e.args, cmd.ProcessState, result.termination, start, exit, e.tail.take())
+			for _, err := range e.notifier.notify(context.Background(), report) {
+				color.Fprintf(e.stderr, "<red>Error: %s</>\n", err.Error())
+			}
 		}
-		return result
+		return waitErr
 	})
-	return eg.Wait()
+	err = eg.Wait()
+	if down != nil {
+		result.down = down
+		return result, nil
+	}
+	if timedOut != nil {
+		return result, timedOut
+	}
+	return result, err
 }
 
-func signalProcessWithTimeout(process *exec.Cmd, sig os.Signal, stderr io.Writer) {
-	done := make(chan struct{})
+// stopAtTimeout stops the command when process.timeout passes, and kills it when process.watchdog detects no output
+//
+// It returns the error wrapping ErrTimeout if it stops the command.
+func (e *execution) stopAtTimeout(ctx context.Context, p *runningProcess, startAt time.Time, activity *outputActivity) error {
+	reason, limit := waitTimeout(ctx, e.config.Process, startAt, activity)
+	if reason == "" || !p.beginStop() {
+		return nil
+	}
+	e.stdoutLogger.WriteTimeout(time.Now(), reason, limit)
+	if reason == "watchdog" {
+		color.Fprintf(e.stderr, "<red>Killing process group because the command doesn't write output for %s</>\n", limit)
+		p.markKilled()
+		signalProcessGroup(p.cmd.Process, syscall.SIGKILL)
+		return fmt.Errorf("%w: no output for %s", ErrTimeout, limit)
+	}
+	color.Fprintf(e.stderr, "<red>Stopping command because it runs longer than %s</>\n", limit)
+	e.stopProcess(ctx, p, e.stopSignal(nil), e.config.Process.StopTimeout)
+	return fmt.Errorf("%w: runs longer than %s", ErrTimeout, limit)
+}
+
+// uploadLog uploads the output of the command to process.logBucket
+func (e *execution) uploadLog(archive *logArchive, pid int, startAt time.Time) error {
+	key, err := logKey(e.logKey, e.command, pid, startAt)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), logUploadTimeout)
+	defer cancel()
+	return archive.upload(ctx, e.bucket, key)
+}
+
+// outputFlushTimeout is the time to read the rest of output after the command exits
+//
+// Background processes of the command can keep the pipes open.
+const outputFlushTimeout = time.Second
+
+// logUploadTimeout is the timeout of uploading log to process.logBucket
+const logUploadTimeout = time.Minute
 
+// waitOutputs waits until all output is read. Readers are closed after the timeout.
+func waitOutputs(outputs *errgroup.Group, timeout time.Duration, readers ...io.Closer) {
+	done := make(chan struct{})
 	go func() {
-		process.Process.Signal(sig) // pretty sure this doesn't do anything. It seems like the signal is automatically sent to the command?
-		process.Wait()
+		outputs.Wait()
 		close(done)
 	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
 	select {
 	case <-done:
 		return
-	case <-time.After(10 * time.Second):
-		color.Fprintf(stderr, "Killing command due to timeout")
-		process.Process.Kill()
+	case <-timer.C:
+	}
+	for _, reader := range readers {
+		reader.Close()
+	}
+	<-done
+}
+
+// defaultStopTimeout is used if process.stopTimeout is not set
+const defaultStopTimeout = 10 * time.Second
+
+// runningProcess is the command that runs in execution.run
+type runningProcess struct {
+	cmd      *exec.Cmd
+	exited   chan struct{}
+	lock     sync.Mutex
+	stopping bool
+	killed   bool
+}
+
+// beginStop returns true only at the first call
+func (p *runningProcess) beginStop() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.stopping {
+		return false
+	}
+	p.stopping = true
+	return true
+}
+
+func (p *runningProcess) markKilled() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.killed = true
+}
+
+// termination returns "exited" (the command exits by itself), "graceful" (it exits after stop signal) or "killed"
+func (p *runningProcess) termination() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	switch {
+	case p.killed:
+		return "killed"
+	case p.stopping:
+		return "graceful"
+	}
+	return "exited"
+}
+
+// stopSignal returns the signal to stop the command
+//
+// process.stopSignal is used if specified. Otherwise the received signal (converted by process.signalRewrite) or SIGTERM is used.
+func (e *execution) stopSignal(received os.Signal) os.Signal {
+	if e.config.Process.StopSignal != 0 {
+		return e.config.Process.StopSignal
+	}
+	if received != nil {
+		return rewriteSignal(e.config.Process.SignalRewrite, received)
+	}
+	return syscall.SIGTERM
+}
+
+// stopProcess runs preStop hook and sends the stop signal to the process group
+//
+// If the command doesn't exit within the timeout, the whole process group is killed.
+func (e *execution) stopProcess(ctx context.Context, p *runningProcess, sig os.Signal, timeout time.Duration) {
+	if hook := e.config.Process.PreStop; hook != nil {
+		if err := runHook(ctx, hook, e.envvar, e.credential, e.stdoutLogger, e.stderrLogger); err != nil {
+			color.Fprintf(e.stderr, "<red>Error: %s</>\n", err.Error())
+		}
+	}
+	signalProcessGroup(p.cmd.Process, sig)
+	if timeout <= 0 {
+		timeout = defaultStopTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-p.exited:
+	case <-timer.C:
+		color.Fprintf(e.stderr, "<red>Killing process group because the command doesn't exit within %s</>\n", timeout)
+		p.markKilled()
+		signalProcessGroup(p.cmd.Process, syscall.SIGKILL)
+	}
+}
+
+// forwardSignals sends received signals to the process group of the command until it exits
+//
+// Signals are converted by process.signalRewrite option. SIGINT and SIGTERM start the stop sequence
+// (preStop hook, stop signal and killing after process.stopTimeout). Stop request from other processes also starts it.
+func (e *execution) forwardSignals(ctx context.Context, eg *errgroup.Group, p *runningProcess) {
+	stopRequest := e.stopRequest
+	for {
+		select {
+		case <-stopRequest:
+			stopRequest = nil
+			if p.beginStop() {
+				eg.Go(func() error {
+					e.stopProcess(ctx, p, e.stopSignal(nil), e.config.Process.StopTimeout)
+					return nil
+				})
+			}
+		case sig := <-e.sigs:
+			if isStopSignal(sig) && p.beginStop() {
+				eg.Go(func() error {
+					e.stopProcess(ctx, p, e.stopSignal(sig), e.config.Process.StopTimeout)
+					return nil
+				})
+			} else {
+				signalProcessGroup(p.cmd.Process, rewriteSignal(e.config.Process.SignalRewrite, sig))
+			}
+		case <-p.exited:
+			return
+		case <-ctx.Done():
+			return
+		}
 	}
 }
@@ -0,0 +1,384 @@
+package docradle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	// the test binary is started as the command with process's resources
+	RunResourceHelper()
+	os.Exit(m.Run())
+}
+
+// syncBuffer is bytes.Buffer that can be written from goroutines
+type syncBuffer struct {
+	lock   sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buffer.String()
+}
+
+// waitForMessage waits until the command writes the message
+//
+// Command line in the start event can contain the same text, so it checks the message field.
+func waitForMessage(stdout *syncBuffer, message string) {
+	for i := 0; i < 200 && !strings.Contains(stdout.String(), `"message":"`+message+`"`); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestExec_ForwardSignal(t *testing.T) {
+	// the shell traps USR1 and USR2. Its child (sleep) is in the same process group.
+	script := `trap "echo got-usr1" USR1; trap "echo got-usr2; exit 3" USR2; echo ready; while :; do sleep 0.05; done`
+	testcases := []struct {
+		name    string
+		config  string
+		signals []syscall.Signal
+		output  []string
+	}{
+		{
+			name:    "forward every signal",
+			config:  `{}`,
+			signals: []syscall.Signal{syscall.SIGUSR1, syscall.SIGUSR2},
+			output:  []string{"got-usr1", "got-usr2"},
+		},
+		{
+			name:    "rewrite signal",
+			config:  `{"process": {"signalRewrite": {"TERM": "USR2"}}}`,
+			signals: []syscall.Signal{syscall.SIGTERM},
+			output:  []string{"got-usr2"},
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ReadConfig("config.json", strings.NewReader(tt.config))
+			assert.NoError(t, err)
+
+			stdout := &syncBuffer{}
+			signals := tt.signals
+			go func() {
+				waitForMessage(stdout, "ready")
+				for _, sig := range signals {
+					syscall.Kill(os.Getpid(), sig)
+					time.Sleep(100 * time.Millisecond)
+				}
+			}()
+			err = Exec(stdout, &syncBuffer{}, config, "sh", []string{"-c", script}, NewEnvVar())
+			assert.Error(t, err)
+			for _, output := range tt.output {
+				assert.Contains(t, stdout.String(), `"message":"`+output+`"`)
+			}
+		})
+	}
+}
+
+func TestExec_Stop(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "cradle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+
+	testcases := []struct {
+		name        string
+		config      string
+		script      string
+		termination string
+		output      string
+	}{
+		{
+			name:        "graceful",
+			config:      `{}`,
+			script:      `trap "echo got-term; exit 0" TERM; echo ready; while :; do sleep 0.05; done`,
+			termination: `"termination":"graceful"`,
+			output:      "got-term",
+		},
+		{
+			name:        "stop signal",
+			config:      `{"process": {"stopSignal": "USR1"}}`,
+			script:      `trap "echo got-usr1; exit 0" USR1; echo ready; while :; do sleep 0.05; done`,
+			termination: `"termination":"graceful"`,
+			output:      "got-usr1",
+		},
+		{
+			name:   "killed",
+			config: `{"process": {"stopTimeout": 0.2}}`,
+			// ignored signal is inherited by the background child in the same process group
+			script:      `trap "" TERM; sleep 30 & echo ready; wait`,
+			termination: `"termination":"killed"`,
+		},
+		{
+			name:        "preStop",
+			config:      `{"process": {"preStop": {"command": ["sh", "-c", "echo drained > ` + filepath.Join(dirPath, "drained") + `"]}}}`,
+			script:      `trap "cat ` + filepath.Join(dirPath, "drained") + `; exit 0" TERM; echo ready; while :; do sleep 0.05; done`,
+			termination: `"termination":"graceful"`,
+			output:      "drained",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ReadConfig("config.json", strings.NewReader(tt.config))
+			assert.NoError(t, err)
+
+			stdout := &syncBuffer{}
+			go func() {
+				waitForMessage(stdout, "ready")
+				syscall.Kill(os.Getpid(), syscall.SIGTERM)
+			}()
+			start := time.Now()
+			Exec(stdout, &syncBuffer{}, config, "sh", []string{"-c", tt.script}, NewEnvVar())
+			assert.True(t, time.Now().Sub(start) < 5*time.Second)
+			assert.Contains(t, stdout.String(), tt.termination)
+			if tt.output != "" {
+				assert.Contains(t, stdout.String(), `"message":"`+tt.output+`"`)
+			}
+		})
+	}
+}
+
+func TestExec_BackgroundProcessKeepsOutput(t *testing.T) {
+	config, err := ReadConfig("config.json", strings.NewReader(`{}`))
+	assert.NoError(t, err)
+
+	stdout := &syncBuffer{}
+	start := time.Now()
+	err = Exec(stdout, &syncBuffer{}, config, "sh", []string{"-c", "sleep 10 & echo last-line"}, NewEnvVar())
+	assert.NoError(t, err)
+	assert.True(t, time.Now().Sub(start) < 5*time.Second)
+	assert.Contains(t, stdout.String(), `"message":"last-line"`)
+	assert.Contains(t, stdout.String(), `"termination":"exited"`)
+}
+
+func TestExec_Restart(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "cradle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+
+	testcases := []struct {
+		name     string
+		config   string
+		script   string
+		hasError bool
+		restarts int
+	}{
+		{
+			name:   "no",
+			config: `{}`,
+			// fails only at the first run
+			script:   `test -f %[1]s && exit 0; touch %[1]s; exit 1`,
+			hasError: true,
+			restarts: 0,
+		},
+		{
+			name:     "on-failure",
+			config:   `{"process": {"restart": "on-failure", "restartBackoff": {"initial": 0.05}}}`,
+			script:   `test -f %[1]s && exit 0; touch %[1]s; exit 1`,
+			hasError: false,
+			restarts: 1,
+		},
+		{
+			name:     "maxRestarts",
+			config:   `{"process": {"restart": "always", "maxRestarts": 2, "restartBackoff": {"initial": 0.05}}}`,
+			script:   `exit 3`,
+			hasError: true,
+			restarts: 2,
+		},
+	}
+	for i, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ReadConfig("config.json", strings.NewReader(tt.config))
+			assert.NoError(t, err)
+
+			script := fmt.Sprintf(tt.script, filepath.Join(dirPath, fmt.Sprintf("ran-%d", i)))
+			stdout := &syncBuffer{}
+			err = Exec(stdout, &syncBuffer{}, config, "sh", []string{"-c", script}, NewEnvVar())
+			if tt.hasError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.restarts, strings.Count(stdout.String(), `"docradle-log":"restart"`))
+			if tt.restarts > 0 {
+				assert.Contains(t, stdout.String(), `"attempt":1,"previous-status":"exit status`)
+			}
+		})
+	}
+}
+
+func TestExec_NoRestartAfterStop(t *testing.T) {
+	config, err := ReadConfig("config.json", strings.NewReader(`{"process": {"restart": "always", "restartBackoff": {"initial": 0.05}}}`))
+	assert.NoError(t, err)
+
+	stdout := &syncBuffer{}
+	go func() {
+		waitForMessage(stdout, "ready")
+		syscall.Kill(os.Getpid(), syscall.SIGTERM)
+	}()
+	start := time.Now()
+	Exec(stdout, &syncBuffer{}, config, "sh", []string{"-c", `trap "exit 0" TERM; echo ready; while :; do sleep 0.05; done`}, NewEnvVar())
+	assert.True(t, time.Now().Sub(start) < 5*time.Second)
+	assert.Contains(t, stdout.String(), `"termination":"graceful"`)
+	assert.NotContains(t, stdout.String(), `"docradle-log":"restart"`)
+}
+
+func TestExec_NoticeExit(t *testing.T) {
+	var report ExitReport
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&report)
+	}))
+	defer server.Close()
+
+	config, err := ReadConfig("config.json", strings.NewReader(`{
+		"process": {"noticeExitHttp": "`+server.URL+`"},
+		"stdout": {"mask": ["password"]}
+	}`))
+	assert.NoError(t, err)
+
+	script := `echo first; echo '{"message": "login", "password": "secret"}'; echo last >&2; exit 2`
+	err = Exec(&syncBuffer{}, &syncBuffer{}, config, "sh", []string{"-c", script}, NewEnvVar())
+	assert.Error(t, err)
+	assert.Equal(t, "sh", report.Command)
+	assert.Equal(t, 2, report.ExitCode)
+	assert.Equal(t, "exited", report.Termination)
+	// order between stdout and stderr isn't guaranteed
+	assert.Len(t, report.LastLogs, 3)
+	assert.Contains(t, report.LastLogs, "first")
+	assert.Contains(t, report.LastLogs, "last")
+	assert.Contains(t, strings.Join(report.LastLogs, "\n"), `"password":"********"`)
+}
+
+func TestExec_LogBucket(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "cradle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+
+	config, err := ReadConfig("config.json", strings.NewReader(`{
+		"process": {"logBucket": "file://`+dirPath+`", "logKey": "logs/{{.Command}}.log.gz"}
+	}`))
+	assert.NoError(t, err)
+
+	err = Exec(&syncBuffer{}, &syncBuffer{}, config, "sh", []string{"-c", "echo hello; echo world >&2"}, NewEnvVar())
+	assert.NoError(t, err)
+
+	file, err := os.Open(filepath.Join(dirPath, "logs", "sh.log.gz"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	assert.NoError(t, err)
+	content, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "hello\n")
+	assert.Contains(t, string(content), "world\n")
+}
+
+func TestExec_Hooks(t *testing.T) {
+	testcases := []struct {
+		name     string
+		hooks    string
+		script   string
+		hasError bool
+		messages []string
+		notShown []string
+	}{
+		{
+			name:     "preStart and postExit",
+			hooks:    `{"preStart": [{"name": "migrate", "command": ["echo", "migrated"]}], "postExit": [{"command": ["echo", "cleaned"]}]}`,
+			script:   "echo main",
+			messages: []string{`"hook":"migrate"`, `"message":"migrated"`, `"message":"main"`, `"hook":"postExit#1"`, `"message":"cleaned"`},
+		},
+		{
+			name:     "preStart failed",
+			hooks:    `{"preStart": [{"command": ["false"]}, {"command": ["echo", "skipped"]}], "postExit": [{"command": ["echo", "cleaned"]}]}`,
+			script:   "echo main",
+			hasError: true,
+			notShown: []string{`"message":"skipped"`, `"message":"main"`, `"message":"cleaned"`},
+		},
+		{
+			name:     "ignore failure",
+			hooks:    `{"preStart": [{"command": ["false"], "failurePolicy": "ignore"}, {"command": ["echo", "next"]}]}`,
+			script:   "echo main",
+			messages: []string{`"message":"next"`, `"message":"main"`},
+		},
+		{
+			name:     "postExit failed",
+			hooks:    `{"postExit": [{"command": ["false"]}]}`,
+			script:   "echo main",
+			hasError: true,
+			messages: []string{`"message":"main"`},
+		},
+		{
+			name:     "postExit runs after failure of command",
+			hooks:    `{"postExit": [{"command": ["echo", "cleaned"]}]}`,
+			script:   "exit 1",
+			hasError: true,
+			messages: []string{`"message":"cleaned"`},
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ReadConfig("config.json", strings.NewReader(`{"hooks": `+tt.hooks+`}`))
+			assert.NoError(t, err)
+
+			stdout := &syncBuffer{}
+			err = Exec(stdout, &syncBuffer{}, config, "sh", []string{"-c", tt.script}, NewEnvVar())
+			if tt.hasError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			output := stdout.String()
+			last := -1
+			for _, message := range tt.messages {
+				index := strings.Index(output, message)
+				assert.True(t, index > last, "%s should be shown in order: %s", message, output)
+				last = index
+			}
+			for _, message := range tt.notShown {
+				assert.NotContains(t, output, message)
+			}
+		})
+	}
+}
+
+func TestExec_User(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("changing user requires root privilege")
+	}
+	config, err := ReadConfig("config.json", strings.NewReader(`{
+		"process": {"user": "65534", "group": "65533"},
+		"hooks": {"preStart": [{"name": "whoami", "command": ["id", "-u"]}]}
+	}`))
+	assert.NoError(t, err)
+
+	stdout := &syncBuffer{}
+	err = Exec(stdout, &syncBuffer{}, config, "sh", []string{"-c", `echo "$(id -u):$(id -g):$(id -G)"`}, NewEnvVar())
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), `"message":"65534:65533:65533"`)
+	// hooks run as the same user
+	assert.Contains(t, stdout.String(), `"hook":"whoami","time":1579946400,"message":"65534"`)
+}
@@ -0,0 +1,91 @@
+package docradle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+)
+
+// Exit codes of docradle
+const (
+	ExitCodeError            = 1
+	ExitCodeDependencyFailed = 69  // EX_UNAVAILABLE of sysexits.h
+	ExitCodeTimeout          = 124 // same as timeout(1)
+)
+
+// ErrDependencyNotReady is returned when dependencies don't become ready until timeout
+var ErrDependencyNotReady = errors.New("dependencies are not ready")
+
+// ErrTimeout is returned when the command is stopped by process.timeout or process.watchdog
+var ErrTimeout = errors.New("command timed out")
+
+// CanceledError is returned when docradle is canceled by signal
+type CanceledError struct {
+	Signal os.Signal
+}
+
+func (e *CanceledError) Error() string {
+	return "canceled by signal: " + e.Signal.String()
+}
+
+// ExitCode returns 128 + signal number like shells
+func (e *CanceledError) ExitCode() int {
+	if sig, ok := e.Signal.(syscall.Signal); ok {
+		return 128 + int(sig)
+	}
+	return 128 + int(syscall.SIGINT)
+}
+
+// ExitCode returns exit code of docradle for the error
+//
+// If the command fails, its exit status is passed on (128 + signal number if it is killed by signal).
+func ExitCode(err error) int {
+	var canceled *CanceledError
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &canceled):
+		return canceled.ExitCode()
+	case errors.As(err, &exitErr):
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return 128 + int(status.Signal())
+		}
+		if code := exitErr.ExitCode(); code > 0 {
+			return code
+		}
+	case errors.Is(err, ErrDependencyNotReady):
+		return ExitCodeDependencyFailed
+	case errors.Is(err, ErrTimeout):
+		return ExitCodeTimeout
+	}
+	return ExitCodeError
+}
+
+// signalContext returns context that is canceled when a signal arrives from sigs
+//
+// received returns the signal after the context is canceled by it, otherwise nil.
+func signalContext(ctx context.Context, sigs <-chan os.Signal) (signalCtx context.Context, cancel context.CancelFunc, received func() os.Signal) {
+	signalCtx, cancel = context.WithCancel(ctx)
+	var lock sync.Mutex
+	var sig os.Signal
+	go func() {
+		select {
+		case s := <-sigs:
+			lock.Lock()
+			sig = s
+			lock.Unlock()
+			cancel()
+		case <-signalCtx.Done():
+		}
+	}()
+	received = func() os.Signal {
+		lock.Lock()
+		defer lock.Unlock()
+		return sig
+	}
+	return
+}
@@ -0,0 +1,85 @@
+package docradle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	exitStatus := exec.Command("sh", "-c", "exit 3").Run()
+	killed := exec.Command("sh", "-c", "kill -TERM $$").Run()
+	testcases := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{
+			name: "success",
+			err:  nil,
+			code: 0,
+		},
+		{
+			name: "error",
+			err:  errors.New("fail to run"),
+			code: ExitCodeError,
+		},
+		{
+			name: "dependency",
+			err:  fmt.Errorf("fail to run: %w", ErrDependencyNotReady),
+			code: ExitCodeDependencyFailed,
+		},
+		{
+			name: "timeout",
+			err:  fmt.Errorf("%w: no output for 1m0s", ErrTimeout),
+			code: ExitCodeTimeout,
+		},
+		{
+			name: "exit status of the command",
+			err:  exitStatus,
+			code: 3,
+		},
+		{
+			name: "command killed by signal",
+			err:  fmt.Errorf("wrapped: %w", killed),
+			code: 143,
+		},
+		{
+			name: "SIGINT",
+			err:  &CanceledError{Signal: syscall.SIGINT},
+			code: 130,
+		},
+		{
+			name: "SIGTERM",
+			err:  fmt.Errorf("wrapped: %w", &CanceledError{Signal: syscall.SIGTERM}),
+			code: 143,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, ExitCode(tt.err))
+		})
+	}
+}
+
+func TestSignalContext(t *testing.T) {
+	sigs := make(chan os.Signal, 1)
+	ctx, cancel, received := signalContext(context.Background(), sigs)
+	defer cancel()
+	assert.Nil(t, received())
+
+	sigs <- syscall.SIGTERM
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context is not canceled by signal")
+	}
+	assert.Equal(t, syscall.SIGTERM, received())
+}
@@ -4,6 +4,7 @@ go 1.13
 
 require (
 	cuelang.org/go v0.0.15
+	github.com/Shopify/sarama v1.22.1
 	github.com/StackExchange/wmi v0.0.0-20190523213315-cbe66965904d // indirect
 	github.com/deltam/go-lsd-parametrized v1.4.0
 	github.com/future-architect/fluentdpub v0.0.4
@@ -19,7 +20,10 @@ require (
 	go.pyspa.org/brbundle v1.1.3
 	gocloud.dev v0.18.0
 	gocloud.dev/pubsub/kafkapubsub v0.18.0
+	golang.org/x/net v0.0.0-20190620200207-3b0461eec859
 	golang.org/x/sync v0.0.0-20190911185100-cd5d95a43a6e
+	golang.org/x/sys v0.0.0-20191026070338-33540a1f6037
+	google.golang.org/grpc v1.21.1
 	gopkg.in/alecthomas/kingpin.v2 v2.2.6
 )
 
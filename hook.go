@@ -0,0 +1,214 @@
+package docradle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Hook is a command or HTTP call that runs at a point of the command's lifecycle
+type Hook struct {
+	Name          string
+	Command       []string
+	URL           string
+	Method        string
+	Timeout       time.Duration
+	FailurePolicy string
+}
+
+type cueHook struct {
+	Name          string   `json:"name"`
+	Command       []string `json:"command"`
+	URL           string   `json:"url"`
+	Method        string   `json:"method"`
+	Timeout       float64  `json:"timeout"`
+	FailurePolicy string   `json:"failurePolicy"`
+}
+
+// encodeHook converts the hook config. name is used if the hook doesn't have its own name.
+func encodeHook(name string, h *cueHook) (*Hook, error) {
+	if h == nil {
+		return nil, nil
+	}
+	if h.Name != "" {
+		name = h.Name
+	}
+	if (len(h.Command) == 0) == (h.URL == "") {
+		return nil, fmt.Errorf("%s should have one of command and url", name)
+	}
+	return &Hook{
+		Name:          name,
+		Command:       h.Command,
+		URL:           h.URL,
+		Method:        h.Method,
+		Timeout:       time.Duration(h.Timeout * float64(time.Second)),
+		FailurePolicy: h.FailurePolicy,
+	}, nil
+}
+
+// Hooks are commands that run before the command starts and after it exits
+type Hooks struct {
+	PreStart []*Hook
+	PostExit []*Hook
+}
+
+type cueHooks struct {
+	PreStart []*cueHook `json:"preStart"`
+	PostExit []*cueHook `json:"postExit"`
+}
+
+func encodeHooks(h cueHooks) (Hooks, error) {
+	var result Hooks
+	for i, src := range h.PreStart {
+		hook, err := encodeHook(fmt.Sprintf("preStart#%d", i+1), src)
+		if err != nil {
+			return result, err
+		}
+		result.PreStart = append(result.PreStart, hook)
+	}
+	for i, src := range h.PostExit {
+		hook, err := encodeHook(fmt.Sprintf("postExit#%d", i+1), src)
+		if err != nil {
+			return result, err
+		}
+		result.PostExit = append(result.PostExit, hook)
+	}
+	return result, nil
+}
+
+// runHook runs the hook until it finishes or its timeout
+//
+// Arguments of command and URL can contain environment variables.
+// Command fails if it exits with non-zero code, and HTTP call fails if the status is not 2xx.
+// Command runs as runAs user if it is not nil.
+// If loggers are passed, output of the command is written to them with "hook" tag.
+// Otherwise the beginning of the output is shown in the error message.
+func runHook(ctx context.Context, hook *Hook, envvar *EnvVar, runAs *credential, stdoutLogger, stderrLogger *Logger) error {
+	if hook.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hook.Timeout)
+		defer cancel()
+	}
+	if len(hook.Command) > 0 {
+		args := make([]string, len(hook.Command)-1)
+		for i, arg := range hook.Command[1:] {
+			args[i] = envvar.Expand(arg)
+		}
+		cmd := exec.Command(envvar.Expand(hook.Command[0]), args...)
+		cmd.Env = envvar.EnvsForExec()
+		runAs.applyTo(cmd)
+		output := &limitedBuffer{limit: maxCommandOutputSize}
+		var err error
+		if stdoutLogger != nil && stderrLogger != nil {
+			err = runWithLoggers(ctx, cmd, stdoutLogger.withTag("hook", hook.Name), stderrLogger.withTag("hook", hook.Name))
+		} else {
+			err = runWithWriter(ctx, cmd, output)
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%s hook timed out after %s", hook.Name, hook.Timeout)
+		} else if err != nil {
+			// exit status of the hook is not passed on as docradle's exit code (see ExitCode)
+			if text := strings.TrimSpace(output.String()); text != "" {
+				return fmt.Errorf("%s hook failed: %v: %s", hook.Name, err, text)
+			}
+			return fmt.Errorf("%s hook failed: %v", hook.Name, err)
+		}
+		return nil
+	}
+	method := hook.Method
+	if method == "" {
+		method = "GET"
+	}
+	req, err := http.NewRequest(method, envvar.Expand(hook.URL), nil)
+	if err != nil {
+		return fmt.Errorf("%s hook failed: %w", hook.Name, err)
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("%s hook failed: %w", hook.Name, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s hook failed: unexpected status %s", hook.Name, resp.Status)
+	}
+	return nil
+}
+
+// runWithLoggers runs the command and writes its output to the loggers line by line
+func runWithLoggers(ctx context.Context, cmd *exec.Cmd, stdoutLogger, stderrLogger *Logger) error {
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer stdoutReader.Close()
+	stderrReader, stderrWriter, err := os.Pipe()
+	if err != nil {
+		stdoutWriter.Close()
+		return err
+	}
+	defer stderrReader.Close()
+	cmd.Stdout = stdoutWriter
+	cmd.Stderr = stderrWriter
+	var outputs errgroup.Group
+	stdoutLogger.StartOutput(&outputs, stdoutReader)
+	stderrLogger.StartOutput(&outputs, stderrReader)
+	err = runInProcessGroup(ctx, cmd)
+	stdoutWriter.Close()
+	stderrWriter.Close()
+	waitOutputs(&outputs, outputFlushTimeout, stdoutReader, stderrReader)
+	return err
+}
+
+// runWithWriter runs the command and writes both stdout and stderr to the writer
+func runWithWriter(ctx context.Context, cmd *exec.Cmd, writer io.Writer) error {
+	reader, pipeWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	cmd.Stdout = pipeWriter
+	cmd.Stderr = pipeWriter
+	var outputs errgroup.Group
+	outputs.Go(func() error {
+		_, err := io.Copy(writer, reader)
+		return err
+	})
+	err = runInProcessGroup(ctx, cmd)
+	pipeWriter.Close()
+	waitOutputs(&outputs, outputFlushTimeout, reader)
+	return err
+}
+
+// runInProcessGroup runs the command in its own process group and kills the group when ctx is done
+//
+// Output of the command should be *os.File. Otherwise cmd.Wait() waits for background processes
+// of the command that keep the output open.
+func runInProcessGroup(ctx context.Context, cmd *exec.Cmd) error {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+	if err := startCommand(cmd); err != nil {
+		return err
+	}
+	defer releaseCommand(cmd)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			signalProcessGroup(cmd.Process, syscall.SIGKILL)
+		case <-done:
+		}
+	}()
+	return cmd.Wait()
+}
@@ -0,0 +1,160 @@
+package docradle
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	dirPath, err := ioutil.TempDir("", "cradle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dirPath)
+
+	envvar := NewEnvVar()
+	envvar.Register(fromOsEnv, "HOOK_DIR", dirPath)
+	envvar.Register(fromOsEnv, "SERVER", server.URL)
+
+	testcases := []struct {
+		name      string
+		hook      Hook
+		errorText string
+	}{
+		{
+			name: "ok: command",
+			hook: Hook{Command: []string{"touch", "${HOOK_DIR}/done"}},
+		},
+		{
+			name:      "ng: command failed",
+			hook:      Hook{Command: []string{"sh", "-c", "echo draining failed; exit 1"}},
+			errorText: "preStop hook failed: exit status 1: draining failed",
+		},
+		{
+			name:      "ng: timeout",
+			hook:      Hook{Command: []string{"sleep", "10"}, Timeout: 50 * time.Millisecond},
+			errorText: "preStop hook timed out after 50ms",
+		},
+		{
+			name: "ok: http",
+			hook: Hook{URL: "${SERVER}/shutdown", Method: "POST"},
+		},
+		{
+			name:      "ng: http status",
+			hook:      Hook{URL: "${SERVER}/shutdown"},
+			errorText: "preStop hook failed: unexpected status 405 Method Not Allowed",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := tt.hook
+			hook.Name = "preStop"
+			err := runHook(context.Background(), &hook, envvar, nil, nil, nil)
+			if tt.errorText == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorText)
+			}
+		})
+	}
+	_, err = os.Stat(filepath.Join(dirPath, "done"))
+	assert.NoError(t, err)
+}
+
+func TestRunHook_Loggers(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	stdoutLogger, err := NewLogger(context.Background(), StdOut, &stdout, "info", LogConfig{DefaultLevel: "info", PassThrough: true}, NewEnvVar())
+	assert.NoError(t, err)
+	stderrLogger, err := NewLogger(context.Background(), StdErr, &stderr, "info", LogConfig{DefaultLevel: "error", PassThrough: true}, NewEnvVar())
+	assert.NoError(t, err)
+
+	hook := &Hook{Name: "migrate", Command: []string{"sh", "-c", "echo migrated; echo warning >&2; exit 1"}}
+	err = runHook(context.Background(), hook, NewEnvVar(), nil, stdoutLogger, stderrLogger)
+	assert.EqualError(t, err, "migrate hook failed: exit status 1")
+	assert.Contains(t, stdout.String(), `"hook":"migrate"`)
+	assert.Contains(t, stdout.String(), `"message":"migrated"`)
+	assert.Contains(t, stderr.String(), `"hook":"migrate"`)
+	assert.Contains(t, stderr.String(), `"message":"warning"`)
+}
+
+func TestRunHook_ProcessGroup(t *testing.T) {
+	// background process is killed with the hook at timeout, so the output is closed soon
+	start := time.Now()
+	hook := &Hook{Name: "preStop", Command: []string{"sh", "-c", "sleep 10 & sleep 10"}, Timeout: 50 * time.Millisecond}
+	err := runHook(context.Background(), hook, NewEnvVar(), nil, nil, nil)
+	assert.EqualError(t, err, "preStop hook timed out after 50ms")
+	assert.True(t, time.Since(start) < outputFlushTimeout, "elapsed %s", time.Since(start))
+
+	// background process that keeps running after the hook exits doesn't block it
+	var stdout, stderr bytes.Buffer
+	stdoutLogger, err := NewLogger(context.Background(), StdOut, &stdout, "info", LogConfig{DefaultLevel: "info", PassThrough: true}, NewEnvVar())
+	assert.NoError(t, err)
+	stderrLogger, err := NewLogger(context.Background(), StdErr, &stderr, "info", LogConfig{DefaultLevel: "error", PassThrough: true}, NewEnvVar())
+	assert.NoError(t, err)
+	start = time.Now()
+	hook = &Hook{Name: "migrate", Command: []string{"sh", "-c", "sleep 10 & echo migrated"}}
+	err = runHook(context.Background(), hook, NewEnvVar(), nil, stdoutLogger, stderrLogger)
+	assert.NoError(t, err)
+	assert.True(t, time.Since(start) < 5*time.Second, "elapsed %s", time.Since(start))
+	assert.Contains(t, stdout.String(), `"message":"migrated"`)
+}
+
+func TestRunHook_User(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("changing user requires root privilege")
+	}
+	var stdout, stderr bytes.Buffer
+	stdoutLogger, err := NewLogger(context.Background(), StdOut, &stdout, "info", LogConfig{DefaultLevel: "info", PassThrough: true}, NewEnvVar())
+	assert.NoError(t, err)
+	stderrLogger, err := NewLogger(context.Background(), StdErr, &stderr, "info", LogConfig{DefaultLevel: "error", PassThrough: true}, NewEnvVar())
+	assert.NoError(t, err)
+
+	runAs := &credential{uid: 65534, gid: 65534, groups: []uint32{65534}, home: "/nonexistent"}
+	hook := &Hook{Name: "migrate", Command: []string{"sh", "-c", `echo "$(id -u):$(id -g)"; printenv HOME`}}
+	err = runHook(context.Background(), hook, NewEnvVar(), runAs, stdoutLogger, stderrLogger)
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), `"message":"65534:65534"`)
+	assert.Contains(t, stdout.String(), `"message":"/nonexistent"`)
+}
+
+func TestEncodeHooks(t *testing.T) {
+	hooks, err := encodeHooks(cueHooks{
+		PreStart: []*cueHook{
+			{Name: "migrate", Command: []string{"./migrate"}, Timeout: 60, FailurePolicy: "fail"},
+			{Command: []string{"./warm-cache"}, Timeout: 30, FailurePolicy: "ignore"},
+		},
+		PostExit: []*cueHook{
+			{URL: "http://localhost/exited", Method: "POST", Timeout: 1, FailurePolicy: "fail"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, Hooks{
+		PreStart: []*Hook{
+			{Name: "migrate", Command: []string{"./migrate"}, Timeout: time.Minute, FailurePolicy: "fail"},
+			{Name: "preStart#2", Command: []string{"./warm-cache"}, Timeout: 30 * time.Second, FailurePolicy: "ignore"},
+		},
+		PostExit: []*Hook{
+			{Name: "postExit#1", URL: "http://localhost/exited", Method: "POST", Timeout: time.Second, FailurePolicy: "fail"},
+		},
+	}, hooks)
+
+	_, err = encodeHooks(cueHooks{
+		PostExit: []*cueHook{{}},
+	})
+	assert.EqualError(t, err, "postExit#1 should have one of command and url")
+}
@@ -0,0 +1,145 @@
+package docradle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONPathAssertion is a simple condition for JSON response like `$.status == "UP"`
+//
+// It supports dot notation (`$.a.b`), bracket notation (`$['a']`) and array index (`$.items[0]`).
+// Operator is one of `==` and `!=`. If operator is omitted, it checks only existence of the path.
+type JSONPathAssertion struct {
+	source   string
+	path     []interface{}
+	operator string
+	expected interface{}
+}
+
+// ParseJSONPathAssertion parses JSON path assertion text
+//
+// The path is parsed first and the operator follows it, so the expected value can contain operators.
+func ParseJSONPathAssertion(src string) (*JSONPathAssertion, error) {
+	result := &JSONPathAssertion{
+		source: src,
+	}
+	pathSrc := strings.TrimSpace(src)
+	if !strings.HasPrefix(pathSrc, "$") {
+		return nil, fmt.Errorf("json path '%s' should start with '$'", src)
+	}
+	rest := pathSrc[1:]
+path:
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[ \t=!")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, fmt.Errorf("json path '%s' has empty key", src)
+			}
+			result.path = append(result.path, key)
+			rest = rest[end+1:]
+		case '[':
+			selector := strings.TrimSpace(rest[1:])
+			if len(selector) > 0 && (selector[0] == '\'' || selector[0] == '"') {
+				// quoted key can contain ']'
+				end := strings.IndexByte(selector[1:], selector[0])
+				if end == -1 {
+					return nil, fmt.Errorf("json path '%s' has unclosed quote", src)
+				}
+				key := selector[1 : end+1]
+				rest = strings.TrimSpace(selector[end+2:])
+				if !strings.HasPrefix(rest, "]") {
+					return nil, fmt.Errorf("json path '%s' has unclosed bracket", src)
+				}
+				result.path = append(result.path, key)
+				rest = rest[1:]
+				continue
+			}
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("json path '%s' has unclosed bracket", src)
+			}
+			index, err := strconv.Atoi(strings.TrimSpace(rest[1:end]))
+			if err != nil {
+				return nil, fmt.Errorf("json path '%s' has invalid selector '%s'", src, strings.TrimSpace(rest[1:end]))
+			}
+			result.path = append(result.path, index)
+			rest = rest[end+1:]
+		default:
+			break path
+		}
+	}
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return result, nil
+	}
+	for _, operator := range []string{"==", "!="} {
+		if strings.HasPrefix(rest, operator) {
+			expected := strings.TrimSpace(rest[len(operator):])
+			if err := json.Unmarshal([]byte(expected), &result.expected); err != nil {
+				return nil, fmt.Errorf("expected value of json path '%s' should be JSON literal: %w", src, err)
+			}
+			result.operator = operator
+			return result, nil
+		}
+	}
+	return nil, fmt.Errorf("json path '%s' has unexpected character '%c'", src, rest[0])
+}
+
+func (a JSONPathAssertion) String() string {
+	return a.source
+}
+
+// Check checks JSON content satisfies the assertion
+func (a JSONPathAssertion) Check(content []byte) error {
+	var root interface{}
+	if err := json.Unmarshal(content, &root); err != nil {
+		return errors.New("response body is not JSON")
+	}
+	current := root
+	for _, selector := range a.path {
+		switch s := selector.(type) {
+		case string:
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("json path '%s' doesn't exist in response", a.source)
+			}
+			if current, ok = object[s]; !ok {
+				return fmt.Errorf("json path '%s' doesn't exist in response", a.source)
+			}
+		case int:
+			array, ok := current.([]interface{})
+			if !ok || s < 0 || s >= len(array) {
+				return fmt.Errorf("json path '%s' doesn't exist in response", a.source)
+			}
+			current = array[s]
+		}
+	}
+	switch a.operator {
+	case "==":
+		if !reflect.DeepEqual(current, a.expected) {
+			return fmt.Errorf("json path '%s' is not satisfied (actual: %s)", a.source, jsonText(current))
+		}
+	case "!=":
+		if reflect.DeepEqual(current, a.expected) {
+			return fmt.Errorf("json path '%s' is not satisfied (actual: %s)", a.source, jsonText(current))
+		}
+	}
+	return nil
+}
+
+func jsonText(value interface{}) string {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(content)
+}
@@ -0,0 +1,165 @@
+package docradle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJSONPathAssertion(t *testing.T) {
+	testcases := []struct {
+		name     string
+		src      string
+		path     []interface{}
+		operator string
+		expected interface{}
+		ok       bool
+	}{
+		{
+			name:     "equal string",
+			src:      `$.status == "UP"`,
+			path:     []interface{}{"status"},
+			operator: "==",
+			expected: "UP",
+			ok:       true,
+		},
+		{
+			name:     "not equal number",
+			src:      `$.components.db.count != 0`,
+			path:     []interface{}{"components", "db", "count"},
+			operator: "!=",
+			expected: float64(0),
+			ok:       true,
+		},
+		{
+			name: "existence with bracket and index",
+			src:  `$['checks'][1].name`,
+			path: []interface{}{"checks", 1, "name"},
+			ok:   true,
+		},
+		{
+			name:     "operator in expected value",
+			src:      `$.x != "a==b"`,
+			path:     []interface{}{"x"},
+			operator: "!=",
+			expected: "a==b",
+			ok:       true,
+		},
+		{
+			name:     "operator in quoted key",
+			src:      `$['a==b'] == "c]"`,
+			path:     []interface{}{"a==b"},
+			operator: "==",
+			expected: "c]",
+			ok:       true,
+		},
+		{
+			name:     "no spaces around operator",
+			src:      `$.count==1`,
+			path:     []interface{}{"count"},
+			operator: "==",
+			expected: float64(1),
+			ok:       true,
+		},
+		{
+			name: "unknown operator",
+			src:  `$.count > 1`,
+			ok:   false,
+		},
+		{
+			name: "no root",
+			src:  `status == "UP"`,
+			ok:   false,
+		},
+		{
+			name: "invalid literal",
+			src:  `$.status == UP`,
+			ok:   false,
+		},
+		{
+			name: "unclosed bracket",
+			src:  `$.checks[0`,
+			ok:   false,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseJSONPathAssertion(tt.src)
+			if tt.ok {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.path, a.path)
+				assert.Equal(t, tt.operator, a.operator)
+				assert.Equal(t, tt.expected, a.expected)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestJSONPathAssertion_Check(t *testing.T) {
+	body := []byte(`{"status": "UP", "components": {"db": {"status": "DOWN", "count": 2}}, "checks": [{"name": "disk"}, {"name": "ping"}]}`)
+	testcases := []struct {
+		name string
+		src  string
+		ok   bool
+	}{
+		{
+			name: "equal",
+			src:  `$.status == "UP"`,
+			ok:   true,
+		},
+		{
+			name: "not equal",
+			src:  `$.components.db.status == "UP"`,
+			ok:   false,
+		},
+		{
+			name: "number",
+			src:  `$.components.db.count == 2`,
+			ok:   true,
+		},
+		{
+			name: "array index",
+			src:  `$.checks[1].name == "ping"`,
+			ok:   true,
+		},
+		{
+			name: "out of range",
+			src:  `$.checks[2].name`,
+			ok:   false,
+		},
+		{
+			name: "exists",
+			src:  `$.components['db']`,
+			ok:   true,
+		},
+		{
+			name: "missing",
+			src:  `$.version`,
+			ok:   false,
+		},
+		{
+			name: "not equal operator",
+			src:  `$.status != "DOWN"`,
+			ok:   true,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseJSONPathAssertion(tt.src)
+			assert.NoError(t, err)
+			if tt.ok {
+				assert.NoError(t, a.Check(body))
+			} else {
+				assert.Error(t, a.Check(body))
+			}
+		})
+	}
+}
+
+func TestJSONPathAssertion_CheckNotJSON(t *testing.T) {
+	a, err := ParseJSONPathAssertion(`$.status == "UP"`)
+	assert.NoError(t, err)
+	assert.EqualError(t, a.Check([]byte("<html></html>")), "response body is not JSON")
+}
@@ -0,0 +1,108 @@
+package docradle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// unlimited is RLIM_INFINITY
+const unlimited = ^uint64(0)
+
+// limitNames are resources available in process.limits
+var limitNames = []string{"nofile", "nproc", "core", "memlock", "stack"}
+
+// ResourceLimit is a soft and hard limit of process.limits
+type ResourceLimit struct {
+	Name string
+	Soft uint64
+	Hard uint64
+}
+
+// String returns the limit like "1024:4096" or "unlimited:unlimited"
+func (l ResourceLimit) String() string {
+	return formatLimit(l.Soft) + ":" + formatLimit(l.Hard)
+}
+
+func formatLimit(value uint64) string {
+	if value == unlimited {
+		return "unlimited"
+	}
+	return strconv.FormatUint(value, 10)
+}
+
+// parseLimits converts process.limits in the order of limitNames
+//
+// Each value is a number (both soft and hard limit), "unlimited" or "soft:hard".
+func parseLimits(limits map[string]interface{}) ([]ResourceLimit, error) {
+	var result []ResourceLimit
+	for name := range limits {
+		if indexOf(limitNames, name) == -1 {
+			return nil, fmt.Errorf("unknown resource '%s' (available: %s)", name, strings.Join(limitNames, ", "))
+		}
+	}
+	for _, name := range limitNames {
+		value, ok := limits[name]
+		if !ok {
+			continue
+		}
+		limit := ResourceLimit{Name: name}
+		var err error
+		switch v := value.(type) {
+		case float64:
+			if v < 0 || v != float64(uint64(v)) {
+				return nil, fmt.Errorf("%s should be positive integer: %v", name, v)
+			}
+			limit.Soft = uint64(v)
+			limit.Hard = limit.Soft
+		case string:
+			parts := strings.SplitN(v, ":", 2)
+			limit.Soft, err = parseLimit(parts[0])
+			if err == nil && len(parts) == 2 {
+				limit.Hard, err = parseLimit(parts[1])
+			} else {
+				limit.Hard = limit.Soft
+			}
+		default:
+			err = fmt.Errorf("unsupported value %v", v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s is invalid: %w", name, err)
+		}
+		if limit.Soft > limit.Hard {
+			return nil, fmt.Errorf("soft limit of %s is larger than hard limit: %s", name, limit)
+		}
+		result = append(result, limit)
+	}
+	return result, nil
+}
+
+func parseLimit(text string) (uint64, error) {
+	if text == "unlimited" {
+		return unlimited, nil
+	}
+	return strconv.ParseUint(text, 10, 64)
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// ProcessResources are effective resource limits and priorities of the started command
+//
+// Only resources that are specified in process section are recorded.
+type ProcessResources struct {
+	Limits      []ResourceLimit
+	Nice        *int
+	OOMScoreAdj *int
+}
+
+// empty returns true if no resources are recorded
+func (r *ProcessResources) empty() bool {
+	return r == nil || (len(r.Limits) == 0 && r.Nice == nil && r.OOMScoreAdj == nil)
+}
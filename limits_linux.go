@@ -0,0 +1,251 @@
+//go:build linux
+// +build linux
+
+package docradle
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// limitResources are resources of setrlimit(2) and their names in /proc/[pid]/limits
+var limitResources = map[string]struct {
+	resource int
+	label    string
+}{
+	"nofile":  {syscall.RLIMIT_NOFILE, "Max open files"},
+	"nproc":   {unix.RLIMIT_NPROC, "Max processes"},
+	"core":    {syscall.RLIMIT_CORE, "Max core file size"},
+	"memlock": {unix.RLIMIT_MEMLOCK, "Max locked memory"},
+	"stack":   {syscall.RLIMIT_STACK, "Max stack size"},
+}
+
+// resourcesEnv passes resourceRequest to docradle that starts as the command
+const resourcesEnv = "DOCRADLE_PROCESS_RESOURCES"
+
+// resourceRequest is process.limits, process.nice and process.oomScoreAdj of the command
+type resourceRequest struct {
+	Path        string          `json:"path"`
+	Limits      []ResourceLimit `json:"limits"`
+	Nice        *int            `json:"nice"`
+	OOMScoreAdj *int            `json:"oomScoreAdj"`
+	// Credential is process.user and process.group. They are set after resources because lower user can't raise them.
+	Credential *syscall.Credential `json:"credential"`
+}
+
+// RunResourceHelper applies process.limits, process.nice and process.oomScoreAdj and executes the command
+// if this process is started by docradle to run the command. Otherwise it returns immediately.
+//
+// It should be called at the beginning of main function.
+func RunResourceHelper() {
+	if request := os.Getenv(resourcesEnv); request != "" {
+		os.Unsetenv(resourcesEnv)
+		execWithResources(request)
+	}
+}
+
+// startCommandWithResources starts the command with process.limits, process.nice and process.oomScoreAdj
+//
+// They are applied only to the command without changing docradle itself: docradle starts itself as the child,
+// applies them in RunResourceHelper and executes the command in the same process (like runc).
+// The error is sent back through the pipe, that is closed when the command is executed successfully.
+func startCommandWithResources(cmd *exec.Cmd, process Process) error {
+	if len(process.Limits) == 0 && process.Nice == nil && process.OOMScoreAdj == nil {
+		return startCommand(cmd)
+	}
+	// exec.Command keeps the name as Path if lookup fails. Report the error here instead of from the helper
+	if _, err := exec.LookPath(cmd.Path); err != nil {
+		return err
+	}
+	req := resourceRequest{
+		Path:        cmd.Path,
+		Limits:      process.Limits,
+		Nice:        process.Nice,
+		OOMScoreAdj: process.OOMScoreAdj,
+	}
+	if cmd.SysProcAttr != nil && cmd.SysProcAttr.Credential != nil {
+		// the helper starts with docradle's privileges and drops them by itself
+		attr := *cmd.SysProcAttr
+		req.Credential = attr.Credential
+		attr.Credential = nil
+		cmd.SysProcAttr = &attr
+	}
+	request, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("can't apply process's resources: %w", err)
+	}
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	cmd.Path = self
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, resourcesEnv+"="+string(request))
+	// the pipe is fd 3 of the child
+	cmd.ExtraFiles = append([]*os.File{writer}, cmd.ExtraFiles...)
+	err = startCommand(cmd)
+	writer.Close()
+	if err != nil {
+		return err
+	}
+	message, _ := ioutil.ReadAll(reader)
+	if len(message) > 0 {
+		cmd.Wait()
+		releaseCommand(cmd)
+		return errors.New(string(message))
+	}
+	return nil
+}
+
+// execWithResources runs in the child process. It applies resources and executes the command.
+func execWithResources(request string) {
+	// niceness is per thread on Linux, so the thread that sets it should execute the command
+	runtime.LockOSThread()
+	errorPipe := os.NewFile(3, "resources")
+	var req resourceRequest
+	err := json.Unmarshal([]byte(request), &req)
+	if err == nil {
+		err = applyResources(req)
+	}
+	if err == nil {
+		err = applyCredential(req.Credential)
+	}
+	if err == nil {
+		syscall.CloseOnExec(3)
+		err = syscall.Exec(req.Path, os.Args, os.Environ())
+	}
+	io.WriteString(errorPipe, err.Error())
+	os.Exit(127)
+}
+
+func applyResources(req resourceRequest) error {
+	for _, limit := range req.Limits {
+		if err := syscall.Setrlimit(limitResources[limit.Name].resource, &syscall.Rlimit{Cur: limit.Soft, Max: limit.Hard}); err != nil {
+			return fmt.Errorf("can't set %s limit to %s: %w", limit.Name, limit, err)
+		}
+	}
+	if req.OOMScoreAdj != nil {
+		err := ioutil.WriteFile("/proc/self/oom_score_adj", []byte(strconv.Itoa(*req.OOMScoreAdj)), 0644)
+		if err != nil {
+			return fmt.Errorf("can't set oomScoreAdj to %d: %w", *req.OOMScoreAdj, err)
+		}
+	}
+	if req.Nice != nil {
+		if err := syscall.Setpriority(syscall.PRIO_PROCESS, 0, *req.Nice); err != nil {
+			return fmt.Errorf("can't set nice to %d: %w", *req.Nice, err)
+		}
+	}
+	return nil
+}
+
+// applyCredential changes the user and groups of the thread that executes the command
+//
+// They are changed only for the current thread (unlike syscall.Setuid of Go 1.16+), but execve(2) uses
+// the credential of the calling thread.
+func applyCredential(c *syscall.Credential) error {
+	if c == nil {
+		return nil
+	}
+	if !c.NoSetGroups {
+		groups := make([]int, len(c.Groups))
+		for i, group := range c.Groups {
+			groups[i] = int(group)
+		}
+		if err := unix.Setgroups(groups); err != nil {
+			return fmt.Errorf("can't set groups: %w", err)
+		}
+	}
+	if err := unix.Setresgid(int(c.Gid), int(c.Gid), int(c.Gid)); err != nil {
+		return fmt.Errorf("can't set gid to %d: %w", c.Gid, err)
+	}
+	if err := unix.Setresuid(int(c.Uid), int(c.Uid), int(c.Uid)); err != nil {
+		return fmt.Errorf("can't set uid to %d: %w", c.Uid, err)
+	}
+	return nil
+}
+
+// effectiveResources reads the resources of the command that are specified in process section
+func effectiveResources(pid int, process Process) *ProcessResources {
+	result := &ProcessResources{}
+	if len(process.Limits) > 0 {
+		limits, err := readLimits(pid)
+		if err == nil {
+			for _, limit := range process.Limits {
+				if effective, ok := limits[limitResources[limit.Name].label]; ok {
+					effective.Name = limit.Name
+					result.Limits = append(result.Limits, effective)
+				}
+			}
+		}
+	}
+	if process.Nice != nil {
+		// getpriority(2) system call returns 20 - nice
+		if priority, err := syscall.Getpriority(syscall.PRIO_PROCESS, pid); err == nil {
+			nice := 20 - priority
+			result.Nice = &nice
+		}
+	}
+	if process.OOMScoreAdj != nil {
+		content, err := ioutil.ReadFile("/proc/" + strconv.Itoa(pid) + "/oom_score_adj")
+		if err == nil {
+			if value, err := strconv.Atoi(strings.TrimSpace(string(content))); err == nil {
+				result.OOMScoreAdj = &value
+			}
+		}
+	}
+	return result
+}
+
+// readLimits parses /proc/[pid]/limits
+//
+// It is readable even if the command runs as other user.
+func readLimits(pid int) (map[string]ResourceLimit, error) {
+	file, err := os.Open("/proc/" + strconv.Itoa(pid) + "/limits")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	result := make(map[string]ResourceLimit)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		for _, resource := range limitResources {
+			if !strings.HasPrefix(line, resource.label+" ") {
+				continue
+			}
+			fields := strings.Fields(line[len(resource.label):])
+			if len(fields) < 2 {
+				continue
+			}
+			soft, err := parseLimit(fields[0])
+			if err != nil {
+				continue
+			}
+			hard, err := parseLimit(fields[1])
+			if err != nil {
+				continue
+			}
+			result[resource.label] = ResourceLimit{Soft: soft, Hard: hard}
+		}
+	}
+	return result, scanner.Err()
+}
@@ -0,0 +1,66 @@
+//go:build linux
+// +build linux
+
+package docradle
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExec_Resources(t *testing.T) {
+	config, err := ReadConfig("config.json", strings.NewReader(`{
+		"process": {"limits": {"nofile": "256:512", "core": 0}, "nice": 5, "oomScoreAdj": 100}
+	}`))
+	assert.NoError(t, err)
+
+	var before syscall.Rlimit
+	assert.NoError(t, syscall.Getrlimit(syscall.RLIMIT_NOFILE, &before))
+
+	stdout := &syncBuffer{}
+	script := `echo "$(ulimit -Sn):$(ulimit -Hn):$(ulimit -c):$(nice):$(cat /proc/self/oom_score_adj)"`
+	err = Exec(stdout, &syncBuffer{}, config, "sh", []string{"-c", script}, NewEnvVar())
+	assert.NoError(t, err)
+	output := stdout.String()
+	assert.Contains(t, output, `"message":"256:512:0:5:100"`)
+	assert.Contains(t, output, `"limits":{"nofile":"256:512","core":"0:0"},"nice":5,"oom-score-adj":100`)
+
+	// docradle itself keeps its resources
+	var after syscall.Rlimit
+	assert.NoError(t, syscall.Getrlimit(syscall.RLIMIT_NOFILE, &after))
+	assert.Equal(t, before, after)
+}
+
+func TestExec_ResourcesError(t *testing.T) {
+	// nofile can't be unlimited even for root because it is limited by fs.nr_open
+	config, err := ReadConfig("config.json", strings.NewReader(`{
+		"process": {"limits": {"nofile": "unlimited"}}
+	}`))
+	assert.NoError(t, err)
+
+	stderr := &syncBuffer{}
+	err = Exec(&syncBuffer{}, stderr, config, "sh", []string{"-c", "echo started"}, NewEnvVar())
+	assert.Error(t, err)
+	assert.Contains(t, stderr.String(), "can't set nofile limit")
+}
+
+func TestExec_ResourcesWithUser(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("only root can change user")
+	}
+	// negative nice needs privilege, so it should be applied before changing user
+	config, err := ReadConfig("config.json", strings.NewReader(`{
+		"process": {"user": "65534", "group": "65534", "limits": {"nofile": "256:512"}, "nice": -5, "oomScoreAdj": 100}
+	}`))
+	assert.NoError(t, err)
+
+	stdout := &syncBuffer{}
+	script := `echo "$(id -u):$(id -G):$(ulimit -Sn):$(ulimit -Hn):$(nice):$(cat /proc/self/oom_score_adj)"`
+	err = Exec(stdout, &syncBuffer{}, config, "sh", []string{"-c", script}, NewEnvVar())
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), `"message":"65534:65534:256:512:-5:100"`)
+}
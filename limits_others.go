@@ -0,0 +1,26 @@
+//go:build !linux
+// +build !linux
+
+package docradle
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// RunResourceHelper does nothing because docradle doesn't start itself to apply resources on this platform
+func RunResourceHelper() {
+}
+
+// startCommandWithResources starts the command. process.limits, process.nice and process.oomScoreAdj are available only on Linux
+func startCommandWithResources(cmd *exec.Cmd, process Process) error {
+	if len(process.Limits) > 0 || process.Nice != nil || process.OOMScoreAdj != nil {
+		return errors.New("process's limits, nice and oomScoreAdj are available only on Linux")
+	}
+	return startCommand(cmd)
+}
+
+// effectiveResources returns nil because no resources are specified on this platform
+func effectiveResources(pid int, process Process) *ProcessResources {
+	return nil
+}
@@ -0,0 +1,59 @@
+package docradle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimits(t *testing.T) {
+	testcases := []struct {
+		name     string
+		limits   map[string]interface{}
+		expected []ResourceLimit
+		hasError bool
+	}{
+		{
+			name:   "number",
+			limits: map[string]interface{}{"nofile": float64(65536)},
+			expected: []ResourceLimit{
+				{Name: "nofile", Soft: 65536, Hard: 65536},
+			},
+		},
+		{
+			name:   "soft and hard in order of names",
+			limits: map[string]interface{}{"stack": "8388608:unlimited", "core": "unlimited", "nproc": "1024:2048"},
+			expected: []ResourceLimit{
+				{Name: "nproc", Soft: 1024, Hard: 2048},
+				{Name: "core", Soft: unlimited, Hard: unlimited},
+				{Name: "stack", Soft: 8388608, Hard: unlimited},
+			},
+		},
+		{
+			name:     "error: unknown resource",
+			limits:   map[string]interface{}{"cpu": float64(10)},
+			hasError: true,
+		},
+		{
+			name:     "error: soft limit is larger than hard limit",
+			limits:   map[string]interface{}{"nofile": "4096:1024"},
+			hasError: true,
+		},
+		{
+			name:     "error: fraction",
+			limits:   map[string]interface{}{"core": 1.5},
+			hasError: true,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			limits, err := parseLimits(tt.limits)
+			if tt.hasError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, limits)
+			}
+		})
+	}
+}
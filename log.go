@@ -10,6 +10,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/future-architect/fluentdpub"
@@ -40,6 +41,7 @@ const (
 
 	LogLevelKey       = "level"
 	LogDocradleLogKey = "docradle-log"
+	LogProcessKey     = "process"
 )
 
 func init() {
@@ -54,6 +56,40 @@ type Logger struct {
 	tags         map[string]string
 	maskKeys     []string
 	structured   bool
+	tail         *logTail
+	archive      *logArchive
+	activity     *outputActivity
+}
+
+// logTail keeps the last lines of the command output for the exit report
+//
+// stdout and stderr loggers share it to keep the order of lines.
+type logTail struct {
+	lock  sync.Mutex
+	lines []string
+	limit int
+}
+
+func newLogTail(limit int) *logTail {
+	return &logTail{limit: limit}
+}
+
+func (t *logTail) add(line string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.lines = append(t.lines, line)
+	if len(t.lines) > t.limit {
+		t.lines = t.lines[len(t.lines)-t.limit:]
+	}
+}
+
+// take returns the kept lines and clears them
+func (t *logTail) take() []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	lines := t.lines
+	t.lines = nil
+	return lines
 }
 
 var logLevelMap = map[string]zerolog.Level{
@@ -121,10 +157,29 @@ func NewLogger(ctx context.Context, logType LogType, writer io.Writer, logLevelL
 	return logger, nil
 }
 
+// withTag returns the logger that adds the tag to each log
+//
+// The returned logger shares the output with l, and it doesn't keep lines for the exit report and process.logBucket.
+func (l *Logger) withTag(key, value string) *Logger {
+	logger := *l
+	logger.tags = make(map[string]string, len(l.tags)+1)
+	for k, v := range l.tags {
+		logger.tags[k] = v
+	}
+	logger.tags[key] = value
+	logger.tail = nil
+	logger.archive = nil
+	logger.activity = nil
+	return &logger
+}
+
 func (l *Logger) StartOutput(eg *errgroup.Group, reader io.ReadCloser) {
 	eg.Go(func() error {
 		scanner := bufio.NewScanner(reader)
 		for scanner.Scan() {
+			if l.activity != nil {
+				l.activity.touch()
+			}
 			l.Write(scanner.Text())
 		}
 		return nil
@@ -142,6 +197,7 @@ func (l *Logger) Write(line string) {
 	if parsed {
 		l.WriteMap(jsonMap)
 	} else {
+		l.record(line)
 		if l.console != nil {
 			event := l.console.WithLevel(l.defaultLevel)
 			for key, value := range l.tags {
@@ -164,6 +220,16 @@ func (l *Logger) Write(line string) {
 	}
 }
 
+// record keeps the output line of the command for the exit report and process.logBucket
+func (l *Logger) record(line string) {
+	if l.tail != nil {
+		l.tail.add(line)
+	}
+	if l.archive != nil {
+		l.archive.add(line)
+	}
+}
+
 func (l *Logger) WriteMap(log map[string]interface{}) {
 	logLevel := l.logLevel
 	if levelItem, ok := log[LogLevelKey]; ok {
@@ -179,6 +245,11 @@ func (l *Logger) WriteMap(log map[string]interface{}) {
 			log[maskKey] = "********"
 		}
 	}
+	if l.tail != nil || l.archive != nil {
+		if line, err := json.Marshal(log); err == nil {
+			l.record(string(line))
+		}
+	}
 	if l.console != nil {
 		event := l.console.WithLevel(logLevel)
 		for key, value := range l.tags {
@@ -235,7 +306,10 @@ func (l *Logger) WriteMetrics(memUsage uint64, memPercent float32, cpuPercent fl
 	}
 }
 
-func (l *Logger) WriteProcessStart(startAt time.Time, pid int, dir, cmd string, args []string) {
+// WriteProcessStart writes the start event of the command
+//
+// resources are the effective resource limits and priorities specified in process section (nil if nothing is specified).
+func (l *Logger) WriteProcessStart(startAt time.Time, pid int, dir, cmd string, args []string, resources *ProcessResources) {
 	if l.console != nil {
 		event := l.console.WithLevel(zerolog.InfoLevel)
 		event.Str(LogDocradleLogKey, "start").
@@ -243,6 +317,21 @@ func (l *Logger) WriteProcessStart(startAt time.Time, pid int, dir, cmd string,
 			Str("work-directory", dir).
 			Str("command", cmd).
 			Strs("arguments", args)
+		if !resources.empty() {
+			if len(resources.Limits) > 0 {
+				limits := zerolog.Dict()
+				for _, limit := range resources.Limits {
+					limits.Str(limit.Name, limit.String())
+				}
+				event.Dict("limits", limits)
+			}
+			if resources.Nice != nil {
+				event.Int("nice", *resources.Nice)
+			}
+			if resources.OOMScoreAdj != nil {
+				event.Int("oom-score-adj", *resources.OOMScoreAdj)
+			}
+		}
 		for key, value := range l.tags {
 			event.Str(key, value)
 		}
@@ -260,17 +349,36 @@ func (l *Logger) WriteProcessStart(startAt time.Time, pid int, dir, cmd string,
 		metadata["work-directory"] = dir
 		metadata["command"] = cmd
 		metadata["arguments"] = strings.Join(args, " ")
+		if !resources.empty() {
+			limits := make([]string, 0, len(resources.Limits))
+			for _, limit := range resources.Limits {
+				limits = append(limits, limit.Name+"="+limit.String())
+			}
+			if len(limits) > 0 {
+				metadata["limits"] = strings.Join(limits, " ")
+			}
+			if resources.Nice != nil {
+				metadata["nice"] = strconv.Itoa(*resources.Nice)
+			}
+			if resources.OOMScoreAdj != nil {
+				metadata["oom-score-adj"] = strconv.Itoa(*resources.OOMScoreAdj)
+			}
+		}
 		l.transporter.Send(context.TODO(), &pubsub.Message{
 			Metadata: metadata,
 		})
 	}
 }
 
-func (l *Logger) WriteProcessResult(exitAt time.Time, status string, wallClock, user, sys time.Duration) {
+// WriteProcessResult writes the exit status of the command
+//
+// termination is "exited" (the command exits by itself), "graceful" (it exits after stop signal) or "killed" (after stop timeout).
+func (l *Logger) WriteProcessResult(exitAt time.Time, status, termination string, wallClock, user, sys time.Duration) {
 	if l.console != nil {
 		event := l.console.WithLevel(zerolog.InfoLevel)
 		event.Str(LogDocradleLogKey, "result").
 			Str("process-status", status).
+			Str("termination", termination).
 			Dur("wallclock-time", wallClock).
 			Dur("user-time", user).
 			Dur("sysetm-time", sys)
@@ -280,7 +388,7 @@ func (l *Logger) WriteProcessResult(exitAt time.Time, status string, wallClock,
 		event.Send()
 	}
 	if l.transporter != nil {
-		metadata := make(map[string]string, len(l.tags)+7)
+		metadata := make(map[string]string, len(l.tags)+8)
 		metadata[LogLevelKey] = zerolog.InfoLevel.String()
 		for key, value := range l.tags {
 			metadata[key] = value
@@ -288,6 +396,7 @@ func (l *Logger) WriteProcessResult(exitAt time.Time, status string, wallClock,
 		metadata[LogDocradleLogKey] = "result"
 		metadata["time"] = strconv.FormatInt(exitAt.Unix(), 10)
 		metadata["process-status"] = status
+		metadata["termination"] = termination
 		metadata["wallclock-time"] = wallClock.String()
 		metadata["user-time"] = user.String()
 		metadata["system-time"] = sys.String()
@@ -297,6 +406,113 @@ func (l *Logger) WriteProcessResult(exitAt time.Time, status string, wallClock,
 	}
 }
 
+// WriteDependencyState writes state change of monitored dependency
+//
+// state is "up" or "down". reason is the error of the check for "down",
+// and downtime is the duration of the down state for "up".
+func (l *Logger) WriteDependencyState(changedAt time.Time, target, state, reason string, downtime time.Duration) {
+	level := zerolog.InfoLevel
+	if state == "down" {
+		level = zerolog.WarnLevel
+	}
+	if l.console != nil {
+		event := l.console.WithLevel(level)
+		event.Str(LogDocradleLogKey, "dependency").
+			Str("dependency", target).
+			Str("state", state)
+		if reason != "" {
+			event.Str("reason", reason)
+		}
+		if downtime > 0 {
+			event.Dur("downtime", downtime)
+		}
+		for key, value := range l.tags {
+			event.Str(key, value)
+		}
+		event.Send()
+	}
+	if l.transporter != nil {
+		metadata := make(map[string]string, len(l.tags)+7)
+		metadata[LogLevelKey] = level.String()
+		for key, value := range l.tags {
+			metadata[key] = value
+		}
+		metadata[LogDocradleLogKey] = "dependency"
+		metadata["time"] = strconv.FormatInt(changedAt.Unix(), 10)
+		metadata["dependency"] = target
+		metadata["state"] = state
+		if reason != "" {
+			metadata["reason"] = reason
+		}
+		if downtime > 0 {
+			metadata["downtime"] = downtime.String()
+		}
+		l.transporter.Send(context.TODO(), &pubsub.Message{
+			Metadata: metadata,
+		})
+	}
+}
+
+// WriteRestart writes restart of the command by process.restart policy
+//
+// attempt is the count of consecutive restarts and previousStatus is the exit status of the last run.
+func (l *Logger) WriteRestart(restartAt time.Time, attempt int, previousStatus string, delay time.Duration) {
+	if l.console != nil {
+		event := l.console.WithLevel(zerolog.WarnLevel)
+		event.Str(LogDocradleLogKey, "restart").
+			Int("attempt", attempt).
+			Str("previous-status", previousStatus).
+			Dur("delay", delay)
+		for key, value := range l.tags {
+			event.Str(key, value)
+		}
+		event.Send()
+	}
+	if l.transporter != nil {
+		metadata := make(map[string]string, len(l.tags)+6)
+		metadata[LogLevelKey] = zerolog.WarnLevel.String()
+		for key, value := range l.tags {
+			metadata[key] = value
+		}
+		metadata[LogDocradleLogKey] = "restart"
+		metadata["time"] = strconv.FormatInt(restartAt.Unix(), 10)
+		metadata["attempt"] = strconv.Itoa(attempt)
+		metadata["previous-status"] = previousStatus
+		metadata["delay"] = delay.String()
+		l.transporter.Send(context.TODO(), &pubsub.Message{
+			Metadata: metadata,
+		})
+	}
+}
+
+// WriteTimeout writes that the command is stopped by process.timeout (reason is "timeout") or process.watchdog ("watchdog")
+func (l *Logger) WriteTimeout(stopAt time.Time, reason string, limit time.Duration) {
+	if l.console != nil {
+		event := l.console.WithLevel(zerolog.WarnLevel)
+		event.Str(LogDocradleLogKey, "timeout").
+			Str("reason", reason).
+			Dur("limit", limit)
+		for key, value := range l.tags {
+			event.Str(key, value)
+		}
+		event.Send()
+	}
+	if l.transporter != nil {
+		metadata := make(map[string]string, len(l.tags)+5)
+		metadata[LogLevelKey] = zerolog.WarnLevel.String()
+		for key, value := range l.tags {
+			metadata[key] = value
+		}
+		metadata[LogDocradleLogKey] = "timeout"
+		metadata["time"] = strconv.FormatInt(stopAt.Unix(), 10)
+		metadata["reason"] = reason
+		metadata["limit"] = limit.String()
+		l.transporter.Send(context.TODO(), &pubsub.Message{
+			Metadata: metadata,
+		})
+	}
+}
+
 func (l *Logger) Close() {
 	if l.transporter != nil {
 		l.transporter.Shutdown(context.TODO())
@@ -159,7 +159,7 @@ func TestLog_WriteProcessStart(t *testing.T) {
 	assert.NoError(t, err)
 
 	startAt := time.Date(2020, time.January, 25, 10, 0, 0, 0, time.UTC)
-	logger.WriteProcessStart(startAt, 10, "/home/root", "time", []string{"echo", "hello"})
+	logger.WriteProcessStart(startAt, 10, "/home/root", "time", []string{"echo", "hello"}, nil)
 
 	assert.Equal(t,
 		`{"level":"info","docradle-log":"start","process-id":10,"work-directory":"/home/root","command":"time","arguments":["echo","hello"],"tag":"tag","time":1579946400}`+"\n",
@@ -176,3 +176,168 @@ func TestLog_WriteProcessStart(t *testing.T) {
 	assert.Equal(t, "echo hello", msg.Metadata["arguments"])
 	assert.Equal(t, "1579946400", msg.Metadata["time"])
 }
+
+func TestLog_WriteProcessStart_Resources(t *testing.T) {
+	var buffer bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger, err := NewLogger(ctx, StdOut, &buffer, "info", LogConfig{
+		Structured:   true,
+		DefaultLevel: "info",
+		PassThrough:  true,
+		ExportConfig: "mem://stdout",
+	}, NewEnvVar())
+	assert.NoError(t, err)
+
+	sub, err := pubsub.OpenSubscription(ctx, "mem://stdout")
+	assert.NoError(t, err)
+
+	nice := 5
+	oomScoreAdj := 100
+	startAt := time.Date(2020, time.January, 25, 10, 0, 0, 0, time.UTC)
+	logger.WriteProcessStart(startAt, 10, "/home/root", "app", nil, &ProcessResources{
+		Limits: []ResourceLimit{
+			{Name: "nofile", Soft: 1024, Hard: 4096},
+			{Name: "core", Soft: unlimited, Hard: unlimited},
+		},
+		Nice:        &nice,
+		OOMScoreAdj: &oomScoreAdj,
+	})
+
+	assert.Equal(t,
+		`{"level":"info","docradle-log":"start","process-id":10,"work-directory":"/home/root","command":"app","arguments":[],"limits":{"nofile":"1024:4096","core":"unlimited:unlimited"},"nice":5,"oom-score-adj":100,"time":1579946400}`+"\n",
+		buffer.String())
+
+	msg, err := sub.Receive(ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "nofile=1024:4096 core=unlimited:unlimited", msg.Metadata["limits"])
+	assert.Equal(t, "5", msg.Metadata["nice"])
+	assert.Equal(t, "100", msg.Metadata["oom-score-adj"])
+}
+
+func TestLog_WriteDependencyState(t *testing.T) {
+	var buffer bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger, err := NewLogger(ctx, StdOut, &buffer, "info", LogConfig{
+		Structured:   true,
+		DefaultLevel: "info",
+		PassThrough:  true,
+		ExportConfig: "mem://stdout",
+		Tags:         map[string]string{"tag": "tag"},
+	}, NewEnvVar())
+	assert.NoError(t, err)
+
+	sub, err := pubsub.OpenSubscription(ctx, "mem://stdout")
+	assert.NoError(t, err)
+
+	changedAt := time.Date(2020, time.January, 25, 10, 0, 0, 0, time.UTC)
+	logger.WriteDependencyState(changedAt, "db: tcp://db:5432", "down", "connection refused", 0)
+	logger.WriteDependencyState(changedAt, "db: tcp://db:5432", "up", "", 3*time.Second)
+
+	assert.Equal(t,
+		`{"level":"warn","docradle-log":"dependency","dependency":"db: tcp://db:5432","state":"down","reason":"connection refused","tag":"tag","time":1579946400}`+"\n"+
+			`{"level":"info","docradle-log":"dependency","dependency":"db: tcp://db:5432","state":"up","downtime":3000,"tag":"tag","time":1579946400}`+"\n",
+		buffer.String())
+
+	// mem pubsub doesn't keep the order of messages
+	states := make(map[string]map[string]string)
+	for i := 0; i < 2; i++ {
+		msg, err := sub.Receive(ctx)
+		assert.NoError(t, err)
+		states[msg.Metadata["state"]] = msg.Metadata
+	}
+
+	assert.Equal(t, "warn", states["down"]["level"])
+	assert.Equal(t, "dependency", states["down"]["docradle-log"])
+	assert.Equal(t, "db: tcp://db:5432", states["down"]["dependency"])
+	assert.Equal(t, "connection refused", states["down"]["reason"])
+
+	assert.Equal(t, "info", states["up"]["level"])
+	assert.Equal(t, "3s", states["up"]["downtime"])
+}
+
+func TestLog_WriteRestart(t *testing.T) {
+	var buffer bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger, err := NewLogger(ctx, StdOut, &buffer, "info", LogConfig{
+		Structured:   true,
+		DefaultLevel: "info",
+		PassThrough:  true,
+		ExportConfig: "mem://restart",
+		Tags:         map[string]string{"tag": "tag"},
+	}, NewEnvVar())
+	assert.NoError(t, err)
+
+	sub, err := pubsub.OpenSubscription(ctx, "mem://restart")
+	assert.NoError(t, err)
+
+	restartAt := time.Date(2020, time.January, 25, 10, 0, 0, 0, time.UTC)
+	logger.WriteRestart(restartAt, 2, "exit status 1", 2*time.Second)
+
+	assert.Equal(t,
+		`{"level":"warn","docradle-log":"restart","attempt":2,"previous-status":"exit status 1","delay":2000,"tag":"tag","time":1579946400}`+"\n",
+		buffer.String())
+
+	msg, err := sub.Receive(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "warn", msg.Metadata["level"])
+	assert.Equal(t, "restart", msg.Metadata["docradle-log"])
+	assert.Equal(t, "2", msg.Metadata["attempt"])
+	assert.Equal(t, "exit status 1", msg.Metadata["previous-status"])
+	assert.Equal(t, "2s", msg.Metadata["delay"])
+	assert.Equal(t, "tag", msg.Metadata["tag"])
+}
+
+func TestLog_WriteTimeout(t *testing.T) {
+	var buffer bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger, err := NewLogger(ctx, StdOut, &buffer, "info", LogConfig{
+		Structured:   true,
+		DefaultLevel: "info",
+		PassThrough:  true,
+		ExportConfig: "mem://timeout",
+		Tags:         map[string]string{"tag": "tag"},
+	}, NewEnvVar())
+	assert.NoError(t, err)
+
+	sub, err := pubsub.OpenSubscription(ctx, "mem://timeout")
+	assert.NoError(t, err)
+
+	stopAt := time.Date(2020, time.January, 25, 10, 0, 0, 0, time.UTC)
+	logger.WriteTimeout(stopAt, "watchdog", time.Minute)
+
+	assert.Equal(t,
+		`{"level":"warn","docradle-log":"timeout","reason":"watchdog","limit":60000,"tag":"tag","time":1579946400}`+"\n",
+		buffer.String())
+
+	msg, err := sub.Receive(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "warn", msg.Metadata["level"])
+	assert.Equal(t, "timeout", msg.Metadata["docradle-log"])
+	assert.Equal(t, "watchdog", msg.Metadata["reason"])
+	assert.Equal(t, "1m0s", msg.Metadata["limit"])
+	assert.Equal(t, "tag", msg.Metadata["tag"])
+}
+
+func TestLog_Tail(t *testing.T) {
+	var buffer bytes.Buffer
+	logger, err := NewLogger(context.Background(), StdOut, &buffer, "info", LogConfig{
+		Structured:   true,
+		DefaultLevel: "info",
+		PassThrough:  true,
+		Mask:         []string{"password"},
+	}, NewEnvVar())
+	assert.NoError(t, err)
+	logger.tail = newLogTail(2)
+
+	logger.Write("first")
+	logger.Write(`{"message":"login","password":"secret"}`)
+	logger.Write("last")
+
+	assert.Equal(t, []string{`{"message":"login","password":"********"}`, "last"}, logger.tail.take())
+	assert.Empty(t, logger.tail.take())
+}
@@ -0,0 +1,103 @@
+package docradle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"text/template"
+	"time"
+
+	"gocloud.dev/blob"
+	_ "gocloud.dev/blob/fileblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/memblob"
+	_ "gocloud.dev/blob/s3blob"
+)
+
+// logArchive keeps output lines of the command in gzip compressed temporary file to upload to process.logBucket
+type logArchive struct {
+	lock   sync.Mutex
+	file   *os.File
+	writer *gzip.Writer
+}
+
+func newLogArchive() (*logArchive, error) {
+	file, err := ioutil.TempFile("", "docradle-log-*.gz")
+	if err != nil {
+		return nil, fmt.Errorf("Can't create temporary file for process's logBucket: %w", err)
+	}
+	return &logArchive{
+		file:   file,
+		writer: gzip.NewWriter(file),
+	}, nil
+}
+
+func (a *logArchive) add(line string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	io.WriteString(a.writer, line)
+	io.WriteString(a.writer, "\n")
+}
+
+// upload writes the compressed log to the bucket
+func (a *logArchive) upload(ctx context.Context, bucket *blob.Bucket, key string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if err := a.writer.Close(); err != nil {
+		return err
+	}
+	if _, err := a.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	writer, err := bucket.NewWriter(ctx, key, &blob.WriterOptions{
+		ContentType: "application/gzip",
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(writer, a.file); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+// Close removes the temporary file
+func (a *logArchive) Close() {
+	a.file.Close()
+	os.Remove(a.file.Name())
+}
+
+// logKeyParams are parameters of process.logKey template
+type logKeyParams struct {
+	Hostname  string
+	StartTime string // like "20200125T100000Z"
+	Command   string // base name of the command
+	ProcessID int
+}
+
+func parseLogKey(text string) (*template.Template, error) {
+	return template.New("logKey").Option("missingkey=error").Parse(text)
+}
+
+// logKey returns the object key of the log
+func logKey(tmpl *template.Template, command string, pid int, startAt time.Time) (string, error) {
+	hostname, _ := os.Hostname()
+	var key bytes.Buffer
+	err := tmpl.Execute(&key, logKeyParams{
+		Hostname:  hostname,
+		StartTime: startAt.UTC().Format("20060102T150405Z"),
+		Command:   filepath.Base(command),
+		ProcessID: pid,
+	})
+	if err != nil {
+		return "", err
+	}
+	return key.String(), nil
+}
@@ -0,0 +1,86 @@
+package docradle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gocloud.dev/blob/memblob"
+)
+
+func TestLogArchive_Upload(t *testing.T) {
+	archive, err := newLogArchive()
+	assert.NoError(t, err)
+	defer archive.Close()
+
+	archive.add("first")
+	archive.add(`{"message":"last"}`)
+
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+	assert.NoError(t, archive.upload(ctx, bucket, "host/log.gz"))
+
+	attrs, err := bucket.Attributes(ctx, "host/log.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, "application/gzip", attrs.ContentType)
+
+	data, err := bucket.ReadAll(ctx, "host/log.gz")
+	assert.NoError(t, err)
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+	content, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "first\n{\"message\":\"last\"}\n", string(content))
+
+	name := archive.file.Name()
+	archive.Close()
+	_, err = os.Stat(name)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLogKey(t *testing.T) {
+	hostname, _ := os.Hostname()
+	startAt := time.Date(2020, time.January, 25, 19, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+
+	testcases := []struct {
+		name     string
+		template string
+		key      string
+		hasError bool
+	}{
+		{
+			name:     "default",
+			template: "{{.Hostname}}/{{.StartTime}}.log.gz",
+			key:      hostname + "/20200125T100000Z.log.gz",
+		},
+		{
+			name:     "command and process id",
+			template: "logs/{{.Command}}-{{.ProcessID}}.log.gz",
+			key:      "logs/app-12.log.gz",
+		},
+		{
+			name:     "unknown field",
+			template: "{{.Unknown}}.log.gz",
+			hasError: true,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := parseLogKey(tt.template)
+			assert.NoError(t, err)
+			key, err := logKey(tmpl, "/usr/local/bin/app", 12, startAt)
+			if tt.hasError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.key, key)
+			}
+		})
+	}
+}
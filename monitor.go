@@ -0,0 +1,95 @@
+package docradle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MonitorDependencies keeps checking dependencies that have "monitor" option until ctx is done
+//
+// State changes are written to logger as "dependency" events. If a critical dependency is down
+// longer than the threshold, onDown is called once per down period.
+func MonitorDependencies(ctx context.Context, dependsOns []DependsOn, config DependencyMonitor, envvar *EnvVar, logger *Logger, onDown func(dependsOn DependsOn, downtime time.Duration)) {
+	var wg sync.WaitGroup
+	for _, dependsOn := range dependsOns {
+		if !dependsOn.Monitor {
+			continue
+		}
+		wg.Add(1)
+		go func(dependsOn DependsOn) {
+			defer wg.Done()
+			monitorDependency(ctx, dependsOn, config, envvar, logger, onDown)
+		}(dependsOn)
+	}
+	wg.Wait()
+}
+
+func monitorDependency(ctx context.Context, dependsOn DependsOn, config DependencyMonitor, envvar *EnvVar, logger *Logger, onDown func(dependsOn DependsOn, downtime time.Duration)) {
+	check := monitorCheck(dependsOn, config.Interval)
+	var downSince time.Time
+	notified := false
+	for {
+		timer := time.NewTimer(config.Interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		var result DependsOnCheckResult
+		if len(check.AnyOf) > 0 {
+			result = waitForAnyOf(ctx, check, envvar)
+		} else {
+			result = waitFor(ctx, check, envvar)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		now := time.Now()
+		if result.error == nil {
+			if !downSince.IsZero() {
+				logger.WriteDependencyState(now, result.label(), "up", "", now.Sub(downSince))
+				downSince = time.Time{}
+				notified = false
+			}
+			continue
+		}
+		if downSince.IsZero() {
+			downSince = now
+			logger.WriteDependencyState(now, result.label(), "down", monitorReason(result), 0)
+		}
+		if dependsOn.Critical && !notified && now.Sub(downSince) >= config.Threshold && onDown != nil {
+			notified = true
+			onDown(dependsOn, now.Sub(downSince))
+		}
+	}
+}
+
+// monitorCheck returns dependency setting that accesses the target only once
+func monitorCheck(dependsOn DependsOn, interval time.Duration) DependsOn {
+	timeout := dependsOn.AttemptTimeout
+	if timeout == 0 || timeout > interval {
+		timeout = interval
+	}
+	dependsOn.After = nil
+	dependsOn.Timeout = timeout
+	dependsOn.Interval = timeout
+	dependsOn.AttemptTimeout = 0
+	dependsOn.Backoff = nil
+	if len(dependsOn.AnyOf) > 0 {
+		alternatives := make([]DependsOn, len(dependsOn.AnyOf))
+		for i, alternative := range dependsOn.AnyOf {
+			alternatives[i] = monitorCheck(alternative, interval)
+		}
+		dependsOn.AnyOf = alternatives
+	}
+	return dependsOn
+}
+
+func monitorReason(result DependsOnCheckResult) string {
+	if result.lastError != nil {
+		return result.lastError.Error()
+	}
+	return result.error.Error()
+}
@@ -0,0 +1,89 @@
+package docradle
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitorDependencies(t *testing.T) {
+	var healthy int32 = 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	time.AfterFunc(50*time.Millisecond, func() { atomic.StoreInt32(&healthy, 0) })
+	time.AfterFunc(200*time.Millisecond, func() { atomic.StoreInt32(&healthy, 1) })
+
+	var buffer bytes.Buffer
+	logger, err := NewLogger(context.Background(), StdOut, &buffer, "info", LogConfig{
+		DefaultLevel: "info",
+		PassThrough:  true,
+	}, NewEnvVar())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 350*time.Millisecond)
+	defer cancel()
+
+	var downs []time.Duration
+	MonitorDependencies(ctx, []DependsOn{
+		{
+			Name:     "api",
+			URL:      mustUrlParse(t, server.URL),
+			Timeout:  10 * time.Second,
+			Interval: time.Second,
+			Monitor:  true,
+			Critical: true,
+		},
+		{
+			// not monitored
+			URL: mustUrlParse(t, "tcp://127.0.0.1:1"),
+		},
+	}, DependencyMonitor{
+		Interval:  10 * time.Millisecond,
+		Threshold: 50 * time.Millisecond,
+	}, NewEnvVar(), logger, func(dependsOn DependsOn, downtime time.Duration) {
+		assert.Equal(t, "api", dependsOn.Name)
+		downs = append(downs, downtime)
+	})
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if assert.Len(t, lines, 2) {
+		assert.Contains(t, lines[0], `"level":"warn","docradle-log":"dependency","dependency":"api: `+server.URL+`","state":"down","reason":"unexpected status 503 Service Unavailable"`)
+		assert.Contains(t, lines[1], `"state":"up","downtime":`)
+	}
+	if assert.Len(t, downs, 1) {
+		assert.True(t, downs[0] >= 50*time.Millisecond)
+	}
+}
+
+func TestMonitorCheck(t *testing.T) {
+	check := monitorCheck(DependsOn{
+		Name:           "db",
+		After:          []string{"vault"},
+		Timeout:        10 * time.Second,
+		Interval:       time.Second,
+		AttemptTimeout: 3 * time.Second,
+		Backoff:        &Backoff{Initial: time.Second, Multiplier: 2},
+		AnyOf: []DependsOn{
+			{Timeout: 10 * time.Second, Interval: time.Second, AttemptTimeout: 500 * time.Millisecond},
+		},
+	}, 2*time.Second)
+	assert.Nil(t, check.After)
+	assert.Nil(t, check.Backoff)
+	assert.Equal(t, 2*time.Second, check.Timeout)
+	assert.Equal(t, 2*time.Second, check.Interval)
+	assert.Equal(t, time.Duration(0), check.AttemptTimeout)
+	assert.Equal(t, 500*time.Millisecond, check.AnyOf[0].Timeout)
+	assert.Equal(t, 500*time.Millisecond, check.AnyOf[0].Interval)
+}
@@ -0,0 +1,178 @@
+package docradle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"gocloud.dev/pubsub"
+)
+
+const (
+	// exitReportLogLines is the number of the last output lines in the exit report
+	exitReportLogLines = 20
+	// noticeTimeout is the timeout of sending the exit report to each target
+	noticeTimeout = 10 * time.Second
+)
+
+// ExitReport is the information of the command that is sent when the command exits
+//
+// Times are in seconds and peakMemory is in bytes.
+type ExitReport struct {
+	Hostname      string    `json:"hostname"`
+	Command       string    `json:"command"`
+	Arguments     []string  `json:"arguments"`
+	ProcessID     int       `json:"processId"`
+	Status        string    `json:"status"`
+	ExitCode      int       `json:"exitCode"`
+	Signal        string    `json:"signal,omitempty"`
+	Termination   string    `json:"termination"`
+	StartAt       time.Time `json:"startAt"`
+	ExitAt        time.Time `json:"exitAt"`
+	WallClockTime float64   `json:"wallClockTime"`
+	UserTime      float64   `json:"userTime"`
+	SystemTime    float64   `json:"systemTime"`
+	PeakMemory    uint64    `json:"peakMemory"`
+	LastLogs      []string  `json:"lastLogs"`
+}
+
+// newExitReport creates the report from the state of the exited command
+//
+// exitCode is -1 if the command is terminated by signal.
+func newExitReport(command string, args []string, state *os.ProcessState, termination string, startAt, exitAt time.Time, lastLogs []string) *ExitReport {
+	hostname, _ := os.Hostname()
+	report := &ExitReport{
+		Hostname:      hostname,
+		Command:       command,
+		Arguments:     args,
+		ProcessID:     state.Pid(),
+		Status:        state.String(),
+		ExitCode:      state.ExitCode(),
+		Termination:   termination,
+		StartAt:       startAt,
+		ExitAt:        exitAt,
+		WallClockTime: exitAt.Sub(startAt).Seconds(),
+		UserTime:      state.UserTime().Seconds(),
+		SystemTime:    state.SystemTime().Seconds(),
+		PeakMemory:    peakMemory(state),
+		LastLogs:      lastLogs,
+	}
+	if report.Arguments == nil {
+		report.Arguments = []string{}
+	}
+	if report.LastLogs == nil {
+		report.LastLogs = []string{}
+	}
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		report.Signal = signalName(status.Signal())
+	}
+	return report
+}
+
+// slackMessage is the payload for Slack's incoming webhook
+func (r *ExitReport) slackMessage() map[string]string {
+	commandLine := strings.Join(append([]string{r.Command}, r.Arguments...), " ")
+	text := fmt.Sprintf("`%s` on %s finished: %s (%s, wallclock time: %s)",
+		commandLine, r.Hostname, r.Status, r.Termination, time.Duration(r.WallClockTime*float64(time.Second)).Round(time.Millisecond))
+	if len(r.LastLogs) > 0 {
+		text += "\n```\n" + strings.Join(r.LastLogs, "\n") + "\n```"
+	}
+	return map[string]string{"text": text}
+}
+
+// exitNotifier sends the exit report to targets of process.noticeExitHttp, noticeExitSlack and noticeExitPubSub
+type exitNotifier struct {
+	httpURL  string
+	slackURL string
+	topic    *pubsub.Topic
+}
+
+// newExitNotifier returns nil if no targets are specified
+//
+// URLs can contain environment variables. noticeExitPubSub is a gocloud pubsub URL like "kafka://topic" or "mem://topic".
+func newExitNotifier(ctx context.Context, process Process, envvar *EnvVar) (*exitNotifier, error) {
+	if process.NoticeExitHTTP == "" && process.NoticeExitSlack == "" && process.NoticeExitPubSub == "" {
+		return nil, nil
+	}
+	n := &exitNotifier{
+		httpURL:  envvar.Expand(process.NoticeExitHTTP),
+		slackURL: envvar.Expand(process.NoticeExitSlack),
+	}
+	if process.NoticeExitPubSub != "" {
+		topic, err := pubsub.OpenTopic(ctx, envvar.Expand(process.NoticeExitPubSub))
+		if err != nil {
+			return nil, fmt.Errorf("Can't open topic of process's noticeExitPubSub: %w", err)
+		}
+		n.topic = topic
+	}
+	return n, nil
+}
+
+// notify sends the report to all targets and returns errors of failed targets
+func (n *exitNotifier) notify(ctx context.Context, report *ExitReport) []error {
+	ctx, cancel := context.WithTimeout(ctx, noticeTimeout)
+	defer cancel()
+	var errs []error
+	if n.httpURL != "" {
+		if err := postJSON(ctx, n.httpURL, report); err != nil {
+			errs = append(errs, fmt.Errorf("exit notification to http failed: %w", err))
+		}
+	}
+	if n.slackURL != "" {
+		if err := postJSON(ctx, n.slackURL, report.slackMessage()); err != nil {
+			errs = append(errs, fmt.Errorf("exit notification to slack failed: %w", err))
+		}
+	}
+	if n.topic != nil {
+		body, err := json.Marshal(report)
+		if err == nil {
+			err = n.topic.Send(ctx, &pubsub.Message{
+				Body: body,
+				Metadata: map[string]string{
+					LogDocradleLogKey: "exit",
+					"command":         report.Command,
+					"exit-code":       strconv.Itoa(report.ExitCode),
+				},
+			})
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("exit notification to pubsub failed: %w", err))
+		}
+	}
+	return errs
+}
+
+func (n *exitNotifier) Close() {
+	if n.topic != nil {
+		n.topic.Shutdown(context.TODO())
+	}
+}
+
+// postJSON sends the body as JSON and fails if the status is not 2xx
+func postJSON(ctx context.Context, url string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package docradle
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gocloud.dev/pubsub"
+)
+
+// exitedState runs the shell script and returns its state
+func exitedState(t *testing.T, script string) *os.ProcessState {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Run()
+	return cmd.ProcessState
+}
+
+func TestNewExitReport(t *testing.T) {
+	startAt := time.Date(2020, time.January, 25, 10, 0, 0, 0, time.UTC)
+	exitAt := startAt.Add(1500 * time.Millisecond)
+
+	testcases := []struct {
+		name     string
+		script   string
+		exitCode int
+		signal   string
+	}{
+		{
+			name:     "exit code",
+			script:   "exit 3",
+			exitCode: 3,
+		},
+		{
+			name:     "signal",
+			script:   "kill -TERM $$",
+			exitCode: -1,
+			signal:   "SIGTERM",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			state := exitedState(t, tt.script)
+			report := newExitReport("sh", []string{"-c", tt.script}, state, "exited", startAt, exitAt, []string{"line"})
+			assert.Equal(t, "sh", report.Command)
+			assert.Equal(t, state.Pid(), report.ProcessID)
+			assert.Equal(t, tt.exitCode, report.ExitCode)
+			assert.Equal(t, tt.signal, report.Signal)
+			assert.Equal(t, 1.5, report.WallClockTime)
+			assert.True(t, report.PeakMemory > 0)
+			assert.Equal(t, []string{"line"}, report.LastLogs)
+		})
+	}
+}
+
+func TestExitNotifier(t *testing.T) {
+	report := &ExitReport{
+		Hostname:      "host",
+		Command:       "app",
+		Arguments:     []string{"--port", "8080"},
+		Status:        "exit status 1",
+		ExitCode:      1,
+		Termination:   "exited",
+		WallClockTime: 2.5,
+		LastLogs:      []string{"first", "last"},
+	}
+
+	var received ExitReport
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer httpServer.Close()
+
+	var slack map[string]string
+	slackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&slack)
+	}))
+	defer slackServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	envvar := NewEnvVar()
+	envvar.Register(fromOsEnv, "SLACK_WEBHOOK_URL", slackServer.URL)
+	// notifier is not closed because mem topic is shared with other runs of this test
+	notifier, err := newExitNotifier(ctx, Process{
+		NoticeExitHTTP:   httpServer.URL,
+		NoticeExitSlack:  "${SLACK_WEBHOOK_URL}",
+		NoticeExitPubSub: "mem://exit-notifier",
+	}, envvar)
+	assert.NoError(t, err)
+	sub, err := pubsub.OpenSubscription(ctx, "mem://exit-notifier")
+	assert.NoError(t, err)
+	defer sub.Shutdown(context.Background())
+
+	errs := notifier.notify(ctx, report)
+	assert.Empty(t, errs)
+
+	assert.Equal(t, *report, received)
+	assert.Equal(t, "`app --port 8080` on host finished: exit status 1 (exited, wallclock time: 2.5s)\n```\nfirst\nlast\n```", slack["text"])
+
+	msg, err := sub.Receive(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	msg.Ack()
+	assert.Equal(t, "exit", msg.Metadata["docradle-log"])
+	assert.Equal(t, "1", msg.Metadata["exit-code"])
+	var published ExitReport
+	assert.NoError(t, json.Unmarshal(msg.Body, &published))
+	assert.Equal(t, *report, published)
+}
+
+func TestExitNotifier_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	notifier, err := newExitNotifier(context.Background(), Process{NoticeExitHTTP: server.URL}, NewEnvVar())
+	assert.NoError(t, err)
+	errs := notifier.notify(context.Background(), &ExitReport{})
+	if assert.Len(t, errs, 1) {
+		assert.True(t, strings.Contains(errs[0].Error(), "500"), errs[0].Error())
+	}
+}
+
+func TestNewExitNotifier_NoTargets(t *testing.T) {
+	notifier, err := newExitNotifier(context.Background(), Process{}, NewEnvVar())
+	assert.NoError(t, err)
+	assert.Nil(t, notifier)
+}
@@ -0,0 +1,176 @@
+package docradle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gookit/color"
+)
+
+// mainProcessName is the name of the command in the process tag when processes section exists
+const mainProcessName = "main"
+
+// newProcessExecution creates the execution of the entry in processes section
+//
+// The entry has its own loggers that tag lines with the name of the entry. They are closed by Close().
+func newProcessExecution(ctx context.Context, stdout, stderr io.Writer, config *Config, entry ProcessEntry, envvar *EnvVar) (*execution, error) {
+	entryConfig := *config
+	entryConfig.Stdout = withProcessTag(entry.Stdout, entry.Name)
+	entryConfig.Stderr = withProcessTag(entry.Stderr, entry.Name)
+	entryConfig.Process = entry.Process
+	entryConfig.DependsOn = entry.DependsOn
+	entryConfig.Processes = nil
+	entryConfig.Hooks = Hooks{}
+
+	stdoutLogger, err := NewLogger(ctx, StdOut, stdout, config.LogLevel, entryConfig.Stdout, envvar)
+	if err != nil {
+		return nil, fmt.Errorf("processes '%s': %w", entry.Name, err)
+	}
+	stderrLogger, err := NewLogger(ctx, StdErr, stderr, config.LogLevel, entryConfig.Stderr, envvar)
+	if err != nil {
+		stdoutLogger.Close()
+		return nil, fmt.Errorf("processes '%s': %w", entry.Name, err)
+	}
+	command := make([]string, len(entry.Command))
+	for i, arg := range entry.Command {
+		command[i] = envvar.Expand(arg)
+	}
+	e, err := newExecution(ctx, stdout, stderr, &entryConfig, command[0], command[1:], envvar, stdoutLogger, stderrLogger)
+	if err != nil {
+		stdoutLogger.Close()
+		stderrLogger.Close()
+		return nil, fmt.Errorf("processes '%s': %w", entry.Name, err)
+	}
+	e.name = entry.Name
+	e.waitFor = dependsOnAs(entry.DependsOn, e.credential)
+	e.onExit = entry.OnExit
+	e.ownLoggers = true
+	return e, nil
+}
+
+func withProcessTag(logConfig LogConfig, name string) LogConfig {
+	tags := make(map[string]string, len(logConfig.Tags)+1)
+	for k, v := range logConfig.Tags {
+		tags[k] = v
+	}
+	tags[LogProcessKey] = name
+	logConfig.Tags = tags
+	return logConfig
+}
+
+// processExit is the result of the execution in superviseAll
+type processExit struct {
+	index int
+	err   error
+}
+
+// superviseAll starts executions in order and waits until all of them finish
+//
+// Each execution starts after the previous one starts (and its dependencies get ready).
+// When an execution whose onExit is "stop" finishes, the others are stopped and its error is returned.
+// Otherwise the first error in order is returned.
+func superviseAll(ctx context.Context, executions []*execution) error {
+	exits := make(chan processExit, len(executions))
+	for i, e := range executions {
+		var previous *execution
+		if i > 0 {
+			previous = executions[i-1]
+		}
+		go func(i int, e, previous *execution) {
+			defer e.markStarted()
+			if previous != nil {
+				select {
+				case <-previous.started:
+				case <-e.stopRequest:
+					exits <- processExit{index: i}
+					return
+				}
+			}
+			if len(e.waitFor) > 0 {
+				if err := e.waitForDependencies(ctx); err != nil || e.stopRequested() {
+					exits <- processExit{index: i, err: err}
+					return
+				}
+			}
+			exits <- processExit{index: i, err: e.supervise(ctx)}
+		}(i, e, previous)
+	}
+
+	errs := make([]error, len(executions))
+	stopped := false
+	var result error
+	for range executions {
+		exit := <-exits
+		errs[exit.index] = exit.err
+		e := executions[exit.index]
+		if stopped || e.onExit != "stop" {
+			continue
+		}
+		stopped = true
+		result = exit.err
+		if len(executions) > 1 {
+			color.Fprintf(e.stderr, "<yellow>Process %s finished. Stopping other processes.</>\n", e.name)
+		}
+		for _, other := range executions {
+			if other != e {
+				other.requestStop()
+			}
+		}
+	}
+	if stopped {
+		return result
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForDependencies waits for dependsOn of the entry in processes section before the first start
+//
+// dependsOnTimeout limits the whole waiting time like dependsOn of the command.
+func (e *execution) waitForDependencies(ctx context.Context) error {
+	ctx, cancel, received := e.waitContext(ctx)
+	defer cancel()
+	if e.config.DependsOnTimeout > 0 {
+		var timeoutCancel context.CancelFunc
+		ctx, timeoutCancel = context.WithTimeout(ctx, e.config.DependsOnTimeout)
+		defer timeoutCancel()
+	}
+	color.Fprintf(e.stdout, "<bg=black;fg=lightBlue;op=reverse;>  Dependencies  </> <cyan>%s</>\n\n", e.name)
+	results := WaitForDependencies(ctx, e.waitFor, e.envvar, NewDependsOnProgress(e.stdout))
+	if sig := received(); sig != nil {
+		return &CanceledError{Signal: sig}
+	} else if e.stopRequested() {
+		return nil
+	}
+	var err error
+	for _, result := range results {
+		color.Fprintln(e.stdout, result.String())
+		if resultErr := result.Error(); resultErr != nil && err == nil {
+			err = fmt.Errorf("dependencies of process %s are not ready: %w: %v", e.name, ErrDependencyNotReady, resultErr)
+		}
+	}
+	return err
+}
+
+// broadcastSignals forwards signals received by docradle to all executions
+func broadcastSignals(ctx context.Context, sigs chan os.Signal, executions []*execution) {
+	for {
+		select {
+		case sig := <-sigs:
+			for _, e := range executions {
+				select {
+				case e.sigs <- sig:
+				default:
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package docradle
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExec_Processes(t *testing.T) {
+	testcases := []struct {
+		name      string
+		processes string
+		command   string
+		script    string
+		hasError  bool
+		messages  []string
+	}{
+		{
+			name:      "processes start before the command",
+			processes: `[{"name": "exporter", "command": ["sh", "-c", "echo exporter; sleep 10"]}]`,
+			command:   "sh",
+			script:    "sleep 0.2; echo main",
+			messages:  []string{`"process":"exporter","time":1579946400,"message":"exporter"`, `"process":"main","time":1579946400,"message":"main"`},
+		},
+		{
+			name:      "onExit stop stops the command",
+			processes: `[{"name": "worker", "command": ["sh", "-c", "sleep 0.2; exit 2"]}]`,
+			command:   "sh",
+			script:    "echo main; sleep 10",
+			hasError:  true,
+			messages:  []string{`"process":"main","time":1579946400,"message":"main"`},
+		},
+		{
+			name:      "onExit none keeps the command running",
+			processes: `[{"name": "worker", "command": ["sh", "-c", "exit 2"], "onExit": "none"}]`,
+			command:   "sh",
+			script:    "sleep 0.5; echo main",
+			messages:  []string{`"process":"main","time":1579946400,"message":"main"`},
+		},
+		{
+			name:      "without command",
+			processes: `[{"name": "first", "command": ["echo", "first"], "onExit": "none"}, {"name": "second", "command": ["sh", "-c", "sleep 0.2; echo second"]}]`,
+			messages:  []string{`"process":"first","time":1579946400,"message":"first"`, `"process":"second","time":1579946400,"message":"second"`},
+		},
+	}
+	// time is fixed in log_test.go
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ReadConfig("config.json", strings.NewReader(`{"processes": `+tt.processes+`}`))
+			assert.NoError(t, err)
+
+			stdout := &syncBuffer{}
+			var args []string
+			if tt.command != "" {
+				args = []string{"-c", tt.script}
+			}
+			start := time.Now()
+			err = Exec(stdout, &syncBuffer{}, config, tt.command, args, NewEnvVar())
+			if tt.hasError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.True(t, time.Since(start) < 5*time.Second, "other processes should be stopped")
+			output := stdout.String()
+			last := -1
+			for _, message := range tt.messages {
+				index := strings.Index(output, message)
+				assert.True(t, index > last, "%s should be shown in order: %s", message, output)
+				last = index
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+//go:build linux
+// +build linux
+
+package docradle
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// prSetChildSubreaper is PR_SET_CHILD_SUBREAPER of prctl(2)
+const prSetChildSubreaper = 36
+
+// startReaper reaps orphaned zombie processes until the returned stop function is called, like tini
+//
+// If docradle is not PID 1, it registers itself as child subreaper to adopt orphaned descendants of the command.
+// Children that are started by startCommand are left to os/exec. stop waits until the reaper finishes.
+func startReaper(ctx context.Context) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	if os.Getpid() != 1 {
+		syscall.RawSyscall(syscall.SYS_PRCTL, prSetChildSubreaper, 1, 0)
+	}
+	sigchld := make(chan os.Signal, 1)
+	signal.Notify(sigchld, syscall.SIGCHLD)
+	go func() {
+		defer close(done)
+		defer signal.Stop(sigchld)
+		// SIGCHLD can be coalesced, so check periodically too
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-sigchld:
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+			reapZombies()
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// reapZombies waits zombie children that are not managed by os/exec and returns their pids
+func reapZombies() (reaped []int) {
+	managedChildren.Lock()
+	defer managedChildren.Unlock()
+	for _, pid := range zombieChildren(os.Getpid()) {
+		if managedChildren.pids[pid] {
+			continue
+		}
+		var status syscall.WaitStatus
+		if wpid, err := syscall.Wait4(pid, &status, syscall.WNOHANG, nil); err == nil && wpid == pid {
+			reaped = append(reaped, pid)
+		}
+	}
+	return
+}
+
+// zombieChildren returns pids of zombie processes whose parent is ppid
+func zombieChildren(ppid int) (pids []int) {
+	stats, _ := filepath.Glob("/proc/[0-9]*/stat")
+	for _, stat := range stats {
+		content, err := ioutil.ReadFile(stat)
+		if err != nil {
+			continue
+		}
+		// format is "pid (comm) state ppid ...". comm can contain spaces and parentheses.
+		end := bytes.LastIndexByte(content, ')')
+		if end == -1 {
+			continue
+		}
+		fields := bytes.Fields(content[end+1:])
+		if len(fields) < 2 || string(fields[0]) != "Z" || string(fields[1]) != strconv.Itoa(ppid) {
+			continue
+		}
+		if pid, err := strconv.Atoi(filepath.Base(filepath.Dir(stat))); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return
+}
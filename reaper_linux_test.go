@@ -0,0 +1,46 @@
+package docradle
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func waitZombie(t *testing.T, pid int) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		for _, zombie := range zombieChildren(os.Getpid()) {
+			if zombie == pid {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("process %d doesn't become zombie", pid)
+}
+
+func TestReapZombies(t *testing.T) {
+	truePath, err := exec.LookPath("true")
+	assert.NoError(t, err)
+
+	t.Run("unmanaged child is reaped", func(t *testing.T) {
+		pid, err := syscall.ForkExec(truePath, []string{"true"}, nil)
+		assert.NoError(t, err)
+		waitZombie(t, pid)
+		assert.Contains(t, reapZombies(), pid)
+		assert.NotContains(t, zombieChildren(os.Getpid()), pid)
+	})
+
+	t.Run("managed child is left to os/exec", func(t *testing.T) {
+		cmd := exec.Command(truePath)
+		assert.NoError(t, startCommand(cmd))
+		waitZombie(t, cmd.Process.Pid)
+		assert.NotContains(t, reapZombies(), cmd.Process.Pid)
+		assert.NoError(t, cmd.Wait())
+		releaseCommand(cmd)
+	})
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package docradle
+
+import "context"
+
+// startReaper does nothing because child subreaper is available only on Linux
+func startReaper(ctx context.Context) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,84 @@
+package docradle
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	// defaultRestartDelay is the first delay before restart if process.restartBackoff.initial is not set
+	defaultRestartDelay = time.Second
+	// defaultMaxRestartDelay is the max delay before restart if process.restartBackoff is not set
+	defaultMaxRestartDelay = time.Minute
+	// defaultRestartWindow is used if process.restartWindow is not set
+	defaultRestartWindow = time.Minute
+)
+
+// runResult is the result of execution.run
+type runResult struct {
+	down        *downDependency // critical dependency that stopped the command
+	status      string          // exit status like "exit status 1"
+	termination string          // "exited", "graceful" or "killed"
+	duration    time.Duration   // wall clock time of the command
+}
+
+// restartTracker decides whether the command should be restarted by process.restart policy
+type restartTracker struct {
+	process   Process
+	attempts  int  // consecutive restarts in the crash loop
+	exhausted bool // restart count reaches process.maxRestarts
+}
+
+// next returns the delay before restart and true if the command should be restarted
+//
+// The command isn't restarted if it is stopped by signal. If the command ran longer than
+// process.restartWindow, it isn't a crash loop, so the restart count and delay are reset.
+// exhausted becomes true when it gives up by process.maxRestarts.
+func (r *restartTracker) next(result runResult, err error) (delay time.Duration, restart bool) {
+	if result.termination != "exited" {
+		return 0, false
+	}
+	switch r.process.Restart {
+	case "always":
+	case "on-failure":
+		if err == nil {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	window := r.process.RestartWindow
+	if window <= 0 {
+		window = defaultRestartWindow
+	}
+	if result.duration >= window {
+		r.attempts = 0
+	}
+	if r.process.MaxRestarts > 0 && r.attempts >= r.process.MaxRestarts {
+		r.exhausted = true
+		return 0, false
+	}
+	r.attempts++
+	return restartDelay(r.process.RestartBackoff, r.attempts), true
+}
+
+// restartDelay returns the delay before the attempt-th restart (starts from 1)
+//
+// backoff always exists because encodeProcess fills the default values.
+func restartDelay(backoff *Backoff, attempt int) time.Duration {
+	delay := backoff.Initial
+	if delay <= 0 {
+		delay = defaultRestartDelay
+	}
+	for i := 1; i < attempt && backoff.Multiplier > 1; i++ {
+		delay = time.Duration(float64(delay) * backoff.Multiplier)
+		if backoff.Max > 0 && delay >= backoff.Max {
+			delay = backoff.Max
+			break
+		}
+	}
+	if backoff.Jitter > 0 {
+		delay -= time.Duration(float64(delay) * backoff.Jitter * rand.Float64())
+	}
+	return delay
+}
@@ -0,0 +1,115 @@
+package docradle
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestartTracker_Next(t *testing.T) {
+	failure := errors.New("exit status 1")
+	crashed := runResult{status: "exit status 1", termination: "exited", duration: time.Second}
+	backoff := &Backoff{Initial: time.Second, Max: 4 * time.Second, Multiplier: 2}
+
+	type run struct {
+		result  runResult
+		err     error
+		restart bool
+		delay   time.Duration
+	}
+	testcases := []struct {
+		name      string
+		process   Process
+		runs      []run
+		exhausted bool
+	}{
+		{
+			name:    "no",
+			process: Process{Restart: "no", RestartBackoff: backoff},
+			runs: []run{
+				{result: crashed, err: failure, restart: false},
+			},
+		},
+		{
+			name:    "on-failure",
+			process: Process{Restart: "on-failure", RestartBackoff: backoff},
+			runs: []run{
+				{result: crashed, err: failure, restart: true, delay: time.Second},
+				{result: crashed, err: failure, restart: true, delay: 2 * time.Second},
+				{result: runResult{status: "exit status 0", termination: "exited"}, restart: false},
+			},
+		},
+		{
+			name:    "always",
+			process: Process{Restart: "always", RestartBackoff: backoff},
+			runs: []run{
+				{result: runResult{status: "exit status 0", termination: "exited"}, restart: true, delay: time.Second},
+				{result: crashed, err: failure, restart: true, delay: 2 * time.Second},
+			},
+		},
+		{
+			name:    "stopped by signal",
+			process: Process{Restart: "always", RestartBackoff: backoff},
+			runs: []run{
+				{result: runResult{status: "signal: terminated", termination: "graceful"}, err: failure, restart: false},
+			},
+		},
+		{
+			name:    "failed to start",
+			process: Process{Restart: "always", RestartBackoff: backoff},
+			runs: []run{
+				{err: failure, restart: false},
+			},
+		},
+		{
+			name:    "backoff up to max",
+			process: Process{Restart: "always", RestartBackoff: backoff},
+			runs: []run{
+				{result: crashed, err: failure, restart: true, delay: time.Second},
+				{result: crashed, err: failure, restart: true, delay: 2 * time.Second},
+				{result: crashed, err: failure, restart: true, delay: 4 * time.Second},
+				{result: crashed, err: failure, restart: true, delay: 4 * time.Second},
+			},
+		},
+		{
+			name:    "maxRestarts",
+			process: Process{Restart: "always", MaxRestarts: 2, RestartBackoff: backoff},
+			runs: []run{
+				{result: crashed, err: failure, restart: true, delay: time.Second},
+				{result: crashed, err: failure, restart: true, delay: 2 * time.Second},
+				{result: crashed, err: failure, restart: false},
+			},
+			exhausted: true,
+		},
+		{
+			name:    "reset after window",
+			process: Process{Restart: "always", MaxRestarts: 2, RestartBackoff: backoff, RestartWindow: time.Minute},
+			runs: []run{
+				{result: crashed, err: failure, restart: true, delay: time.Second},
+				{result: crashed, err: failure, restart: true, delay: 2 * time.Second},
+				{result: runResult{status: "exit status 1", termination: "exited", duration: 2 * time.Minute}, err: failure, restart: true, delay: time.Second},
+			},
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := &restartTracker{process: tt.process}
+			for i, r := range tt.runs {
+				delay, restart := tracker.next(r.result, r.err)
+				assert.Equal(t, r.restart, restart, "run %d", i)
+				assert.Equal(t, r.delay, delay, "run %d", i)
+			}
+			assert.Equal(t, tt.exhausted, tracker.exhausted)
+		})
+	}
+}
+
+func TestRestartDelay_Jitter(t *testing.T) {
+	backoff := &Backoff{Initial: time.Second, Multiplier: 2, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		delay := restartDelay(backoff, 2)
+		assert.True(t, delay > time.Second && delay <= 2*time.Second, delay.String())
+	}
+}
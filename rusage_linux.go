@@ -0,0 +1,18 @@
+//go:build linux
+// +build linux
+
+package docradle
+
+import (
+	"os"
+	"syscall"
+)
+
+// peakMemory returns the max resident set size of the exited command in bytes
+func peakMemory(state *os.ProcessState) uint64 {
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		// ru_maxrss is in kilobytes on Linux
+		return uint64(usage.Maxrss) * 1024
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package docradle
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// forwardedSignals are signals that docradle receives and forwards to the command like tini
+//
+// Signals for job control and synchronous errors (like SIGSEGV) are not forwarded.
+var forwardedSignals = []os.Signal{
+	syscall.SIGHUP,
+	syscall.SIGINT,
+	syscall.SIGQUIT,
+	syscall.SIGTERM,
+	syscall.SIGUSR1,
+	syscall.SIGUSR2,
+	syscall.SIGWINCH,
+	syscall.SIGALRM,
+	syscall.SIGCONT,
+}
+
+var signalNames = map[string]syscall.Signal{
+	"HUP":   syscall.SIGHUP,
+	"INT":   syscall.SIGINT,
+	"QUIT":  syscall.SIGQUIT,
+	"KILL":  syscall.SIGKILL,
+	"TERM":  syscall.SIGTERM,
+	"USR1":  syscall.SIGUSR1,
+	"USR2":  syscall.SIGUSR2,
+	"WINCH": syscall.SIGWINCH,
+	"ALRM":  syscall.SIGALRM,
+	"CONT":  syscall.SIGCONT,
+	"STOP":  syscall.SIGSTOP,
+}
+
+// parseSignal parses signal name like "SIGTERM", "TERM" or number like "15"
+func parseSignal(name string) (syscall.Signal, error) {
+	if number, err := strconv.Atoi(name); err == nil && number > 0 && number < 65 {
+		return syscall.Signal(number), nil
+	}
+	if sig, ok := signalNames[strings.TrimPrefix(strings.ToUpper(name), "SIG")]; ok {
+		return sig, nil
+	}
+	return 0, fmt.Errorf("unknown signal '%s'", name)
+}
+
+// signalName returns the name like "SIGTERM", or the description for signals that don't have a name in signalNames
+func signalName(sig syscall.Signal) string {
+	for name, s := range signalNames {
+		if s == sig {
+			return "SIG" + name
+		}
+	}
+	return sig.String()
+}
+
+// isStopSignal returns true if the signal asks docradle to stop the command
+func isStopSignal(sig os.Signal) bool {
+	return sig == syscall.SIGINT || sig == syscall.SIGTERM
+}
+
+// rewriteSignal converts the received signal by process.signalRewrite option
+func rewriteSignal(rewrite map[syscall.Signal]syscall.Signal, sig os.Signal) os.Signal {
+	if s, ok := sig.(syscall.Signal); ok {
+		if rewritten, ok := rewrite[s]; ok {
+			return rewritten
+		}
+	}
+	return sig
+}
+
+// signalProcessGroup sends the signal to the process group of the command
+//
+// The command runs as a leader of new process group, so the group id is the same as its pid.
+// If the group doesn't exist anymore, the signal is sent to the command itself.
+func signalProcessGroup(process *os.Process, sig os.Signal) error {
+	if s, ok := sig.(syscall.Signal); ok {
+		if err := syscall.Kill(-process.Pid, s); err != syscall.ESRCH {
+			return err
+		}
+	}
+	return process.Signal(sig)
+}
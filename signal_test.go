@@ -0,0 +1,50 @@
+package docradle
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSignal(t *testing.T) {
+	testcases := []struct {
+		name   string
+		signal syscall.Signal
+		ok     bool
+	}{
+		{name: "SIGTERM", signal: syscall.SIGTERM, ok: true},
+		{name: "TERM", signal: syscall.SIGTERM, ok: true},
+		{name: "quit", signal: syscall.SIGQUIT, ok: true},
+		{name: "SIGWINCH", signal: syscall.SIGWINCH, ok: true},
+		{name: "10", signal: syscall.Signal(10), ok: true},
+		{name: "SIGUNKNOWN", ok: false},
+		{name: "0", ok: false},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			sig, err := parseSignal(tt.name)
+			if tt.ok {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.signal, sig)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestSignalName(t *testing.T) {
+	assert.Equal(t, "SIGTERM", signalName(syscall.SIGTERM))
+	assert.Equal(t, "SIGKILL", signalName(syscall.SIGKILL))
+	assert.Equal(t, "segmentation fault", signalName(syscall.SIGSEGV))
+}
+
+func TestRewriteSignal(t *testing.T) {
+	rewrite := map[syscall.Signal]syscall.Signal{
+		syscall.SIGTERM: syscall.SIGQUIT,
+	}
+	assert.Equal(t, syscall.SIGQUIT, rewriteSignal(rewrite, syscall.SIGTERM))
+	assert.Equal(t, syscall.SIGHUP, rewriteSignal(rewrite, syscall.SIGHUP))
+	assert.Equal(t, syscall.SIGHUP, rewriteSignal(nil, syscall.SIGHUP))
+}
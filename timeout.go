@@ -0,0 +1,63 @@
+package docradle
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// outputActivity is the last time when the command writes output. process.watchdog watches it.
+type outputActivity struct {
+	last int64 // unix nano
+}
+
+func newOutputActivity() *outputActivity {
+	a := &outputActivity{}
+	a.touch()
+	return a
+}
+
+func (a *outputActivity) touch() {
+	atomic.StoreInt64(&a.last, time.Now().UnixNano())
+}
+
+func (a *outputActivity) idle() time.Duration {
+	return time.Since(time.Unix(0, atomic.LoadInt64(&a.last)))
+}
+
+// maxWatchdogInterval is the max interval to check output for process.watchdog
+const maxWatchdogInterval = time.Second
+
+// waitTimeout waits until process.timeout passes from startAt, or the command doesn't write output for process.watchdog
+//
+// It returns the reason ("timeout" or "watchdog") and the limit. The reason is empty if ctx is done before them.
+func waitTimeout(ctx context.Context, process Process, startAt time.Time, activity *outputActivity) (string, time.Duration) {
+	var deadline <-chan time.Time
+	if process.Timeout > 0 {
+		timer := time.NewTimer(process.Timeout - time.Since(startAt))
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	var check <-chan time.Time
+	if process.Watchdog > 0 {
+		interval := process.Watchdog / 10
+		if interval > maxWatchdogInterval {
+			interval = maxWatchdogInterval
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		check = ticker.C
+	}
+	for {
+		select {
+		case <-deadline:
+			return "timeout", process.Timeout
+		case <-check:
+			if activity.idle() >= process.Watchdog {
+				return "watchdog", process.Watchdog
+			}
+		case <-ctx.Done():
+			return "", 0
+		}
+	}
+}
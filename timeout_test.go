@@ -0,0 +1,123 @@
+package docradle
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitTimeout(t *testing.T) {
+	testcases := []struct {
+		name    string
+		process Process
+		touch   bool
+		reason  string
+		limit   time.Duration
+	}{
+		{
+			name:    "timeout",
+			process: Process{Timeout: 100 * time.Millisecond},
+			reason:  "timeout",
+			limit:   100 * time.Millisecond,
+		},
+		{
+			name:    "watchdog",
+			process: Process{Watchdog: 100 * time.Millisecond},
+			reason:  "watchdog",
+			limit:   100 * time.Millisecond,
+		},
+		{
+			name:    "output keeps watchdog away",
+			process: Process{Timeout: 300 * time.Millisecond, Watchdog: 100 * time.Millisecond},
+			touch:   true,
+			reason:  "timeout",
+			limit:   300 * time.Millisecond,
+		},
+		{
+			name:    "command exits",
+			process: Process{Timeout: time.Minute},
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			activity := newOutputActivity()
+			if tt.touch {
+				go func() {
+					for ctx.Err() == nil {
+						activity.touch()
+						time.Sleep(10 * time.Millisecond)
+					}
+				}()
+			}
+			reason, limit := waitTimeout(ctx, tt.process, time.Now(), activity)
+			assert.Equal(t, tt.reason, reason)
+			assert.Equal(t, tt.limit, limit)
+		})
+	}
+}
+
+func TestExec_Timeout(t *testing.T) {
+	testcases := []struct {
+		name        string
+		process     string
+		script      string
+		exitCode    int
+		reason      string
+		termination string
+	}{
+		{
+			name:        "timeout stops the command gracefully",
+			process:     `{"timeout": 0.3}`,
+			script:      "echo started; sleep 10",
+			exitCode:    ExitCodeTimeout,
+			reason:      "timeout",
+			termination: "graceful",
+		},
+		{
+			name:        "restarts don't reset timeout",
+			process:     `{"timeout": 0.5, "restart": "always", "restartBackoff": {"initial": 0.05}}`,
+			script:      "echo started; sleep 0.2; exit 1",
+			exitCode:    ExitCodeTimeout,
+			reason:      "timeout",
+			termination: "graceful",
+		},
+		{
+			name:        "watchdog kills the command",
+			process:     `{"watchdog": 0.3}`,
+			script:      "trap '' TERM; echo started; sleep 10",
+			exitCode:    ExitCodeTimeout,
+			reason:      "watchdog",
+			termination: "killed",
+		},
+		{
+			name:        "command that writes output",
+			process:     `{"timeout": 5, "watchdog": 0.3}`,
+			script:      "for i in 1 2 3 4 5 6; do echo $i; sleep 0.1; done",
+			termination: "exited",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ReadConfig("config.json", strings.NewReader(`{"process": `+tt.process+`}`))
+			assert.NoError(t, err)
+
+			stdout := &syncBuffer{}
+			start := time.Now()
+			err = Exec(stdout, &syncBuffer{}, config, "sh", []string{"-c", tt.script}, NewEnvVar())
+			assert.Equal(t, tt.exitCode, ExitCode(err))
+			assert.True(t, time.Since(start) < 5*time.Second, "the command should be stopped")
+			output := stdout.String()
+			if tt.reason != "" {
+				assert.Contains(t, output, `"docradle-log":"timeout","reason":"`+tt.reason+`"`)
+			} else {
+				assert.NotContains(t, output, `"docradle-log":"timeout"`)
+			}
+			assert.Contains(t, output, `"termination":"`+tt.termination+`"`)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package docradle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gookit/color"
+)
+
+// Wait waits for dependencies specified by URLs without config file
+//
+// It is the implementation of "docradle wait" command. URLs can contain envvars.
+func Wait(stdout, stderr io.Writer, urls []string, timeout, interval time.Duration) error {
+	envvars := NewEnvVar()
+	envvars.Import(fromOsEnv, os.Environ())
+	dependsOns := make([]DependsOn, len(urls))
+	for i, rawURL := range urls {
+		u, err := url.Parse(envvars.Expand(rawURL))
+		if err != nil {
+			color.Fprintf(stderr, "<red>URL '%s' is invalid: %s</>\n", rawURL, err.Error())
+			return fmt.Errorf("URL '%s' is invalid: %w", rawURL, err)
+		}
+		dependsOns[i] = DependsOn{
+			URL:      u,
+			Timeout:  timeout,
+			Interval: interval,
+		}
+	}
+	results, sig := waitForDependenciesWithSignal(dependsOns, 0, envvars, stdout)
+	if sig != nil {
+		color.Fprintf(stderr, "\n<yellow>Waiting for dependencies is canceled by signal: %s</>\n", sig)
+		return &CanceledError{Signal: sig}
+	}
+	color.Fprintln(stdout, "<bg=black;fg=lightBlue;op=reverse;>  Dependencies  </>\n")
+	hasError := false
+	for _, result := range results {
+		color.Fprintln(stdout, result.String())
+		if result.Error() != nil {
+			hasError = true
+		}
+	}
+	if hasError {
+		return ErrDependencyNotReady
+	}
+	return nil
+}
+
+// waitForDependenciesWithSignal waits for dependencies until SIGINT or SIGTERM arrives
+//
+// If dependsOnTimeout is not zero, it limits the whole waiting time. It returns the signal if waiting is canceled by it.
+func waitForDependenciesWithSignal(dependsOns []DependsOn, dependsOnTimeout time.Duration, envvars *EnvVar, stdout io.Writer) ([]DependsOnCheckResult, os.Signal) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	ctx, cancel, received := signalContext(context.Background(), sigs)
+	defer cancel()
+	if dependsOnTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, dependsOnTimeout)
+		defer cancel()
+	}
+	results := WaitForDependencies(ctx, dependsOns, envvars, NewDependsOnProgress(stdout))
+	return results, received()
+}
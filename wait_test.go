@@ -0,0 +1,90 @@
+package docradle
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWait(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	closedAddress := closed.Addr().String()
+	closed.Close()
+
+	os.Setenv("DOCRADLE_TEST_ADDRESS", listener.Addr().String())
+	defer os.Unsetenv("DOCRADLE_TEST_ADDRESS")
+
+	testcases := []struct {
+		name string
+		urls []string
+		err  error
+	}{
+		{
+			name: "ok",
+			urls: []string{"tcp://${DOCRADLE_TEST_ADDRESS}"},
+		},
+		{
+			name: "ng",
+			urls: []string{"tcp://${DOCRADLE_TEST_ADDRESS}", "tcp://" + closedAddress},
+			err:  ErrDependencyNotReady,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Wait(ioutil.Discard, ioutil.Discard, tt.urls, 100*time.Millisecond, 10*time.Millisecond)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tt.err))
+			}
+		})
+	}
+}
+
+func TestWaitForDependenciesWithSignal(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	dependsOns := []DependsOn{
+		{
+			URL:      mustUrlParse(t, "tcp://"+address),
+			Timeout:  10 * time.Second,
+			Interval: 10 * time.Millisecond,
+		},
+	}
+
+	t.Run("dependsOnTimeout", func(t *testing.T) {
+		start := time.Now()
+		results, sig := waitForDependenciesWithSignal(dependsOns, 100*time.Millisecond, NewEnvVar(), ioutil.Discard)
+		assert.True(t, time.Now().Sub(start) < time.Second)
+		assert.Nil(t, sig)
+		assert.Len(t, results, 1)
+		assert.True(t, errors.Is(results[0].Error(), context.DeadlineExceeded))
+	})
+
+	t.Run("signal", func(t *testing.T) {
+		timer := time.AfterFunc(100*time.Millisecond, func() {
+			syscall.Kill(os.Getpid(), syscall.SIGTERM)
+		})
+		defer timer.Stop()
+		start := time.Now()
+		results, sig := waitForDependenciesWithSignal(dependsOns, 0, NewEnvVar(), ioutil.Discard)
+		assert.True(t, time.Now().Sub(start) < time.Second)
+		assert.Equal(t, syscall.SIGTERM, sig)
+		assert.Len(t, results, 1)
+		assert.True(t, errors.Is(results[0].Error(), context.Canceled))
+	})
+}